@@ -0,0 +1,129 @@
+package retrier
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"time"
+)
+
+// ErrAttemptAbandoned is the error of an attempt abandoned after the duration set through WithAbandonAfter.
+var ErrAttemptAbandoned = errors.New("attempt abandoned")
+
+// abandonedRunning counts the abandoned attempts whose operation has not returned yet.
+var abandonedRunning atomic.Int64
+
+// AbandonedAttempts returns the number of attempts, across every retry sequence, abandoned after the
+// duration set through WithAbandonAfter, or left running once their retry sequence was canceled,
+// whose operation has not returned yet, i.e., the number of goroutines currently leaked by operations
+// ignoring cancellation.
+//
+// Returns:
+//   - running: The number of abandoned attempts still running.
+func AbandonedAttempts() (running int64) {
+	running = abandonedRunning.Load()
+
+	return
+}
+
+// executeWithin runs a single attempt of the operation through the middleware chain in its own
+// goroutine, and leaves it running if it does not return within the timeout or before ctx is done. The
+// attempt gets a chain and an Attempt of its own, so that an abandoned attempt cannot race with the
+// following ones, and a context of its own, canceled with ErrAttemptAbandoned as its cause once the
+// attempt is abandoned.
+//
+// Parameters:
+//   - ctx:         The context of the retry sequence.
+//   - clock:       The clock timing the attempt.
+//   - timeout:     The duration after which the attempt is abandoned.
+//   - middlewares: The middlewares wrapping the operation, outermost first.
+//   - operation:   The operation to execute.
+//   - sequenceID:  The unique identifier of the retry sequence.
+//   - number:      The zero-based number of the attempt within the retry sequence.
+//
+// Returns:
+//   - result:    The result of the operation, or the zero value if it was abandoned or not called.
+//   - called:    Whether the operation was called by the middleware chain.
+//   - abandoned: Whether the attempt was abandoned after the timeout. An attempt left running once ctx
+//     is done is not, as the retry sequence itself was canceled.
+//   - err:       The error returned by the middleware chain, ErrAttemptAbandoned if the attempt was
+//     abandoned after the timeout, or the context's error if ctx is done.
+func executeWithin[T any](ctx context.Context, clock Clock, timeout time.Duration, middlewares []Middleware, operation OperationWithContextAndData[T], sequenceID string, number int) (result T, called, abandoned bool, err error) {
+	type outcome struct {
+		result T
+		called bool
+		err    error
+	}
+
+	const (
+		running int32 = iota
+		returned
+		left
+	)
+
+	var state atomic.Int32
+
+	// The channel is buffered so that an abandoned attempt does not block once it returns.
+	done := make(chan outcome, 1)
+
+	attemptCtx, cancel := context.WithCancelCause(ctx)
+
+	defer func() {
+		if abandoned {
+			cancel(ErrAttemptAbandoned)
+		} else {
+			cancel(nil)
+		}
+	}()
+
+	go func() {
+		var o outcome
+
+		o.result, o.called, o.err = newChain(&Attempt{SequenceID: sequenceID}, middlewares, func() (T, error) {
+			return operation(attemptCtx)
+		}).execute(number)
+
+		if !state.CompareAndSwap(running, returned) {
+			abandonedRunning.Add(-1)
+		}
+
+		done <- o
+	}()
+
+	timer := clock.NewTimer(timeout)
+
+	defer timer.Stop()
+
+	var (
+		o        outcome
+		timedOut bool
+	)
+
+	select {
+	case o = <-done:
+	case <-timer.C():
+		err, timedOut = ErrAttemptAbandoned, true
+	case <-ctx.Done():
+		err = contextError(ctx)
+	}
+
+	if err != nil {
+		// Leave the attempt running, unless it returned in the meantime. The count is raised
+		// beforehand, so that the goroutine of the left attempt never lowers it below zero.
+		abandonedRunning.Add(1)
+
+		if state.CompareAndSwap(running, left) {
+			abandoned = timedOut
+
+			return
+		}
+
+		abandonedRunning.Add(-1)
+
+		o, err = <-done, nil
+	}
+
+	result, called, err = o.result, o.called, o.err
+
+	return
+}
@@ -0,0 +1,45 @@
+package retrier
+
+import (
+	"context"
+	"errors"
+)
+
+// ErrNotAcquired is the error of an attempt of UntilAcquired finding the lock or lease held elsewhere.
+var ErrNotAcquired = errors.New("not acquired")
+
+// UntilAcquired retries acquiring a lock or a lease, e.g., a state lock contended by several
+// processes, with the backoff, jitter, and give-up semantics of the provided options. Both contention
+// and errors of tryAcquire are retried.
+//
+// Parameters:
+//   - ctx:        A context to control the lifetime of the acquisition. It is passed to tryAcquire.
+//   - tryAcquire: The function trying to acquire the lock once. It returns the function releasing the
+//     lock and true if it acquired it, false if the lock is held elsewhere, or an error.
+//   - opts:       Optional configuration options.
+//
+// Returns:
+//   - release: The function releasing the lock, returned by the successful attempt.
+//   - err:     ErrNotAcquired if the lock was still held elsewhere at the last attempt, the error of the
+//     last attempt, or the context's error.
+//
+// Example:
+//
+//	release, err := retrier.UntilAcquired(ctx, lock.TryLock, retrier.WithMaxRetries(10))
+//	if err != nil {
+//	    return err
+//	}
+//
+//	defer release()
+func UntilAcquired(ctx context.Context, tryAcquire func(ctx context.Context) (release func(), ok bool, err error), opts ...Option) (release func(), err error) {
+	release, err = RetryWithData(ctx, func() (release func(), err error) {
+		release, ok, err := tryAcquire(ctx)
+		if err == nil && !ok {
+			err = ErrNotAcquired
+		}
+
+		return
+	}, opts...)
+
+	return
+}
@@ -0,0 +1,77 @@
+package retrier
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+)
+
+// Error is the error of a retry sequence that gave up with WithErrorAggregation, joining the errors of
+// its attempts, so that intermittent failures of earlier attempts are not hidden by the last one.
+//
+// Fields:
+//   - Attempts:   The number of attempts of the retry sequence.
+//   - Elapsed:    The time the retry sequence took.
+//   - Err:        The errors of the attempts, retained according to WithErrorRetention, joined with
+//     errors.Join in attempt order, ending with the error the retry sequence gave up with.
+//   - SequenceID: The unique identifier of the retry sequence, as reported to its notifiers and in its
+//     Stats.
+type Error struct {
+	Attempts   int
+	Elapsed    time.Duration
+	Err        error
+	SequenceID string
+}
+
+// Error implements the error interface.
+//
+// Returns:
+//   - message: The identifier of the retry sequence, if any, the number of attempts and the elapsed
+//     time, followed by the joined errors.
+func (e *Error) Error() (message string) {
+	message = fmt.Sprintf("%d attempts failed in %s: %s", e.Attempts, e.Elapsed, e.Err)
+
+	if e.SequenceID != "" {
+		message = "retry sequence " + e.SequenceID + ": " + message
+	}
+
+	return
+}
+
+// Unwrap returns the joined errors of the attempts, so that errors.Is and errors.As match any of them.
+//
+// Returns:
+//   - err: The joined errors.
+func (e *Error) Unwrap() (err error) {
+	err = e.Err
+
+	return
+}
+
+// aggregate joins the errors of the attempts of a retry sequence with the error it gave up with.
+//
+// Parameters:
+//   - err:        The error the retry sequence gave up with.
+//   - retained:   The retained errors of the attempts, in attempt order.
+//   - attempts:   The number of attempts of the retry sequence.
+//   - elapsed:    The time the retry sequence took.
+//   - sequenceID: The identifier of the retry sequence.
+//
+// Returns:
+//   - aggregated: The *Error joining the errors.
+func aggregate(err error, retained []error, attempts int, elapsed time.Duration, sequenceID string) (aggregated *Error) {
+	errs := slices.Clone(retained)
+
+	// The error given up with usually is the error of the last attempt, possibly wrapped or with its
+	// Permanent marker removed, which replaces it rather than repeating it.
+	if n := len(errs); n > 0 && (errors.Is(err, errs[n-1]) || errors.Is(errs[n-1], err)) {
+		errs[n-1] = err
+	} else {
+		errs = append(errs, err)
+	}
+
+	aggregated = &Error{Attempts: attempts, Elapsed: elapsed, Err: errors.Join(errs...), SequenceID: sequenceID}
+
+	return
+}
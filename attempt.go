@@ -0,0 +1,129 @@
+package retrier
+
+import (
+	"maps"
+	"sync"
+)
+
+// Attempt represents a single execution of an operation within a retry sequence. It carries the
+// attempt number and a scoped key-value store shared by every middleware of the same attempt, so
+// that, for example, a request ID generated by one middleware can be logged by another. The stored
+// values are cleared when the attempt ends.
+//
+// To keep the hot path free of allocations, the same Attempt is reused for every attempt of a retry
+// sequence and returned to a pool once the sequence ends. Middlewares must therefore not retain it
+// beyond the attempt; use Copy to keep a snapshot.
+//
+// Fields:
+//   - Number: The zero-based number of the attempt within the retry sequence.
+//   - SequenceID: The unique identifier of the retry sequence, a time-ordered UUID shared by its attempts
+//     that correlates the logs of one logical retry sequence across systems.
+type Attempt struct {
+	Number     int
+	SequenceID string
+
+	mutex  sync.Mutex
+	values map[any]any
+}
+
+// Set stores a value under the given key for the remainder of the attempt. Keys should be of an
+// unexported type to avoid collisions between packages, as with context keys.
+//
+// Parameters:
+//   - key:   The key under which the value is stored.
+//   - value: The value to store.
+func (a *Attempt) Set(key, value any) {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+
+	if a.values == nil {
+		a.values = make(map[any]any)
+	}
+
+	a.values[key] = value
+}
+
+// Get returns the value stored under the given key during the attempt.
+//
+// Parameters:
+//   - key: The key under which the value was stored.
+//
+// Returns:
+//   - value: The stored value, or nil if no value is stored under key.
+//   - ok:    Whether a value is stored under key.
+func (a *Attempt) Get(key any) (value any, ok bool) {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+
+	value, ok = a.values[key]
+
+	return
+}
+
+// Copy returns a snapshot of the attempt, detached from the retry sequence, that is safe to retain
+// after the attempt ends.
+//
+// Returns:
+//   - snapshot: A new Attempt with the same number, sequence ID, and a copy of the stored values.
+func (a *Attempt) Copy() (snapshot *Attempt) {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+
+	snapshot = &Attempt{Number: a.Number, SequenceID: a.SequenceID}
+
+	if len(a.values) > 0 {
+		snapshot.values = maps.Clone(a.values)
+	}
+
+	return
+}
+
+// end clears the values stored during the attempt.
+func (a *Attempt) end() {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+
+	clear(a.values)
+}
+
+// attempts pools the Attempt values reused by retry sequences.
+var attempts = sync.Pool{
+	New: func() any {
+		return new(Attempt)
+	},
+}
+
+// acquireAttempt returns a cleared Attempt from the pool.
+func acquireAttempt() (attempt *Attempt) {
+	attempt, _ = attempts.Get().(*Attempt)
+
+	return
+}
+
+// releaseAttempt clears the attempt and returns it to the pool.
+func releaseAttempt(attempt *Attempt) {
+	attempt.end()
+
+	attempt.Number, attempt.SequenceID = 0, ""
+
+	attempts.Put(attempt)
+}
+
+// Middleware is a function type that wraps the execution of every attempt. A middleware receives the
+// current Attempt, whose scoped values are shared with the other middlewares, and the next step of the
+// chain, which it must call for the operation to be executed.
+//
+// Parameters:
+//   - attempt: The Attempt being executed.
+//   - next:    The next middleware in the chain, or the operation itself.
+//
+// Returns:
+//   - err: The error of the attempt, usually the one returned by next.
+//
+// Example:
+//
+//	func requestID(attempt *retrier.Attempt, next retrier.Operation) error {
+//	    attempt.Set(requestIDKey{}, uuid.NewString())
+//	    return next()
+//	}
+type Middleware func(attempt *Attempt, next Operation) (err error)
@@ -0,0 +1,77 @@
+package retrier
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// AuditFormat is the format of the records written by WithAuditWriter.
+type AuditFormat int
+
+const (
+	// AuditFormatJSONL writes every record as a JSON object on its own line. This is the default.
+	AuditFormatJSONL AuditFormat = iota
+	// AuditFormatLogfmt writes every record as a line of key=value pairs.
+	AuditFormatLogfmt
+)
+
+// AuditRecord is the audit record of an attempt, written by WithAuditWriter.
+//
+// Fields:
+//   - Time: The time the attempt started.
+//   - SequenceID: The identifier of the retry sequence of the attempt.
+//   - Attempt: The zero-based number of the attempt.
+//   - Outcome: The name of the Class of the outcome of the attempt.
+//   - Error: The message of the error of the attempt, or empty if it succeeded.
+//   - Delay: The delay before the next attempt, or 0 if the retry sequence ended.
+type AuditRecord struct {
+	Time       time.Time     `json:"time"`
+	SequenceID string        `json:"sequence_id"`
+	Attempt    int           `json:"attempt"`
+	Outcome    string        `json:"outcome"`
+	Error      string        `json:"error,omitempty"`
+	Delay      time.Duration `json:"delay"`
+}
+
+// auditLog writes the audit records of the retry sequences sharing a Configuration, one line per
+// record, serializing the writes so that records of concurrent sequences never interleave.
+type auditLog struct {
+	mutex  sync.Mutex
+	writer io.Writer
+	format AuditFormat
+}
+
+// write appends a record to the audit log.
+//
+// Parameters:
+//   - record: The record to write.
+//
+// Returns:
+//   - err: The error of the writer, if any.
+func (l *auditLog) write(record AuditRecord) (err error) {
+	var line []byte
+
+	switch l.format {
+	case AuditFormatLogfmt:
+		line = fmt.Appendf(nil, "time=%s sequence_id=%s attempt=%d outcome=%s error=%s delay=%s\n",
+			record.Time.Format(time.RFC3339Nano), record.SequenceID, record.Attempt, record.Outcome,
+			strconv.Quote(record.Error), record.Delay)
+	default:
+		if line, err = json.Marshal(record); err != nil {
+			return
+		}
+
+		line = append(line, '\n')
+	}
+
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	_, err = l.writer.Write(line)
+
+	return
+}
@@ -0,0 +1,101 @@
+package backoff
+
+import (
+	"math"
+	"sync"
+	"time"
+)
+
+// autoTuneWeight is the weight of a new observation in the moving average of the recovery horizon.
+const autoTuneWeight = 0.2
+
+// AutoTuner is an experimental backoff strategy learning how long the dependency historically takes
+// to recover, i.e., the time from the first failure of a retry sequence to its first success, and
+// biasing its delays toward that horizon. It is safe for concurrent use; one AutoTuner should be
+// shared by the retry sequences calling the same dependency.
+type AutoTuner struct {
+	mutex   sync.Mutex
+	horizon time.Duration
+}
+
+// AutoTune returns an experimental AutoTuner. Until it observes a recovery, its delays are those of
+// Exponential.
+//
+// Returns:
+//   - tuner: The AutoTuner.
+//
+// Example:
+//
+//	tuner := backoff.AutoTune()
+//
+//	err := retrier.Retry(ctx, operation, retrier.WithAutoTune(tuner))
+func AutoTune() (tuner *AutoTuner) {
+	tuner = &AutoTuner{}
+
+	return
+}
+
+// Observe records the time a retry sequence took to recover, from its first failure to its first
+// success, in the exponentially weighted moving average of the recovery horizon.
+//
+// Parameters:
+//   - recovery: The time from the first failure to the first success.
+func (t *AutoTuner) Observe(recovery time.Duration) {
+	if recovery <= 0 {
+		return
+	}
+
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	if t.horizon == 0 {
+		t.horizon = recovery
+
+		return
+	}
+
+	t.horizon = time.Duration(float64(t.horizon) + autoTuneWeight*float64(recovery-t.horizon))
+}
+
+// Horizon returns the learned recovery horizon.
+//
+// Returns:
+//   - horizon: The moving average of the observed recovery times, or 0 if none was observed.
+func (t *AutoTuner) Horizon() (horizon time.Duration) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	horizon = t.horizon
+
+	return
+}
+
+// Backoff returns the backoff function of the AutoTuner. Once a recovery horizon is learned, the delay
+// is the geometric mean of the exponential delay and the horizon, so that early retries of a
+// dependency that recovers slowly wait longer, and late retries of one that recovers quickly wait
+// less, bounded by minDelay and maxDelay.
+//
+// Formula: delay = sqrt(minDelay * 2^attempt * horizon), bounded by minDelay and maxDelay
+//
+// Returns:
+//   - Backoff: The backoff function.
+func (t *AutoTuner) Backoff() Backoff {
+	return describe(func(minDelay, maxDelay time.Duration, attempt int) (backoff time.Duration) {
+		backoff = SafeShift(minDelay, attempt)
+
+		if horizon := t.Horizon(); horizon > 0 {
+			// Compare in floating point, as the mean may not be representable as a Duration.
+			mean := math.Sqrt(float64(backoff)) * math.Sqrt(float64(horizon))
+
+			backoff = maxDelay
+
+			if mean < float64(maxDelay) {
+				backoff = time.Duration(math.Round(mean))
+			}
+		}
+
+		backoff = min(max(backoff, minDelay), maxDelay)
+
+		return
+	}, StrategyInfo{Name: "autotune", Parameters: map[string]string{"weight": "0.2"}})
+}
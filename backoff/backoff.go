@@ -1,8 +1,6 @@
 package backoff
 
 import (
-	"math"
-	"sync"
 	"time"
 
 	"go.source.hueristiq.com/retrier/jitter"
@@ -40,16 +38,16 @@ type Backoff func(minDelay, maxDelay time.Duration, attempt int) (delay time.Dur
 //	backoffFunc := backoff.Exponential()
 //	delay := backoffFunc(1*time.Second, 30*time.Second, 3)
 //	// delay will be 8 seconds (1s * 2^3), but capped at maxDelay if exceeded.
-func Exponential() func(minDelay, maxDelay time.Duration, attempt int) (backoff time.Duration) {
-	return func(minDelay, maxDelay time.Duration, attempt int) (backoff time.Duration) {
-		backoff = time.Duration(math.Pow(2, float64(attempt)) * float64(minDelay))
+func Exponential() Backoff {
+	return describe(func(minDelay, maxDelay time.Duration, attempt int) (backoff time.Duration) {
+		backoff = SafeShift(minDelay, attempt)
 
 		if backoff > maxDelay {
 			backoff = maxDelay
 		}
 
 		return
-	}
+	}, StrategyInfo{Name: "exponential", Parameters: map[string]string{"multiplier": "2"}})
 }
 
 // ExponentialWithEqualJitter returns a backoff function that implements exponential backoff with equal jitter.
@@ -58,6 +56,8 @@ func Exponential() func(minDelay, maxDelay time.Duration, attempt int) (backoff
 //
 // Formula: delay = minDelay * 2^attempt + random(midpoint, delay)
 //
+// The optional jitter options, such as jitter.WithFloor, are forwarded to the jitter strategy.
+//
 // Parameters:
 //   - minDelay: The minimum backoff duration (base duration).
 //   - maxDelay: The maximum allowable backoff duration.
@@ -71,24 +71,28 @@ func Exponential() func(minDelay, maxDelay time.Duration, attempt int) (backoff
 //	backoffFunc := backoff.ExponentialWithEqualJitter()
 //	delay := backoffFunc(1*time.Second, 30*time.Second, 3)
 //	// delay will be exponentially calculated with equal jitter applied.
-func ExponentialWithEqualJitter() func(minDelay, maxDelay time.Duration, attempt int) (backoff time.Duration) {
-	mutex := &sync.Mutex{}
+func ExponentialWithEqualJitter(opts ...jitter.Option) Backoff {
+	j := jitter.NewEqual(opts...)
 
-	return func(minDelay, maxDelay time.Duration, attempt int) (backoff time.Duration) {
-		backoff = time.Duration(math.Pow(2, float64(attempt)) * float64(minDelay))
+	return annotate(func(minDelay, maxDelay time.Duration, attempt int) (backoff time.Duration) {
+		backoff = SafeShift(minDelay, attempt)
 
-		mutex.Lock()
-		jittered := jitter.Equal(backoff)
-		mutex.Unlock()
+		jittered := j.Apply(backoff)
 
-		backoff += jittered
+		backoff = SafeAdd(backoff, jittered)
 
 		if backoff > maxDelay {
 			backoff = maxDelay
 		}
 
 		return
-	}
+	}, &description{
+		info:     StrategyInfo{Name: "exponential", Parameters: map[string]string{"multiplier": "2", "jitter": jitter.Describe(j).String()}},
+		unjitter: stripsTo(Exponential),
+		base:     Exponential(),
+		jitter:   j,
+		additive: true,
+	})
 }
 
 // ExponentialWithFullJitter returns a backoff function that implements exponential backoff with full jitter.
@@ -97,6 +101,8 @@ func ExponentialWithEqualJitter() func(minDelay, maxDelay time.Duration, attempt
 //
 // Formula: delay = minDelay * 2^attempt + random(0, delay)
 //
+// The optional jitter options, such as jitter.WithFloor, are forwarded to the jitter strategy.
+//
 // Parameters:
 //   - minDelay: The minimum backoff duration (base duration).
 //   - maxDelay: The maximum allowable backoff duration.
@@ -110,24 +116,28 @@ func ExponentialWithEqualJitter() func(minDelay, maxDelay time.Duration, attempt
 //	backoffFunc := backoff.ExponentialWithFullJitter()
 //	delay := backoffFunc(1*time.Second, 30*time.Second, 3)
 //	// delay will be exponentially calculated with full jitter applied.
-func ExponentialWithFullJitter() func(minDelay, maxDelay time.Duration, attempt int) (backoff time.Duration) {
-	mutex := &sync.Mutex{}
+func ExponentialWithFullJitter(opts ...jitter.Option) Backoff {
+	j := jitter.NewFull(opts...)
 
-	return func(minDelay, maxDelay time.Duration, attempt int) (backoff time.Duration) {
-		backoff = time.Duration(math.Pow(2, float64(attempt)) * float64(minDelay))
+	return annotate(func(minDelay, maxDelay time.Duration, attempt int) (backoff time.Duration) {
+		backoff = SafeShift(minDelay, attempt)
 
-		mutex.Lock()
-		jittered := jitter.Full(backoff)
-		mutex.Unlock()
+		jittered := j.Apply(backoff)
 
-		backoff += jittered
+		backoff = SafeAdd(backoff, jittered)
 
 		if backoff > maxDelay {
 			backoff = maxDelay
 		}
 
 		return
-	}
+	}, &description{
+		info:     StrategyInfo{Name: "exponential", Parameters: map[string]string{"multiplier": "2", "jitter": jitter.Describe(j).String()}},
+		unjitter: stripsTo(Exponential),
+		base:     Exponential(),
+		jitter:   j,
+		additive: true,
+	})
 }
 
 // ExponentialWithDecorrelatedJitter returns a backoff function that implements exponential backoff
@@ -136,6 +146,12 @@ func ExponentialWithFullJitter() func(minDelay, maxDelay time.Duration, attempt
 //
 // Formula: delay = minDelay * 2^attempt + random(previous * 3, delay)
 //
+// The optional jitter options, such as jitter.WithFloor, are forwarded to the jitter strategy.
+//
+// As a Backoff cannot remember its previous delays, the previous delay is reconstructed as
+// minDelay * 2^(attempt-1) rather than the randomized delay actually returned, which weakens the
+// decorrelation. DecorrelatedJitter, a stateful Strategy, tracks the actual previous delay instead.
+//
 // Parameters:
 //   - minDelay: The minimum backoff duration (base duration).
 //   - maxDelay: The maximum allowable backoff duration.
@@ -149,24 +165,63 @@ func ExponentialWithFullJitter() func(minDelay, maxDelay time.Duration, attempt
 //	backoffFunc := backoff.ExponentialWithDecorrelatedJitter()
 //	delay := backoffFunc(1*time.Second, 30*time.Second, 3)
 //	// delay will be exponentially calculated with decorrelated jitter applied.
-func ExponentialWithDecorrelatedJitter() func(minDelay, maxDelay time.Duration, attempt int) (delay time.Duration) {
-	mutex := &sync.Mutex{}
+func ExponentialWithDecorrelatedJitter(opts ...jitter.Option) Backoff {
+	return annotate(func(minDelay, maxDelay time.Duration, attempt int) (backoff time.Duration) {
+		previous := SafeShift(minDelay, attempt-1)
 
-	return func(minDelay, maxDelay time.Duration, attempt int) (backoff time.Duration) {
-		previous := time.Duration(math.Pow(2, float64(attempt-1)) * float64(minDelay))
+		backoff = SafeShift(minDelay, attempt)
 
-		backoff = time.Duration(math.Pow(2, float64(attempt)) * float64(minDelay))
+		jittered := jitter.Decorrelated(minDelay, maxDelay, previous, opts...)
 
-		mutex.Lock()
-		jittered := jitter.Decorrelated(minDelay, maxDelay, previous)
-		mutex.Unlock()
+		backoff = SafeAdd(backoff, jittered)
+
+		if backoff > maxDelay {
+			backoff = maxDelay
+		}
+
+		return
+	}, &description{
+		info:     StrategyInfo{Name: "exponential", Parameters: map[string]string{"multiplier": "2", "jitter": "decorrelated"}},
+		unjitter: stripsTo(Exponential),
+	})
+}
+
+// ExponentialWithSymmetricJitter returns a backoff function that implements exponential backoff with
+// symmetric (plus/minus) jitter. In this strategy, the base delay increases exponentially, and the delay
+// is drawn uniformly around it, deviating by up to the given fraction in both directions.
+//
+// Formula: delay = random(base * (1 - fraction), base * (1 + fraction)), where base = minDelay * 2^attempt
+//
+// The optional jitter options, such as jitter.WithFloor, are forwarded to the jitter strategy.
+//
+// Parameters:
+//   - minDelay: The minimum backoff duration (base duration).
+//   - maxDelay: The maximum allowable backoff duration.
+//   - attempt:  The current retry attempt number.
+//
+// Returns:
+//   - delay: The calculated delay with symmetric jitter applied, capped at the maximum duration.
+//
+// Example:
+//
+//	backoffFunc := backoff.ExponentialWithSymmetricJitter(0.2)
+//	delay := backoffFunc(1*time.Second, 30*time.Second, 3)
+//	// delay will be somewhere between 6.4 and 9.6 seconds (8s +/- 20%).
+func ExponentialWithSymmetricJitter(fraction float64, opts ...jitter.Option) Backoff {
+	j := jitter.NewSymmetric(fraction, opts...)
 
-		backoff += jittered
+	return annotate(func(minDelay, maxDelay time.Duration, attempt int) (backoff time.Duration) {
+		backoff = j.Apply(SafeShift(minDelay, attempt))
 
 		if backoff > maxDelay {
 			backoff = maxDelay
 		}
 
 		return
-	}
+	}, &description{
+		info:     StrategyInfo{Name: "exponential", Parameters: map[string]string{"multiplier": "2", "jitter": jitter.Describe(j).String()}},
+		unjitter: stripsTo(Exponential),
+		base:     Exponential(),
+		jitter:   j,
+	})
 }
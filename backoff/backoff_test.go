@@ -1,11 +1,14 @@
 package backoff_test
 
 import (
+	"math"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"go.source.hueristiq.com/retrier/backoff"
+	"go.source.hueristiq.com/retrier/jitter"
 )
 
 func TestExponentialBackoff(t *testing.T) {
@@ -100,3 +103,461 @@ func TestExponentialWithDecorrelatedJitterBackoff(t *testing.T) {
 		assert.LessOrEqual(t, delay, tt.maxDelay, "Backoff delay should not exceed the maximum")
 	}
 }
+
+func TestExponentialWithFullJitterBackoff_SeededSource(t *testing.T) {
+	t.Parallel()
+
+	schedule := func() (delays []time.Duration) {
+		backoffFunc := backoff.ExponentialWithFullJitter(jitter.WithSource(jitter.SeededSource(42)))
+
+		for attempt := range 10 {
+			delays = append(delays, backoffFunc(100*time.Millisecond, 10*time.Second, attempt))
+		}
+
+		return
+	}
+
+	assert.Equal(t, schedule(), schedule(), "A seeded source should make the jittered schedule reproducible")
+}
+
+func TestExponentialBackoff_NoOverflow(t *testing.T) {
+	t.Parallel()
+
+	b := backoff.Exponential()
+
+	for _, attempt := range []int{62, 63, 64, 100, 1000} {
+		delay := b(time.Second, time.Hour, attempt)
+
+		assert.Equal(t, time.Hour, delay, "Backoff delay should be capped at the maximum for attempt %d", attempt)
+	}
+}
+
+func TestSafeMul(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		d        time.Duration
+		factor   float64
+		expected time.Duration
+	}{
+		{time.Second, 1.5, 1500 * time.Millisecond},
+		{time.Second, 0, 0},
+		{time.Second, math.NaN(), 0},
+		{math.MaxInt64, 2, math.MaxInt64},
+		{math.MaxInt64, -2, math.MinInt64},
+		{time.Second, math.Inf(1), math.MaxInt64},
+	}
+
+	for _, tt := range tests {
+		assert.Equal(t, tt.expected, backoff.SafeMul(tt.d, tt.factor), "Unexpected product of %d and %f", tt.d, tt.factor)
+	}
+}
+
+func TestSafeShift(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		d        time.Duration
+		n        int
+		expected time.Duration
+	}{
+		{time.Second, 0, time.Second},
+		{time.Second, 3, 8 * time.Second},
+		{time.Second, -1, 500 * time.Millisecond},
+		{time.Second, -64, 0},
+		{0, 100, 0},
+		{time.Second, 40, math.MaxInt64},
+		{time.Second, 63, math.MaxInt64},
+		{-time.Second, 40, math.MinInt64},
+	}
+
+	for _, tt := range tests {
+		assert.Equal(t, tt.expected, backoff.SafeShift(tt.d, tt.n), "Unexpected shift of %d by %d", tt.d, tt.n)
+	}
+}
+
+func TestSafeAdd(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		a, b     time.Duration
+		expected time.Duration
+	}{
+		{time.Second, time.Second, 2 * time.Second},
+		{math.MaxInt64, time.Second, math.MaxInt64},
+		{math.MinInt64, -time.Second, math.MinInt64},
+		{math.MaxInt64, -time.Second, math.MaxInt64 - time.Second},
+	}
+
+	for _, tt := range tests {
+		assert.Equal(t, tt.expected, backoff.SafeAdd(tt.a, tt.b), "Unexpected sum of %d and %d", tt.a, tt.b)
+	}
+}
+
+func TestBackoff_Describe(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		b        backoff.Backoff
+		expected string
+	}{
+		{backoff.Exponential(), "exponential(multiplier=2)"},
+		{backoff.ExponentialWithEqualJitter(), "exponential(jitter=equal, multiplier=2)"},
+		{backoff.ExponentialWithFullJitter(), "exponential(jitter=full, multiplier=2)"},
+		{backoff.ExponentialWithDecorrelatedJitter(), "exponential(jitter=decorrelated, multiplier=2)"},
+		{backoff.ExponentialWithSymmetricJitter(0.2), "exponential(jitter=symmetric(fraction=0.2), multiplier=2)"},
+		{backoff.ExponentialWithFullJitter(jitter.WithFloor(time.Second)), "exponential(jitter=full(floor=1s), multiplier=2)"},
+		{backoff.Linear(5 * time.Millisecond), "linear(increment=5ms)"},
+		{backoff.Linear(time.Second), "linear(increment=1s)"},
+		{backoff.Polynomial(3), "polynomial(exponent=3)"},
+		{backoff.WithJitter(backoff.Linear(0), jitter.NewFull()), "with-jitter(base=linear(increment=0s), jitter=full)"},
+		{backoff.WithCap(backoff.Exponential(), time.Second), "with-cap(base=exponential(multiplier=2), ceiling=1s)"},
+		{backoff.WithFloor(backoff.Polynomial(2), time.Second), "with-floor(base=polynomial(exponent=2), floor=1s)"},
+		{func(minDelay, _ time.Duration, _ int) time.Duration { return minDelay }, "custom"},
+		{nil, "none"},
+	}
+
+	for _, tt := range tests {
+		assert.Equal(t, tt.expected, tt.b.String(), "Unexpected strategy description")
+	}
+
+	info := backoff.ExponentialWithFullJitter().Describe()
+
+	assert.Equal(t, "exponential", info.Name, "Unexpected strategy name")
+	assert.Equal(t, "full", info.Parameters["jitter"], "Unexpected jitter parameter")
+}
+
+func TestExponentialWithSymmetricJitterBackoff(t *testing.T) {
+	t.Parallel()
+
+	b := backoff.ExponentialWithSymmetricJitter(0.2)
+
+	for range 100 {
+		delay := b(time.Second, 30*time.Second, 3)
+
+		assert.GreaterOrEqual(t, delay, 6400*time.Millisecond, "Backoff delay should be at least 80% of the exponential delay")
+		assert.LessOrEqual(t, delay, 9600*time.Millisecond, "Backoff delay should be at most 120% of the exponential delay")
+	}
+
+	assert.Equal(t, 30*time.Second, b(time.Second, 30*time.Second, 10), "Backoff delay should be capped at the maximum")
+}
+
+func TestExponentialWithResetOnIdleBackoff(t *testing.T) {
+	t.Parallel()
+
+	idle := 100 * time.Millisecond
+
+	b := backoff.ExponentialWithResetOnIdle(idle)
+
+	// The attempt number is ignored in favor of the internal failure counter.
+	for i, expected := range []time.Duration{time.Second, 2 * time.Second, 4 * time.Second, 8 * time.Second, 8 * time.Second} {
+		assert.Equal(t, expected, b(time.Second, 8*time.Second, 0), "Unexpected delay after %d failures", i)
+	}
+
+	time.Sleep(2*idle + idle/2)
+
+	assert.Equal(t, 4*time.Second, b(time.Second, 8*time.Second, 0), "Expected the counter to decay by one per idle period")
+	assert.Equal(t, "exponential-reset-on-idle(idle=100ms, multiplier=2)", b.String(), "Unexpected description")
+}
+
+func TestAutoTune(t *testing.T) {
+	t.Parallel()
+
+	tuner := backoff.AutoTune()
+	b := tuner.Backoff()
+
+	assert.Equal(t, 4*time.Second, b(time.Second, time.Minute, 2), "Expected exponential delays before any recovery is observed")
+
+	tuner.Observe(4 * time.Second)
+
+	assert.Equal(t, 2*time.Second, b(time.Second, time.Minute, 0), "Expected the delay to be biased toward the recovery horizon")
+	assert.Equal(t, 8*time.Second, b(time.Second, time.Minute, 4), "Expected late delays to be biased toward the recovery horizon")
+	assert.Equal(t, 5*time.Second, b(time.Second, 5*time.Second, 10), "Expected the delay to be capped at the maximum")
+
+	tuner.Observe(9 * time.Second)
+
+	assert.Equal(t, 5*time.Second, tuner.Horizon(), "Expected the horizon to be a moving average of the recoveries")
+	assert.Equal(t, "autotune(weight=0.2)", b.String(), "Unexpected description")
+}
+
+func TestBurstBackoff(t *testing.T) {
+	t.Parallel()
+
+	minDelay := 100 * time.Millisecond
+	maxDelay := 10 * time.Second
+
+	b := backoff.Burst(3, backoff.Exponential())
+
+	for attempt := range 3 {
+		assert.Zero(t, b(minDelay, maxDelay, attempt), "Expected no delay within the burst, attempt %d", attempt)
+	}
+
+	assert.Equal(t, minDelay, b(minDelay, maxDelay, 3), "Expected the backoff to start after the burst")
+	assert.Equal(t, 2*minDelay, b(minDelay, maxDelay, 4), "Expected the backoff to grow after the burst")
+
+	assert.Equal(t, minDelay, backoff.Burst(0, nil)(minDelay, maxDelay, 0), "Expected no burst for n = 0")
+	assert.Equal(t, minDelay, backoff.Burst(-1, nil)(minDelay, maxDelay, 0), "Expected no burst for a negative n")
+	assert.Equal(t, "burst(n=1, then=exponential(multiplier=2))", backoff.Burst(1, nil).String(), "Unexpected description")
+}
+
+func TestLinearBackoff(t *testing.T) {
+	t.Parallel()
+
+	minDelay := time.Second
+	maxDelay := 3 * time.Second
+
+	b := backoff.Linear(500 * time.Millisecond)
+
+	expected := []time.Duration{time.Second, 1500 * time.Millisecond, 2 * time.Second, 2500 * time.Millisecond, 3 * time.Second, 3 * time.Second}
+
+	for attempt, delay := range expected {
+		assert.Equal(t, delay, b(minDelay, maxDelay, attempt), "Unexpected delay for attempt %d", attempt)
+	}
+
+	assert.Equal(t, maxDelay, b(minDelay, maxDelay, math.MaxInt), "Expected the delay not to overflow")
+	assert.Equal(t, "linear(increment=500ms)", b.String(), "Unexpected description")
+
+	for range 100 {
+		delay := backoff.LinearWithEqualJitter(500*time.Millisecond)(minDelay, time.Minute, 2)
+
+		assert.GreaterOrEqual(t, delay, 3*time.Second, "Expected equal jitter to add at least half the delay")
+		assert.LessOrEqual(t, delay, 4*time.Second, "Expected equal jitter to add at most the delay")
+
+		delay = backoff.LinearWithFullJitter(500*time.Millisecond)(minDelay, time.Minute, 2)
+
+		assert.GreaterOrEqual(t, delay, 2*time.Second, "Expected full jitter not to shorten the delay")
+		assert.LessOrEqual(t, delay, 4*time.Second, "Expected full jitter to add at most the delay")
+	}
+}
+
+func TestPolynomialBackoff(t *testing.T) {
+	t.Parallel()
+
+	minDelay := time.Second
+	maxDelay := 30 * time.Second
+
+	b := backoff.Polynomial(2)
+
+	expected := []time.Duration{time.Second, 4 * time.Second, 9 * time.Second, 16 * time.Second, 25 * time.Second, 30 * time.Second}
+
+	for attempt, delay := range expected {
+		assert.Equal(t, delay, b(minDelay, maxDelay, attempt), "Unexpected delay for attempt %d", attempt)
+	}
+
+	assert.Equal(t, maxDelay, b(minDelay, maxDelay, math.MaxInt), "Expected the delay not to overflow")
+	assert.Equal(t, 4*time.Second, backoff.Polynomial(1)(minDelay, maxDelay, 3), "Expected an exponent of 1 to grow linearly")
+	assert.Equal(t, minDelay, backoff.Polynomial(-1)(minDelay, maxDelay, 3), "Expected a negative exponent to count as 0")
+	assert.Equal(t, "polynomial(exponent=2)", b.String(), "Unexpected description")
+}
+
+func TestScheduleBackoff(t *testing.T) {
+	t.Parallel()
+
+	durations := []time.Duration{time.Second, 5 * time.Second, 30 * time.Second, 2 * time.Minute}
+
+	b := backoff.Schedule(durations...)
+
+	durations[0] = time.Hour
+
+	expected := []time.Duration{time.Second, 5 * time.Second, 30 * time.Second, 2 * time.Minute, 2 * time.Minute}
+
+	for attempt, delay := range expected {
+		assert.Equal(t, delay, b(time.Millisecond, time.Millisecond, attempt), "Unexpected delay for attempt %d", attempt)
+	}
+
+	assert.Zero(t, backoff.Schedule()(time.Second, time.Minute, 0), "Expected no delay for an empty schedule")
+	assert.Equal(t, "schedule(durations=[1s 5s 30s 2m0s])", b.String(), "Unexpected description")
+}
+
+func TestBackoff_Compose(t *testing.T) {
+	t.Parallel()
+
+	minDelay := time.Second
+	maxDelay := 30 * time.Second
+
+	jittered := backoff.WithJitter(backoff.Linear(time.Second), jitter.NewFull())
+
+	for range 100 {
+		delay := jittered(minDelay, maxDelay, 3)
+
+		assert.GreaterOrEqual(t, delay, time.Duration(0), "Expected full jitter from 0")
+		assert.LessOrEqual(t, delay, 4*time.Second, "Expected full jitter up to the base delay")
+	}
+
+	assert.Equal(t, maxDelay, backoff.WithJitter(backoff.Schedule(time.Minute), jitter.NewSymmetric(0.5))(minDelay, maxDelay, 0), "Expected the jittered delay to be capped at maxDelay")
+	assert.Equal(t, 2*time.Second, backoff.WithJitter(backoff.Exponential(), nil)(minDelay, maxDelay, 1), "Expected a nil strategy to leave delays unjittered")
+	assert.Equal(t, 5*time.Second, backoff.WithCap(backoff.Exponential(), 5*time.Second)(minDelay, maxDelay, 4), "Expected the delay to be capped")
+	assert.Equal(t, 3*time.Second, backoff.WithFloor(backoff.Exponential(), 3*time.Second)(minDelay, maxDelay, 0), "Expected the delay to be floored")
+}
+
+func TestUnjittered(t *testing.T) {
+	t.Parallel()
+
+	unjittered, stripped := backoff.Unjittered(backoff.ExponentialWithFullJitter())
+
+	assert.True(t, stripped, "Expected the jitter to be stripped")
+	assert.Equal(t, 8*time.Second, unjittered(time.Second, 30*time.Second, 3), "Expected the unjittered exponential delay")
+
+	_, stripped = backoff.Unjittered(backoff.Exponential())
+
+	assert.False(t, stripped, "Expected a deterministic strategy to be returned as is")
+
+	tests := []struct {
+		b        backoff.Backoff
+		expected time.Duration
+	}{
+		{backoff.ExponentialWithSymmetricJitter(0.5, jitter.WithFloor(time.Second)), 8 * time.Second},
+		{backoff.ExponentialWithDecorrelatedJitter(), 8 * time.Second},
+		{backoff.LinearWithFullJitter(time.Second), 4 * time.Second},
+		{backoff.LinearWithEqualJitter(2 * time.Second), 7 * time.Second},
+		{backoff.WithJitter(backoff.Linear(0), jitter.NewFull()), time.Second},
+		{backoff.WithJitter(backoff.ExponentialWithFullJitter(), jitter.NewEqual()), 8 * time.Second},
+		{backoff.WithCap(backoff.ExponentialWithFullJitter(), 5*time.Second), 5 * time.Second},
+		{backoff.WithFloor(backoff.LinearWithFullJitter(0), 3*time.Second), 3 * time.Second},
+		{backoff.Burst(1, backoff.ExponentialWithEqualJitter()), 4 * time.Second},
+	}
+
+	for _, tt := range tests {
+		unjittered, stripped := backoff.Unjittered(tt.b)
+
+		assert.True(t, stripped, "Expected %s to be stripped", tt.b)
+
+		for range 10 {
+			assert.Equal(t, tt.expected, unjittered(time.Second, 30*time.Second, 3), "Expected the unjittered delay of %s", tt.b)
+		}
+	}
+
+	for _, b := range []backoff.Backoff{
+		backoff.WithCap(backoff.Exponential(), time.Second),
+		backoff.Burst(1, backoff.Linear(time.Second)),
+		func(minDelay, _ time.Duration, _ int) time.Duration { return minDelay },
+	} {
+		_, stripped = backoff.Unjittered(b)
+
+		assert.False(t, stripped, "Expected %s to be returned as is", b)
+	}
+
+	strategy, stripped := backoff.UnjitteredStrategy(backoff.DecorrelatedJitter(time.Second, time.Minute))
+
+	require.True(t, stripped, "Expected the decorrelated jitter strategy to be stripped")
+	assert.Equal(t, []time.Duration{time.Second, 2 * time.Second, 4 * time.Second}, []time.Duration{strategy.Next(), strategy.Next(), strategy.Next()}, "Expected the exponential delays")
+
+	unjittered, stripped = backoff.Unjittered(backoff.FromStrategy(backoff.DecorrelatedJitter(time.Second, time.Minute)))
+
+	require.True(t, stripped, "Expected a jittered stateful strategy to be stripped")
+	assert.Equal(t, time.Second, unjittered(0, 0, 0), "Expected the exponential delays")
+	assert.Equal(t, 2*time.Second, unjittered(0, 0, 1), "Expected the exponential delays")
+}
+
+func TestStrategy(t *testing.T) {
+	t.Parallel()
+
+	strategy := backoff.NewStrategy(backoff.Exponential(), time.Second, 30*time.Second)
+
+	assert.Equal(t, time.Second, strategy.Next(), "Expected the delay of attempt 0")
+	assert.Equal(t, 2*time.Second, strategy.Next(), "Expected the delay of attempt 1")
+
+	strategy.Reset()
+
+	assert.Equal(t, time.Second, strategy.Next(), "Expected the strategy to start over once reset")
+
+	adapted := backoff.FromStrategy(strategy)
+
+	assert.Equal(t, time.Second, adapted(0, 0, 0), "Expected attempt 0 to reset the strategy")
+	assert.Equal(t, 2*time.Second, adapted(0, 0, 1), "Expected the next delay of the strategy")
+	assert.Equal(t, "stateful", adapted.String(), "Expected the adapted strategy to be described")
+}
+
+// highestSource is a jitter.Source always drawing the highest value.
+type highestSource struct{}
+
+func (highestSource) Int64N(n int64) (random int64) {
+	random = n - 1
+
+	return
+}
+
+func TestDecorrelatedJitter(t *testing.T) {
+	t.Parallel()
+
+	minDelay := time.Second
+	maxDelay := time.Minute
+
+	strategy := backoff.DecorrelatedJitter(minDelay, maxDelay, jitter.WithSource(highestSource{}))
+
+	first := strategy.Next()
+
+	assert.Equal(t, minDelay+3*minDelay-1, first, "Expected the first delay to be drawn from the minimum delay")
+	assert.Equal(t, minDelay+3*first-1, strategy.Next(), "Expected the next delay to be drawn from the actual previous delay")
+
+	for range 5 {
+		assert.LessOrEqual(t, strategy.Next(), maxDelay, "Expected the delays to be capped at maxDelay")
+	}
+
+	strategy.Reset()
+
+	assert.Equal(t, first, strategy.Next(), "Expected the strategy to start over once reset")
+
+	random := backoff.DecorrelatedJitter(minDelay, maxDelay)
+
+	for range 100 {
+		delay := random.Next()
+
+		assert.GreaterOrEqual(t, delay, minDelay, "Expected the delays to be at least minDelay")
+		assert.LessOrEqual(t, delay, maxDelay, "Expected the delays to be at most maxDelay")
+	}
+}
+
+func TestRegistry(t *testing.T) {
+	t.Parallel()
+
+	exponential, ok := backoff.Get("exponential-full-jitter")
+
+	require.True(t, ok, "Expected the built-in strategies to be registered")
+	assert.Equal(t, "exponential", exponential.Describe().Name)
+
+	require.NoError(t, backoff.Register("test-steps", func() backoff.Backoff {
+		return backoff.Schedule(time.Second, 2*time.Second)
+	}))
+
+	steps, ok := backoff.Get("test-steps")
+
+	require.True(t, ok, "Expected a registered strategy to be resolved")
+	assert.Equal(t, 2*time.Second, steps(0, time.Minute, 1))
+	assert.Contains(t, backoff.Names(), "test-steps")
+
+	require.ErrorIs(t, backoff.Register("test-steps", backoff.Exponential), backoff.ErrAlreadyRegistered)
+	require.ErrorIs(t, backoff.Register("", backoff.Exponential), backoff.ErrInvalidRegistration)
+	require.ErrorIs(t, backoff.Register("test-nil", nil), backoff.ErrInvalidRegistration)
+
+	_, ok = backoff.Get("test-unknown")
+
+	assert.False(t, ok)
+}
+
+func TestJitterOf(t *testing.T) {
+	t.Parallel()
+
+	j := jitter.NewFull(jitter.WithFloor(time.Second))
+
+	base, strategy, additive, ok := backoff.JitterOf(backoff.WithJitter(backoff.Linear(0), j))
+
+	require.True(t, ok, "Expected the jitter strategy of WithJitter")
+	assert.Same(t, j, strategy, "Expected the jitter strategy instance composed")
+	assert.False(t, additive, "Expected WithJitter to replace the delay")
+	assert.Equal(t, 2*time.Second, base(2*time.Second, time.Minute, 3), "Expected the base strategy")
+
+	_, strategy, additive, ok = backoff.JitterOf(backoff.LinearWithEqualJitter(time.Second, jitter.WithFloor(time.Second)))
+
+	require.True(t, ok, "Expected the jitter strategy of the built-in jittered strategies")
+	assert.True(t, additive, "Expected the built-in jittered strategies to add the jitter")
+	assert.Equal(t, "equal(floor=1s)", jitter.Describe(strategy).String(), "Expected the jitter strategy with its options")
+
+	_, _, _, ok = backoff.JitterOf(backoff.ExponentialWithDecorrelatedJitter())
+
+	assert.False(t, ok, "Expected no jitter strategy for decorrelated jitter")
+
+	_, _, _, ok = backoff.JitterOf(backoff.Exponential())
+
+	assert.False(t, ok, "Expected no jitter strategy for an unjittered strategy")
+}
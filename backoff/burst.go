@@ -0,0 +1,45 @@
+package backoff
+
+import (
+	"strconv"
+	"time"
+)
+
+// Burst returns a backoff function allowing a burst of immediate retries before backing off, as
+// request paths often want to "try thrice fast, then back off": the first n retries are not delayed,
+// and the ones that follow are delegated to then, as if the backoff sequence started after the burst.
+//
+// Formula: delay = 0 for attempt < n, otherwise then(minDelay, maxDelay, attempt - n)
+//
+// Parameters:
+//   - n:    The number of immediate retries. A non-positive number disables the burst.
+//   - then: The backoff function taking over after the burst. A nil function stands for Exponential.
+//
+// Returns:
+//   - Backoff: The burst-then-backoff function.
+//
+// Example:
+//
+//	b := backoff.Burst(3, backoff.ExponentialWithFullJitter())
+//	delay := b(100*time.Millisecond, 10*time.Second, 4)
+//	// delay is the full-jitter delay of the second attempt after the burst.
+func Burst(n int, then Backoff) Backoff {
+	if then == nil {
+		then = Exponential()
+	}
+
+	return annotate(func(minDelay, maxDelay time.Duration, attempt int) (backoff time.Duration) {
+		if attempt < n {
+			return
+		}
+
+		backoff = then(minDelay, maxDelay, attempt-max(n, 0))
+
+		return
+	}, &description{
+		info: StrategyInfo{Name: "burst", Parameters: map[string]string{"n": strconv.Itoa(n), "then": then.String()}},
+		unjitter: rewrap(then, func(then Backoff) Backoff {
+			return Burst(n, then)
+		}),
+	})
+}
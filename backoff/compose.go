@@ -0,0 +1,107 @@
+package backoff
+
+import (
+	"time"
+
+	"go.source.hueristiq.com/retrier/jitter"
+)
+
+// WithJitter returns a backoff function applying a jitter strategy to the delays of another backoff
+// function, so that any base strategy can be mixed with any jitter, including user-provided ones. As
+// with the built-in jittered strategies, the jittered delay is capped at maxDelay.
+//
+// Formula: delay = min(j.Apply(b(minDelay, maxDelay, attempt)), maxDelay)
+//
+// Parameters:
+//   - b: The base backoff function.
+//   - j: The jitter strategy. A nil strategy leaves the delays unjittered.
+//
+// Returns:
+//   - Backoff: The jittered backoff function.
+//
+// Example:
+//
+//	backoffFunc := backoff.WithJitter(backoff.Linear(time.Second), jitter.NewFull())
+//	delay := backoffFunc(1*time.Second, 30*time.Second, 3)
+//	// delay will be somewhere between 0 and 4 seconds.
+func WithJitter(b Backoff, j jitter.Strategy) Backoff {
+	return annotate(func(minDelay, maxDelay time.Duration, attempt int) (backoff time.Duration) {
+		backoff = b(minDelay, maxDelay, attempt)
+
+		if j != nil {
+			backoff = min(j.Apply(backoff), maxDelay)
+		}
+
+		return
+	}, &description{
+		info: StrategyInfo{Name: "with-jitter", Parameters: map[string]string{"base": b.String(), "jitter": jitter.Describe(j).String()}},
+		unjitter: func() (unjittered Backoff, stripped bool) {
+			unjittered, stripped = Unjittered(b)
+
+			stripped = stripped || j != nil
+
+			return
+		},
+		base:   b,
+		jitter: j,
+	})
+}
+
+// WithCap returns a backoff function capping the delays of another backoff function at a fixed
+// maximum, regardless of the maxDelay it is called with.
+//
+// Formula: delay = min(b(minDelay, maxDelay, attempt), ceiling)
+//
+// Parameters:
+//   - b:       The base backoff function.
+//   - ceiling: The maximum delay.
+//
+// Returns:
+//   - Backoff: The capped backoff function.
+//
+// Example:
+//
+//	backoffFunc := backoff.WithCap(backoff.Exponential(), 5*time.Second)
+//	delay := backoffFunc(1*time.Second, 30*time.Second, 4)
+//	// delay will be 5 seconds instead of 16 seconds.
+func WithCap(b Backoff, ceiling time.Duration) Backoff {
+	return annotate(func(minDelay, maxDelay time.Duration, attempt int) (backoff time.Duration) {
+		backoff = min(b(minDelay, maxDelay, attempt), ceiling)
+
+		return
+	}, &description{
+		info: StrategyInfo{Name: "with-cap", Parameters: map[string]string{"base": b.String(), "ceiling": ceiling.String()}},
+		unjitter: rewrap(b, func(b Backoff) Backoff {
+			return WithCap(b, ceiling)
+		}),
+	})
+}
+
+// WithFloor returns a backoff function raising the delays of another backoff function to a fixed
+// minimum, e.g., to guarantee a recovery gap after a fully jittered delay close to zero.
+//
+// Formula: delay = max(b(minDelay, maxDelay, attempt), floor)
+//
+// Parameters:
+//   - b:     The base backoff function.
+//   - floor: The minimum delay.
+//
+// Returns:
+//   - Backoff: The floored backoff function.
+//
+// Example:
+//
+//	backoffFunc := backoff.WithFloor(backoff.ExponentialWithFullJitter(), 100*time.Millisecond)
+//	// delays will never be shorter than 100ms.
+func WithFloor(b Backoff, floor time.Duration) Backoff {
+	return annotate(func(minDelay, maxDelay time.Duration, attempt int) (backoff time.Duration) {
+		backoff = max(b(minDelay, maxDelay, attempt), floor)
+
+		return
+	}, &description{
+		info: StrategyInfo{Name: "with-floor", Parameters: map[string]string{"base": b.String(), "floor": floor.String()}},
+		unjitter: rewrap(b, func(b Backoff) Backoff {
+			return WithFloor(b, floor)
+		}),
+	})
+}
@@ -0,0 +1,70 @@
+package backoff
+
+import (
+	"time"
+
+	"go.source.hueristiq.com/retrier/jitter"
+)
+
+// decorrelatedJitter is the Strategy returned by DecorrelatedJitter.
+type decorrelatedJitter struct {
+	minDelay time.Duration
+	maxDelay time.Duration
+	opts     []jitter.Option
+	previous time.Duration
+}
+
+// DecorrelatedJitter returns a stateful Strategy implementing decorrelated jitter, where every delay
+// is drawn from a range set by the delay it actually returned before, rather than by one recomputed
+// from the attempt number, so that the delays of concurrent clients keep drifting apart. It remembers
+// its last delay until reset, and therefore serves one retry sequence at a time, e.g., created per
+// sequence by retrier.WithStrategy.
+//
+// Formula: delay = min(minDelay + random(0, previous * 3), maxDelay), with previous = minDelay initially
+// and once reset
+//
+// The optional jitter options, such as jitter.WithSource, are forwarded to jitter.Decorrelated.
+//
+// Parameters:
+//   - minDelay: The minimum backoff duration (base duration).
+//   - maxDelay: The maximum allowable backoff duration.
+//   - opts:     The jitter options.
+//
+// Returns:
+//   - strategy: The decorrelated jitter Strategy.
+//
+// Example:
+//
+//	retrier.WithStrategy(func(minDelay, maxDelay time.Duration) backoff.Strategy {
+//	    return backoff.DecorrelatedJitter(minDelay, maxDelay)
+//	})
+func DecorrelatedJitter(minDelay, maxDelay time.Duration, opts ...jitter.Option) (strategy Strategy) {
+	strategy = &decorrelatedJitter{minDelay: minDelay, maxDelay: maxDelay, opts: opts, previous: minDelay}
+
+	return
+}
+
+// Next implements Strategy.
+func (s *decorrelatedJitter) Next() (delay time.Duration) {
+	delay = jitter.Decorrelated(s.minDelay, s.maxDelay, s.previous, s.opts...)
+
+	s.previous = delay
+
+	return
+}
+
+// unjittered returns the deterministic counterpart of the strategy, following Exponential, as
+// ExponentialWithDecorrelatedJitter is stripped to Exponential.
+//
+// Returns:
+//   - strategy: The deterministic counterpart of the strategy.
+func (s *decorrelatedJitter) unjittered() (strategy Strategy) {
+	strategy = NewStrategy(Exponential(), s.minDelay, s.maxDelay)
+
+	return
+}
+
+// Reset implements Strategy.
+func (s *decorrelatedJitter) Reset() {
+	s.previous = s.minDelay
+}
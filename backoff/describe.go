@@ -0,0 +1,158 @@
+package backoff
+
+import (
+	"math"
+	"reflect"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"go.source.hueristiq.com/retrier/jitter"
+)
+
+// StrategyInfo describes a backoff strategy by name and parameters, so that logs, traces, and
+// configuration tooling can report exactly which strategy governed a retry sequence. It is shared
+// with the jitter strategies, which the composed strategies report among their parameters.
+type StrategyInfo = jitter.StrategyInfo
+
+// Describe returns the StrategyInfo of the backoff strategy. Strategies built by this package
+// report their name and parameters; any other function is reported as "custom".
+//
+// Returns:
+//   - info: The StrategyInfo describing the strategy.
+//
+// Example:
+//
+//	info := backoff.ExponentialWithFullJitter().Describe()
+//	// info.Name will be "exponential" and info.Parameters["jitter"] will be "full".
+func (b Backoff) Describe() (info StrategyInfo) {
+	if b == nil {
+		info = StrategyInfo{Name: "none"}
+
+		return
+	}
+
+	if described, ok := lookup(b); ok {
+		info = described.info
+
+		return
+	}
+
+	info = StrategyInfo{Name: "custom"}
+
+	return
+}
+
+// String implements fmt.Stringer by returning the textual form of the strategy's StrategyInfo.
+//
+// Returns:
+//   - description: The textual description of the strategy.
+func (b Backoff) String() (description string) {
+	description = b.Describe().String()
+
+	return
+}
+
+// description is the description of a strategy function built by this package.
+//
+// Fields:
+//   - info: The StrategyInfo of the function.
+//   - unjitter: The function returning the deterministic counterpart of the function, and whether it
+//     draws random delays that were stripped, or nil if it cannot draw random delays.
+//   - base: The strategy whose delays the jitter strategy is applied to, if any.
+//   - jitter: The jitter strategy the function applies, as constructed with its options, if any.
+//   - additive: Whether the jittered duration is added to the delay of base rather than replacing it.
+type description struct {
+	info     StrategyInfo
+	unjitter func() (unjittered Backoff, stripped bool)
+	base     Backoff
+	jitter   jitter.Strategy
+	additive bool
+}
+
+// probeAttempt is the attempt number a strategy function built by describe is called with to hand
+// over its description rather than compute a delay.
+const probeAttempt = math.MinInt
+
+var (
+	// probes holds the descriptions handed over by the strategy functions, keyed by probe ID.
+	probes sync.Map
+	// probeIDs generates the probe IDs, passed as the minDelay of the probing calls.
+	probeIDs atomic.Int64
+	// describedCode holds the code pointers of the strategy functions built by describe, one per call site.
+	describedCode sync.Map
+)
+
+// describe attaches the StrategyInfo of a built-in strategy function to the instance returned. The
+// functions returned by a constructor share their code pointer, which therefore only tells the
+// functions built by describe apart from custom ones: the description itself is carried by the
+// returned function, which hands it over to lookup when probed, so that every instance reports its
+// own parameters.
+//
+// Parameters:
+//   - b:    The strategy function to describe.
+//   - info: The StrategyInfo describing the function.
+//
+// Returns:
+//   - described: The strategy function, delegating to b.
+func describe(b Backoff, info StrategyInfo) (described Backoff) {
+	described = annotate(b, &description{info: info})
+
+	return
+}
+
+// annotate attaches a description to a built-in strategy function, as describe does, for the
+// strategies that can be stripped of their jitter.
+//
+// Parameters:
+//   - b: The strategy function to describe.
+//   - d: The description of the function.
+//
+// Returns:
+//   - described: The strategy function, delegating to b.
+func annotate(b Backoff, d *description) (described Backoff) {
+	described = func(minDelay, maxDelay time.Duration, attempt int) (backoff time.Duration) {
+		if attempt == probeAttempt {
+			probes.Store(minDelay, d)
+
+			return
+		}
+
+		backoff = b(minDelay, maxDelay, attempt)
+
+		return
+	}
+
+	describedCode.Store(reflect.ValueOf(described).Pointer(), struct{}{})
+
+	return
+}
+
+// lookup returns the description of a strategy function built by describe, by probing it.
+//
+// Parameters:
+//   - b: The strategy function.
+//
+// Returns:
+//   - d:  The description of b.
+//   - ok: Whether b was built by describe.
+func lookup(b Backoff) (d *description, ok bool) {
+	if b == nil {
+		return
+	}
+
+	if _, ok = describedCode.Load(reflect.ValueOf(b).Pointer()); !ok {
+		return
+	}
+
+	id := time.Duration(probeIDs.Add(1))
+
+	b(id, 0, probeAttempt)
+
+	value, ok := probes.LoadAndDelete(id)
+	if ok {
+		d, ok = value.(*description)
+	}
+
+	return
+}
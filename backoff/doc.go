@@ -5,7 +5,7 @@
 // retries, backoff mechanisms can reduce load on resources and prevent overwhelming
 // a system that may be experiencing temporary failure.
 //
-// This package supports multiple exponential backoff strategies, including:
+// This package supports multiple backoff strategies, including:
 //  1. **Exponential Backoff**: A strategy where the delay between retries increases
 //     exponentially based on the number of attempts.
 //  2. **Exponential Backoff with Equal Jitter**: Adds a moderate amount of randomness
@@ -14,8 +14,40 @@
 //     retry interval, introducing full jitter to the exponential delay.
 //  4. **Exponential Backoff with Decorrelated Jitter**: Calculates the retry interval
 //     based on the previous delay, ensuring bounded and random backoff durations.
+//  5. **Exponential Backoff with Symmetric Jitter**: Spreads the retry interval around
+//     the exponential delay, by up to a given fraction in both directions.
+//  6. **Exponential Backoff with Reset on Idle**: A stateful strategy whose exponent is a
+//     failure counter decaying back toward zero after idle periods without failures.
+//  7. **AutoTune** (experimental): Learns how long the dependency takes to recover and
+//     biases the delays toward that horizon.
+//  8. **Burst**: Allows a number of immediate retries before delegating to another strategy.
+//  9. **Linear Backoff**: A strategy where the delay grows by a fixed increment with each attempt,
+//     with equal or full jitter variants, suited to polling.
+//  10. **Polynomial Backoff**: A strategy where the delay grows as a power of the attempt number,
+//     tuning the growth curve between linear and exponential.
+//  11. **Schedule**: Follows an explicit schedule of delays, for exact and auditable retries.
 //
 // By adding jitter, the retry intervals are randomized, preventing the "thundering herd"
 // problem where multiple clients retry operations simultaneously, leading to further
 // system overload.
+//
+// The overflow-safe arithmetic helpers used by the built-in strategies (SafeMul, SafeShift,
+// and SafeAdd) are exported as well, so custom Backoff implementations can saturate at the
+// largest representable duration instead of overflowing.
+//
+// Strategies can be composed with WithJitter, which applies any jitter.Strategy to any base strategy,
+// and WithCap and WithFloor, which bound the delays of any strategy, rather than being limited to the
+// built-in jittered permutations.
+//
+// Strategy is a stateful alternative to Backoff, for strategies whose next delay depends on the
+// delays they actually returned before, such as DecorrelatedJitter. NewStrategy and FromStrategy
+// adapt between the two.
+//
+// Every Backoff implements fmt.Stringer and a Describe method returning a StrategyInfo, so the
+// strategy (including the jitter it applies) governing a retry sequence can be logged or traced.
+// Every instance built by this package reports its own parameters, e.g., "linear(increment=5ms)",
+// and the composed strategies report the strategy they wrap, e.g., "with-cap(base=..., ceiling=1s)".
+//
+// Register and Get resolve strategies by name, including custom ones, for configuration systems such
+// as the policy package.
 package backoff
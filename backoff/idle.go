@@ -0,0 +1,62 @@
+package backoff
+
+import (
+	"sync"
+	"time"
+)
+
+// ExponentialWithResetOnIdle returns a stateful backoff function implementing exponential backoff
+// whose exponent is an internal failure counter rather than the attempt number it is called with.
+// Every call records a failure, and the counter decays back toward zero by one for every idle period
+// elapsed since the previous failure. It suits supervisors restarting child processes, whose failures
+// are bursty and sparse: a burst of crashes backs off exponentially, while a crash after a long healthy
+// run restarts quickly again. The counter stops growing once the delay reaches maxDelay, so that it
+// decays from saturation as quickly as it reached it.
+//
+// Formula: delay = minDelay * 2^counter, where counter = max(0, failures - idle periods elapsed)
+//
+// The returned function is safe for concurrent use, and shares its counter between every caller, so
+// that one instance should be created per supervised process.
+//
+// Parameters:
+//   - idle: The period without failures after which the counter decays by one. A non-positive period
+//     disables the decay.
+//
+// Returns:
+//   - Backoff: The stateful backoff function.
+//
+// Example:
+//
+//	restart := backoff.ExponentialWithResetOnIdle(time.Minute)
+//	delay := restart(time.Second, time.Hour, 0)
+//	// delay grows with every failure, and shrinks back after every minute without failures.
+func ExponentialWithResetOnIdle(idle time.Duration) Backoff {
+	var (
+		mutex   sync.Mutex
+		counter int
+		last    time.Time
+	)
+
+	return describe(func(minDelay, maxDelay time.Duration, _ int) (backoff time.Duration) {
+		mutex.Lock()
+		defer mutex.Unlock()
+
+		now := time.Now()
+
+		if idle > 0 && !last.IsZero() {
+			counter = max(counter-int(now.Sub(last)/idle), 0)
+		}
+
+		backoff = SafeShift(minDelay, counter)
+
+		if backoff > maxDelay {
+			backoff = maxDelay
+		} else {
+			counter++
+		}
+
+		last = now
+
+		return
+	}, StrategyInfo{Name: "exponential-reset-on-idle", Parameters: map[string]string{"idle": idle.String(), "multiplier": "2"}})
+}
@@ -0,0 +1,139 @@
+package backoff
+
+import (
+	"time"
+
+	"go.source.hueristiq.com/retrier/jitter"
+)
+
+// Linear returns a backoff function that implements linear backoff. In this strategy, the delay grows
+// arithmetically with each retry attempt, by a fixed increment, but is capped by the provided maximum
+// duration. It suits polling, for which exponential backoff backs off too aggressively.
+//
+// Formula: delay = minDelay + increment * attempt
+//
+// Parameters:
+//   - increment: The duration added to the delay with each retry attempt.
+//
+// Returns:
+//   - Backoff: The linear backoff function.
+//
+// Example:
+//
+//	backoffFunc := backoff.Linear(500*time.Millisecond)
+//	delay := backoffFunc(1*time.Second, 30*time.Second, 3)
+//	// delay will be 2.5 seconds (1s + 500ms * 3), but capped at maxDelay if exceeded.
+func Linear(increment time.Duration) Backoff {
+	return describe(func(minDelay, maxDelay time.Duration, attempt int) (backoff time.Duration) {
+		backoff = linear(minDelay, increment, attempt)
+
+		if backoff > maxDelay {
+			backoff = maxDelay
+		}
+
+		return
+	}, StrategyInfo{Name: "linear", Parameters: map[string]string{"increment": increment.String()}})
+}
+
+// LinearWithEqualJitter returns a backoff function that implements linear backoff with equal jitter.
+// In this strategy, the base delay grows linearly, and equal jitter is applied to introduce moderate
+// randomness by adding a random value from the midpoint of the calculated delay.
+//
+// Formula: delay = minDelay + increment * attempt + random(midpoint, delay)
+//
+// The optional jitter options, such as jitter.WithFloor, are forwarded to the jitter strategy.
+//
+// Parameters:
+//   - increment: The duration added to the delay with each retry attempt.
+//   - opts:      Optional jitter options.
+//
+// Returns:
+//   - Backoff: The linear backoff function with equal jitter.
+//
+// Example:
+//
+//	backoffFunc := backoff.LinearWithEqualJitter(500*time.Millisecond)
+//	delay := backoffFunc(1*time.Second, 30*time.Second, 3)
+//	// delay will be linearly calculated with equal jitter applied.
+func LinearWithEqualJitter(increment time.Duration, opts ...jitter.Option) Backoff {
+	j := jitter.NewEqual(opts...)
+
+	return annotate(func(minDelay, maxDelay time.Duration, attempt int) (backoff time.Duration) {
+		backoff = linear(minDelay, increment, attempt)
+
+		jittered := j.Apply(backoff)
+
+		backoff = SafeAdd(backoff, jittered)
+
+		if backoff > maxDelay {
+			backoff = maxDelay
+		}
+
+		return
+	}, &description{
+		info:     StrategyInfo{Name: "linear", Parameters: map[string]string{"increment": increment.String(), "jitter": jitter.Describe(j).String()}},
+		unjitter: stripsTo(func() Backoff { return Linear(increment) }),
+		base:     Linear(increment),
+		jitter:   j,
+		additive: true,
+	})
+}
+
+// LinearWithFullJitter returns a backoff function that implements linear backoff with full jitter.
+// In this strategy, the base delay grows linearly, and full jitter is applied by adding a random value
+// drawn from a uniform distribution between 0 and the calculated delay.
+//
+// Formula: delay = minDelay + increment * attempt + random(0, delay)
+//
+// The optional jitter options, such as jitter.WithFloor, are forwarded to the jitter strategy.
+//
+// Parameters:
+//   - increment: The duration added to the delay with each retry attempt.
+//   - opts:      Optional jitter options.
+//
+// Returns:
+//   - Backoff: The linear backoff function with full jitter.
+//
+// Example:
+//
+//	backoffFunc := backoff.LinearWithFullJitter(500*time.Millisecond)
+//	delay := backoffFunc(1*time.Second, 30*time.Second, 3)
+//	// delay will be linearly calculated with full jitter applied.
+func LinearWithFullJitter(increment time.Duration, opts ...jitter.Option) Backoff {
+	j := jitter.NewFull(opts...)
+
+	return annotate(func(minDelay, maxDelay time.Duration, attempt int) (backoff time.Duration) {
+		backoff = linear(minDelay, increment, attempt)
+
+		jittered := j.Apply(backoff)
+
+		backoff = SafeAdd(backoff, jittered)
+
+		if backoff > maxDelay {
+			backoff = maxDelay
+		}
+
+		return
+	}, &description{
+		info:     StrategyInfo{Name: "linear", Parameters: map[string]string{"increment": increment.String(), "jitter": jitter.Describe(j).String()}},
+		unjitter: stripsTo(func() Backoff { return Linear(increment) }),
+		base:     Linear(increment),
+		jitter:   j,
+		additive: true,
+	})
+}
+
+// linear returns the linearly grown delay of an attempt, saturating instead of overflowing.
+//
+// Parameters:
+//   - minDelay:  The delay of the first attempt.
+//   - increment: The duration added with each attempt.
+//   - attempt:   The attempt number.
+//
+// Returns:
+//   - delay: The delay of the attempt.
+func linear(minDelay, increment time.Duration, attempt int) (delay time.Duration) {
+	delay = SafeAdd(minDelay, SafeMul(increment, float64(max(attempt, 0))))
+
+	return
+}
@@ -0,0 +1,39 @@
+package backoff
+
+import (
+	"math"
+	"strconv"
+	"time"
+)
+
+// Polynomial returns a backoff function that implements polynomial backoff. In this strategy, the
+// delay grows as a power of the attempt number, so that the exponent tunes the growth curve between
+// linear (1) and steeper ones, without the doubling of exponential backoff, but is capped by the
+// provided maximum duration. As attempts are numbered from zero, the first delay is minDelay.
+//
+// Formula: delay = minDelay * (attempt + 1)^exponent
+//
+// Parameters:
+//   - exponent: The exponent of the attempt number. Negative exponents count as 0.
+//
+// Returns:
+//   - Backoff: The polynomial backoff function.
+//
+// Example:
+//
+//	backoffFunc := backoff.Polynomial(2)
+//	delay := backoffFunc(1*time.Second, 30*time.Second, 3)
+//	// delay will be 16 seconds (1s * 4^2), but capped at maxDelay if exceeded.
+func Polynomial(exponent float64) Backoff {
+	exponent = max(exponent, 0)
+
+	return describe(func(minDelay, maxDelay time.Duration, attempt int) (backoff time.Duration) {
+		backoff = SafeMul(minDelay, math.Pow(float64(max(attempt, 0))+1, exponent))
+
+		if backoff > maxDelay {
+			backoff = maxDelay
+		}
+
+		return
+	}, StrategyInfo{Name: "polynomial", Parameters: map[string]string{"exponent": strconv.FormatFloat(exponent, 'g', -1, 64)}})
+}
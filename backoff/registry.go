@@ -0,0 +1,105 @@
+package backoff
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+)
+
+var (
+	// ErrAlreadyRegistered is returned by Register for a name already in use.
+	ErrAlreadyRegistered = errors.New("backoff strategy already registered")
+	// ErrInvalidRegistration is returned by Register for an empty name or a nil factory.
+	ErrInvalidRegistration = errors.New("invalid backoff strategy registration")
+)
+
+// registry holds the backoff strategies resolvable by name, starting with the built-in strategies
+// that need no parameters.
+var registry = struct {
+	mutex     sync.RWMutex
+	factories map[string]func() Backoff
+}{
+	factories: map[string]func() Backoff{
+		"exponential":                     Exponential,
+		"exponential-full-jitter":         func() Backoff { return ExponentialWithFullJitter() },
+		"exponential-equal-jitter":        func() Backoff { return ExponentialWithEqualJitter() },
+		"exponential-decorrelated-jitter": func() Backoff { return ExponentialWithDecorrelatedJitter() },
+	},
+}
+
+// Register makes a backoff strategy resolvable by name through Get, so that configuration systems,
+// such as the policy package, can refer to custom strategies by name. The built-in exponential
+// strategies are registered as "exponential", "exponential-full-jitter", "exponential-equal-jitter",
+// and "exponential-decorrelated-jitter".
+//
+// Parameters:
+//   - name:    The name of the strategy.
+//   - factory: The function creating the strategy, called by every Get.
+//
+// Returns:
+//   - err: An error wrapping ErrInvalidRegistration if the name is empty or the factory nil, or
+//     ErrAlreadyRegistered if the name is already in use.
+//
+// Example:
+//
+//	err := backoff.Register("fibonacci", func() backoff.Backoff { return backoff.Schedule(1*time.Second, 2*time.Second, 3*time.Second) })
+func Register(name string, factory func() Backoff) (err error) {
+	if name == "" || factory == nil {
+		err = fmt.Errorf("%w: empty name or nil factory", ErrInvalidRegistration)
+
+		return
+	}
+
+	registry.mutex.Lock()
+	defer registry.mutex.Unlock()
+
+	if _, ok := registry.factories[name]; ok {
+		err = fmt.Errorf("%w: %q", ErrAlreadyRegistered, name)
+
+		return
+	}
+
+	registry.factories[name] = factory
+
+	return
+}
+
+// Get creates the backoff strategy registered under a name.
+//
+// Parameters:
+//   - name: The name of the strategy.
+//
+// Returns:
+//   - strategy: The strategy created by the factory registered under name.
+//   - ok:       Whether a strategy is registered under name.
+func Get(name string) (strategy Backoff, ok bool) {
+	registry.mutex.RLock()
+	factory, ok := registry.factories[name]
+	registry.mutex.RUnlock()
+
+	if ok {
+		strategy = factory()
+	}
+
+	return
+}
+
+// Names returns the names of the registered backoff strategies, sorted.
+//
+// Returns:
+//   - names: The names of the registered strategies.
+func Names() (names []string) {
+	registry.mutex.RLock()
+	defer registry.mutex.RUnlock()
+
+	names = make([]string, 0, len(registry.factories))
+
+	for name := range registry.factories {
+		names = append(names, name)
+	}
+
+	slices.Sort(names)
+
+	return
+}
@@ -0,0 +1,99 @@
+package backoff
+
+import (
+	"math"
+	"time"
+)
+
+// SafeMul multiplies a duration by a floating-point factor with saturation semantics. Instead of
+// overflowing, the result is capped at the largest (or smallest) representable time.Duration.
+//
+// Parameters:
+//   - d:      The duration to multiply.
+//   - factor: The factor to multiply the duration by.
+//
+// Returns:
+//   - product: The saturated product of d and factor. A NaN product yields 0.
+//
+// Example:
+//
+//	product := backoff.SafeMul(time.Second, 1.5)
+//	// product will be 1.5 seconds.
+func SafeMul(d time.Duration, factor float64) (product time.Duration) {
+	f := float64(d) * factor
+
+	switch {
+	case math.IsNaN(f):
+		product = 0
+	case f >= math.MaxInt64:
+		product = math.MaxInt64
+	case f <= math.MinInt64:
+		product = math.MinInt64
+	default:
+		product = time.Duration(f)
+	}
+
+	return
+}
+
+// SafeShift multiplies a duration by 2^n with saturation semantics. A negative n divides the duration
+// by 2^-n instead. This is the building block of exponential strategies, where n is the attempt number.
+//
+// Parameters:
+//   - d: The duration to shift.
+//   - n: The number of binary orders of magnitude to shift by.
+//
+// Returns:
+//   - shifted: The saturated value of d * 2^n.
+//
+// Example:
+//
+//	shifted := backoff.SafeShift(time.Second, 3)
+//	// shifted will be 8 seconds.
+func SafeShift(d time.Duration, n int) (shifted time.Duration) {
+	switch {
+	case d == 0:
+		shifted = 0
+	case n < 0:
+		if n <= -63 {
+			return 0
+		}
+
+		shifted = d >> -n
+	case n >= 63, d > math.MaxInt64>>n:
+		shifted = math.MaxInt64
+	case d < math.MinInt64>>n:
+		shifted = math.MinInt64
+	default:
+		shifted = d << n
+	}
+
+	return
+}
+
+// SafeAdd adds two durations with saturation semantics. Instead of overflowing, the sum is capped at
+// the largest (or smallest) representable time.Duration.
+//
+// Parameters:
+//   - a: The first duration.
+//   - b: The second duration.
+//
+// Returns:
+//   - sum: The saturated sum of a and b.
+//
+// Example:
+//
+//	sum := backoff.SafeAdd(time.Duration(math.MaxInt64), time.Second)
+//	// sum will be time.Duration(math.MaxInt64).
+func SafeAdd(a, b time.Duration) (sum time.Duration) {
+	switch {
+	case b > 0 && a > math.MaxInt64-b:
+		sum = math.MaxInt64
+	case b < 0 && a < math.MinInt64-b:
+		sum = math.MinInt64
+	default:
+		sum = a + b
+	}
+
+	return
+}
@@ -0,0 +1,39 @@
+package backoff
+
+import (
+	"fmt"
+	"slices"
+	"time"
+)
+
+// Schedule returns a backoff function following an explicit schedule of delays, e.g., [1s, 5s, 30s,
+// 2m], for exact and auditable retry schedules: attempt N is delayed by the Nth duration, and attempts
+// beyond the schedule by its last one. The schedule is followed as is, regardless of minDelay and
+// maxDelay. To give up once the schedule is exhausted instead, use retrier.WithSchedule.
+//
+// Formula: delay = durations[min(attempt, len(durations) - 1)]
+//
+// Parameters:
+//   - durations: The schedule of delays. An empty schedule yields no delay.
+//
+// Returns:
+//   - Backoff: The schedule backoff function.
+//
+// Example:
+//
+//	backoffFunc := backoff.Schedule(time.Second, 5*time.Second, 30*time.Second, 2*time.Minute)
+//	delay := backoffFunc(0, 0, 5)
+//	// delay will be 2 minutes, the last delay of the schedule.
+func Schedule(durations ...time.Duration) Backoff {
+	durations = slices.Clone(durations)
+
+	return describe(func(_, _ time.Duration, attempt int) (backoff time.Duration) {
+		if len(durations) == 0 {
+			return
+		}
+
+		backoff = durations[min(max(attempt, 0), len(durations)-1)]
+
+		return
+	}, StrategyInfo{Name: "schedule", Parameters: map[string]string{"durations": fmt.Sprint(durations)}})
+}
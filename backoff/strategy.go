@@ -0,0 +1,102 @@
+package backoff
+
+import (
+	"sync"
+	"time"
+)
+
+// Strategy is a stateful alternative to Backoff, for strategies whose next delay depends on the
+// delays they actually returned before, such as decorrelated jitter, which the attempt number alone
+// cannot reconstruct. A Strategy serves one retry sequence at a time.
+type Strategy interface {
+	// Next returns the delay before the next retry and advances the strategy.
+	Next() (delay time.Duration)
+	// Reset returns the strategy to its initial state, before the first retry of a sequence.
+	Reset()
+}
+
+// backoffStrategy is a Strategy following a Backoff function.
+type backoffStrategy struct {
+	b        Backoff
+	minDelay time.Duration
+	maxDelay time.Duration
+	attempt  int
+}
+
+// NewStrategy adapts a Backoff function into a Strategy, which calls it with increasing attempt
+// numbers, starting from 0.
+//
+// Parameters:
+//   - b:        The backoff function.
+//   - minDelay: The minimum delay passed to b.
+//   - maxDelay: The maximum delay passed to b.
+//
+// Returns:
+//   - strategy: The Strategy following b.
+//
+// Example:
+//
+//	strategy := backoff.NewStrategy(backoff.Exponential(), time.Second, 30*time.Second)
+//	strategy.Next() // 1s
+//	strategy.Next() // 2s
+func NewStrategy(b Backoff, minDelay, maxDelay time.Duration) (strategy Strategy) {
+	strategy = &backoffStrategy{b: b, minDelay: minDelay, maxDelay: maxDelay}
+
+	return
+}
+
+// Next implements Strategy.
+func (s *backoffStrategy) Next() (delay time.Duration) {
+	delay = s.b(s.minDelay, s.maxDelay, s.attempt)
+
+	s.attempt++
+
+	return
+}
+
+// Reset implements Strategy.
+func (s *backoffStrategy) Reset() {
+	s.attempt = 0
+}
+
+// FromStrategy adapts a Strategy into a Backoff function, which resets the strategy when called for
+// attempt 0, and otherwise returns its next delay, ignoring the delay bounds it is called with. As the
+// strategy is shared by every call, the returned function must serve one retry sequence at a time;
+// retrier.WithStrategy creates a Strategy per retry sequence instead.
+//
+// Parameters:
+//   - strategy: The Strategy to follow.
+//
+// Returns:
+//   - Backoff: The backoff function following strategy.
+//
+// Example:
+//
+//	retrier.WithBackoff(backoff.FromStrategy(strategy))
+func FromStrategy(strategy Strategy) Backoff {
+	var mutex sync.Mutex
+
+	return annotate(func(_, _ time.Duration, attempt int) (backoff time.Duration) {
+		mutex.Lock()
+		defer mutex.Unlock()
+
+		if attempt == 0 {
+			strategy.Reset()
+		}
+
+		backoff = strategy.Next()
+
+		return
+	}, &description{
+		info: StrategyInfo{Name: "stateful"},
+		unjitter: func() (unjittered Backoff, stripped bool) {
+			var base Strategy
+
+			if base, stripped = UnjitteredStrategy(strategy); stripped {
+				unjittered = FromStrategy(base)
+			}
+
+			return
+		},
+	})
+}
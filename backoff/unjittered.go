@@ -0,0 +1,134 @@
+package backoff
+
+import "go.source.hueristiq.com/retrier/jitter"
+
+// Unjittered returns the deterministic counterpart of a jittered strategy built by this package,
+// stripped of its jitter, e.g., Exponential for ExponentialWithFullJitter, Linear for the jittered
+// linear strategies, and the base strategy for WithJitter. The composed strategies, i.e., WithJitter,
+// WithCap, WithFloor, Burst, and FromStrategy, are stripped recursively, so that a jitter nested
+// anywhere in a composition is removed. Custom strategies, which may draw random delays internally,
+// are returned as is, and must be checked for determinism by the caller.
+//
+// Parameters:
+//   - b: The backoff function to strip.
+//
+// Returns:
+//   - unjittered: The deterministic counterpart of b, or b itself if it cannot be stripped.
+//   - stripped:   Whether b was a jittered strategy that was stripped.
+//
+// Example:
+//
+//	unjittered, _ := backoff.Unjittered(backoff.WithCap(backoff.ExponentialWithFullJitter(), time.Minute))
+//	delay := unjittered(1*time.Second, 30*time.Second, 3)
+//	// delay will be exactly 8 seconds.
+func Unjittered(b Backoff) (unjittered Backoff, stripped bool) {
+	unjittered = b
+
+	if d, ok := lookup(b); ok && d.unjitter != nil {
+		if base, ok := d.unjitter(); ok {
+			unjittered, stripped = base, true
+		}
+	}
+
+	return
+}
+
+// JitterOf returns the jitter.Strategy a jittered strategy built by this package applies, as
+// constructed with its options, and the strategy whose delays it is applied to, so that the jitter
+// band of its delays is given by the Bounds of the jitter strategy, e.g., to compute the shortest
+// schedule of delays. The built-in jittered strategies add the jittered duration to the delay of their
+// base, while WithJitter and ExponentialWithSymmetricJitter replace the delay with it; either way, the
+// result is capped at maxDelay.
+//
+// Parameters:
+//   - b: The backoff function.
+//
+// Returns:
+//   - base:     The strategy whose delays the jitter strategy is applied to, which may itself be
+//     jittered, e.g., for WithJitter.
+//   - strategy: The jitter strategy b applies.
+//   - additive: Whether the jittered duration is added to the delay of base rather than replacing it.
+//   - ok:       Whether b applies a jitter.Strategy, which ExponentialWithDecorrelatedJitter, whose
+//     jitter depends on the previous delay, does not.
+//
+// Example:
+//
+//	base, strategy, additive, _ := backoff.JitterOf(backoff.ExponentialWithFullJitter())
+//	delay := base(time.Second, time.Minute, 3)
+//	lower, upper := strategy.Bounds(delay)
+//	// With additive true, delays lie between delay+lower and delay+upper, i.e., 8s and 16s.
+func JitterOf(b Backoff) (base Backoff, strategy jitter.Strategy, additive, ok bool) {
+	d, described := lookup(b)
+	if !described || d.jitter == nil {
+		return
+	}
+
+	base, strategy, additive, ok = d.base, d.jitter, d.additive, true
+
+	return
+}
+
+// UnjitteredStrategy returns the deterministic counterpart of a stateful jittered Strategy built by
+// this package, e.g., a strategy following Exponential for DecorrelatedJitter. Any other Strategy is
+// returned as is.
+//
+// Parameters:
+//   - strategy: The Strategy to strip.
+//
+// Returns:
+//   - unjittered: The deterministic counterpart of strategy, or strategy itself if it cannot be stripped.
+//   - stripped:   Whether strategy was a jittered strategy that was stripped.
+//
+// Example:
+//
+//	unjittered, _ := backoff.UnjitteredStrategy(backoff.DecorrelatedJitter(time.Second, time.Minute))
+//	unjittered.Next() // 1s
+//	unjittered.Next() // 2s
+func UnjitteredStrategy(strategy Strategy) (unjittered Strategy, stripped bool) {
+	unjittered = strategy
+
+	if jittered, ok := strategy.(interface{ unjittered() Strategy }); ok {
+		unjittered, stripped = jittered.unjittered(), true
+	}
+
+	return
+}
+
+// stripsTo returns the unjitter function of a jittered strategy whose deterministic counterpart is
+// built by base.
+//
+// Parameters:
+//   - base: The constructor of the deterministic counterpart.
+//
+// Returns:
+//   - unjitter: The unjitter function.
+func stripsTo(base func() Backoff) (unjitter func() (unjittered Backoff, stripped bool)) {
+	unjitter = func() (unjittered Backoff, stripped bool) {
+		unjittered, stripped = base(), true
+
+		return
+	}
+
+	return
+}
+
+// rewrap returns the unjitter function of a strategy wrapping b, which is stripped when b is, by
+// wrapping the deterministic counterpart of b the same way.
+//
+// Parameters:
+//   - b:    The wrapped strategy.
+//   - wrap: The function wrapping a strategy as the wrapping strategy does.
+//
+// Returns:
+//   - unjitter: The unjitter function.
+func rewrap(b Backoff, wrap func(b Backoff) Backoff) (unjitter func() (unjittered Backoff, stripped bool)) {
+	unjitter = func() (unjittered Backoff, stripped bool) {
+		if unjittered, stripped = Unjittered(b); stripped {
+			unjittered = wrap(unjittered)
+		}
+
+		return
+	}
+
+	return
+}
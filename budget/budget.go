@@ -0,0 +1,161 @@
+package budget
+
+import (
+	"errors"
+	"time"
+)
+
+// ErrExhausted is wrapped by the error of a retry sequence that gave up because its Budget was exhausted.
+var ErrExhausted = errors.New("retry budget exhausted")
+
+// Backend stores the requests and retries a Budget accounts for. The default backend, returned by
+// NewWindow, counts them in the memory of the process; distributed backends, e.g., a token bucket in
+// Redis, let a fleet of processes share one retry budget against a fragile dependency.
+//
+// Implementations must be safe for concurrent use, and should check and record a retry atomically.
+type Backend interface {
+	// Request records a request, i.e., the first attempt of a retry sequence.
+	Request() (err error)
+	// TryRetry records a retry if the retries, including it, amount to at most minRetries plus ratio
+	// of the requests, and reports whether it was recorded.
+	TryRetry(ratio float64, minRetries int) (allowed bool, err error)
+	// Ratio returns the ratio of the retries to the requests.
+	Ratio() (ratio float64, err error)
+}
+
+// Budget is a retry budget shared by retry sequences. It is safe for concurrent use.
+type Budget struct {
+	ratio      float64
+	minRetries int
+	backend    Backend
+	onError    func(err error)
+}
+
+// Option is a function type used to modify a Budget.
+//
+// Parameters:
+//   - *Budget: A pointer to the Budget that allows modification of its fields.
+type Option func(*Budget)
+
+// WithMinRetries sets the number of retries allowed per window regardless of the ratio, so that
+// callers with little traffic can still retry. It defaults to 10.
+//
+// Parameters:
+//   - minRetries: The number of retries allowed per window regardless of the ratio. Negative values count as 0.
+//
+// Returns:
+//   - Option: A functional option that modifies the Budget to set the minRetries field.
+func WithMinRetries(minRetries int) Option {
+	return func(b *Budget) {
+		b.minRetries = max(minRetries, 0)
+	}
+}
+
+// WithBackend sets the Backend storing the requests and retries of the Budget, e.g., one shared by a
+// fleet of processes. The window of New is then up to the backend. When the backend fails, the Budget
+// fails open, allowing retries, so that an unavailable backend does not disable retries altogether.
+//
+// Parameters:
+//   - backend: The Backend. A nil backend keeps the default one.
+//
+// Returns:
+//   - Option: A functional option that modifies the Budget to set the backend field.
+//
+// Example:
+//
+//	b := budget.New(0.2, 10*time.Second, budget.WithBackend(redisBackend))
+func WithBackend(backend Backend) Option {
+	return func(b *Budget) {
+		if backend != nil {
+			b.backend = backend
+		}
+	}
+}
+
+// WithErrorHandler sets a callback receiving the errors of the Backend, e.g., to log them.
+//
+// Parameters:
+//   - onError: The callback receiving the errors of the Backend.
+//
+// Returns:
+//   - Option: A functional option that modifies the Budget to set the onError field.
+func WithErrorHandler(onError func(err error)) Option {
+	return func(b *Budget) {
+		b.onError = onError
+	}
+}
+
+// New returns a Budget allowing retries to amount to at most ratio of the requests observed over the
+// sliding window.
+//
+// Parameters:
+//   - ratio:  The maximum ratio of retries to requests, e.g., 0.2 for 20%. Negative values count as 0.
+//   - window: The duration of the sliding window of the default Backend. It is raised to 1s if it is shorter.
+//   - opts:   Optional configuration options, such as WithMinRetries.
+//
+// Returns:
+//   - b: The new Budget.
+//
+// Example:
+//
+//	b := budget.New(0.2, 10*time.Second)
+//	err := retrier.Retry(ctx, operation, retrier.WithBudget(b))
+func New(ratio float64, window time.Duration, opts ...Option) (b *Budget) {
+	b = &Budget{
+		ratio:      max(ratio, 0),
+		minRetries: 10,
+	}
+
+	for _, opt := range opts {
+		opt(b)
+	}
+
+	if b.backend == nil {
+		b.backend = NewWindow(window)
+	}
+
+	return
+}
+
+// Request records a request, i.e., the first attempt of a retry sequence, which is always allowed and
+// raises the number of retries the Budget allows.
+func (b *Budget) Request() {
+	b.report(b.backend.Request())
+}
+
+// TryRetry records a retry if the Budget allows it.
+//
+// Returns:
+//   - allowed: Whether the retry is allowed. A refused retry is not recorded.
+func (b *Budget) TryRetry() (allowed bool) {
+	allowed, err := b.backend.TryRetry(b.ratio, b.minRetries)
+	if err != nil {
+		b.report(err)
+
+		allowed = true
+	}
+
+	return
+}
+
+// Ratio returns the ratio of the retries to the requests observed over the sliding window.
+//
+// Returns:
+//   - ratio: The ratio of the retries to the requests, or 0 if no request was observed or the Backend failed.
+func (b *Budget) Ratio() (ratio float64) {
+	ratio, err := b.backend.Ratio()
+
+	b.report(err)
+
+	return
+}
+
+// report passes an error of the Backend to the error handler, if any.
+//
+// Parameters:
+//   - err: The error of the Backend, or nil.
+func (b *Budget) report(err error) {
+	if err != nil && b.onError != nil {
+		b.onError(err)
+	}
+}
@@ -0,0 +1,81 @@
+package budget_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.source.hueristiq.com/retrier/budget"
+)
+
+func TestBudget(t *testing.T) {
+	t.Parallel()
+
+	b := budget.New(0.2, time.Minute, budget.WithMinRetries(1))
+
+	assert.True(t, b.TryRetry(), "Expected the minimum retries to be allowed without requests")
+	assert.False(t, b.TryRetry(), "Expected retries beyond the minimum to be refused without requests")
+
+	for range 10 {
+		b.Request()
+	}
+
+	assert.True(t, b.TryRetry(), "Expected retries within the ratio to be allowed")
+	assert.True(t, b.TryRetry(), "Expected retries within the ratio to be allowed")
+	assert.False(t, b.TryRetry(), "Expected retries beyond the ratio to be refused")
+	assert.InDelta(t, 0.3, b.Ratio(), 1e-9, "Unexpected ratio of retries to requests")
+}
+
+func TestBudget_Window(t *testing.T) {
+	t.Parallel()
+
+	b := budget.New(0, time.Second, budget.WithMinRetries(1))
+
+	assert.True(t, b.TryRetry(), "Expected the minimum retries to be allowed")
+	assert.False(t, b.TryRetry(), "Expected the budget to be exhausted")
+
+	time.Sleep(1100 * time.Millisecond)
+
+	assert.True(t, b.TryRetry(), "Expected the budget to recover once the window slid")
+}
+
+var errBackendDown = errors.New("backend down")
+
+type failingBackend struct{}
+
+func (failingBackend) Request() error {
+	return errBackendDown
+}
+
+func (failingBackend) TryRetry(_ float64, _ int) (bool, error) {
+	return false, errBackendDown
+}
+
+func (failingBackend) Ratio() (float64, error) {
+	return 0, errBackendDown
+}
+
+func TestBudget_Backend(t *testing.T) {
+	t.Parallel()
+
+	// Two processes sharing one backend share one budget.
+	shared := budget.NewWindow(time.Minute)
+
+	first := budget.New(0, time.Minute, budget.WithMinRetries(1), budget.WithBackend(shared))
+	second := budget.New(0, time.Minute, budget.WithMinRetries(1), budget.WithBackend(shared))
+
+	assert.True(t, first.TryRetry(), "Expected the first retry of the fleet to be allowed")
+	assert.False(t, second.TryRetry(), "Expected the budget to be exhausted for the whole fleet")
+
+	var reported []error
+
+	failing := budget.New(0, time.Minute, budget.WithMinRetries(0), budget.WithBackend(failingBackend{}), budget.WithErrorHandler(func(err error) {
+		reported = append(reported, err)
+	}))
+
+	failing.Request()
+
+	assert.True(t, failing.TryRetry(), "Expected the budget to fail open")
+	assert.Len(t, reported, 2, "Expected the errors of the backend to be reported")
+}
@@ -0,0 +1,13 @@
+// Package budget provides retry budgets, which cap the rate of retries across every retry sequence
+// sharing them, to keep retries from amplifying an outage into a retry storm.
+//
+// A Budget allows retries as long as they amount to at most a ratio of the requests, i.e., of the
+// first attempts, observed over a sliding window, e.g., at most 20% over the last 10 seconds, plus a
+// minimum number of retries per window so that low-traffic callers can still retry. Sharing one Budget
+// between the retry sequences of many goroutines, through retrier.WithBudget, makes them collectively
+// stop retrying once it is exhausted, while first attempts are never refused.
+//
+// The requests and retries are stored by a Backend: by default a Window, counting them in the memory
+// of the process, or a distributed implementation, e.g., a token bucket in Redis, set through
+// WithBackend, so that a fleet of processes shares one retry budget against a fragile dependency.
+package budget
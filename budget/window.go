@@ -0,0 +1,139 @@
+package budget
+
+import (
+	"sync"
+	"time"
+)
+
+// buckets is the number of buckets the sliding window of a Window is divided into.
+const buckets = 10
+
+// bucket counts the requests and retries of one slice of the sliding window.
+//
+// Fields:
+//   - slice:    The index of the slice of time the bucket counts.
+//   - requests: The number of requests during the slice.
+//   - retries:  The number of retries allowed during the slice.
+type bucket struct {
+	slice    int64
+	requests int
+	retries  int
+}
+
+// Window is the default Backend, counting the requests and retries of the process over a sliding
+// window. It is safe for concurrent use.
+type Window struct {
+	slice time.Duration
+
+	mutex   sync.Mutex
+	buckets [buckets]bucket
+}
+
+// NewWindow returns a Window counting requests and retries over a sliding window.
+//
+// Parameters:
+//   - window: The duration of the sliding window. It is raised to 1s if it is shorter.
+//
+// Returns:
+//   - w: The new Window.
+func NewWindow(window time.Duration) (w *Window) {
+	w = &Window{
+		slice: max(window, time.Second) / buckets,
+	}
+
+	return
+}
+
+// Request implements Backend by counting a request in the current slice.
+//
+// Returns:
+//   - err: Always nil.
+func (w *Window) Request() (err error) {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+
+	w.current().requests++
+
+	return
+}
+
+// TryRetry implements Backend by counting a retry in the current slice if the window allows it.
+//
+// Parameters:
+//   - ratio:      The maximum ratio of retries to requests.
+//   - minRetries: The number of retries allowed regardless of the ratio.
+//
+// Returns:
+//   - allowed: Whether the retry is allowed.
+//   - err:     Always nil.
+func (w *Window) TryRetry(ratio float64, minRetries int) (allowed bool, err error) {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+
+	current := w.current()
+
+	requests, retries := w.sum(current.slice)
+
+	if allowed = float64(retries+1) <= float64(minRetries)+ratio*float64(requests); allowed {
+		current.retries++
+	}
+
+	return
+}
+
+// Ratio implements Backend by returning the ratio of the retries to the requests within the window.
+//
+// Returns:
+//   - ratio: The ratio of the retries to the requests, or 0 if no request was observed.
+//   - err:   Always nil.
+func (w *Window) Ratio() (ratio float64, err error) {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+
+	requests, retries := w.sum(w.current().slice)
+
+	if requests > 0 {
+		ratio = float64(retries) / float64(requests)
+	}
+
+	return
+}
+
+// current returns the bucket of the current slice of time, recycling it if it counted a past slice.
+// It must be called with the mutex held.
+//
+// Returns:
+//   - current: The bucket of the current slice.
+func (w *Window) current() (current *bucket) {
+	slice := time.Now().UnixNano() / int64(w.slice)
+
+	current = &w.buckets[slice%buckets]
+
+	if current.slice != slice {
+		*current = bucket{slice: slice}
+	}
+
+	return
+}
+
+// sum returns the requests and retries counted by the buckets of the sliding window ending with the
+// given slice. It must be called with the mutex held.
+//
+// Parameters:
+//   - slice: The index of the current slice of time.
+//
+// Returns:
+//   - requests: The number of requests within the window.
+//   - retries:  The number of retries within the window.
+func (w *Window) sum(slice int64) (requests, retries int) {
+	for i := range w.buckets {
+		if slice-w.buckets[i].slice >= buckets {
+			continue
+		}
+
+		requests += w.buckets[i].requests
+		retries += w.buckets[i].retries
+	}
+
+	return
+}
@@ -0,0 +1,94 @@
+package retrier
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// negativeEntry is a failure cached by WithNegativeCache.
+type negativeEntry struct {
+	err     error
+	expires time.Time
+}
+
+// negativeCache holds the failures of retry sequences configured with WithNegativeCache, keyed by the
+// negative cache key of the sequence.
+var negativeCache = struct {
+	mutex     sync.Mutex
+	entries   map[string]negativeEntry
+	lastSweep time.Time
+}{
+	entries: make(map[string]negativeEntry),
+}
+
+// lookupNegative returns the failure cached for key, if it has not expired yet.
+//
+// Parameters:
+//   - key: The negative cache key.
+//   - now: The current time of the clock of the retry sequence.
+//
+// Returns:
+//   - err: The cached failure, or nil if none is cached for key.
+func lookupNegative(key string, now time.Time) (err error) {
+	negativeCache.mutex.Lock()
+	defer negativeCache.mutex.Unlock()
+
+	entry, ok := negativeCache.entries[key]
+	if !ok {
+		return
+	}
+
+	if now.After(entry.expires) {
+		delete(negativeCache.entries, key)
+
+		return
+	}
+
+	err = entry.err
+
+	return
+}
+
+// storeNegative caches the failure of a retry sequence under key for ttl. Expired entries are swept
+// at most once per ttl, so failures for keys that are never looked up again do not accumulate.
+//
+// Parameters:
+//   - key: The negative cache key.
+//   - ttl: The duration for which the failure is cached.
+//   - err: The failure to cache.
+//   - now: The current time of the clock of the retry sequence.
+func storeNegative(key string, ttl time.Duration, err error, now time.Time) {
+	negativeCache.mutex.Lock()
+	defer negativeCache.mutex.Unlock()
+
+	if now.Sub(negativeCache.lastSweep) > ttl {
+		for k, entry := range negativeCache.entries {
+			if now.After(entry.expires) {
+				delete(negativeCache.entries, k)
+			}
+		}
+
+		negativeCache.lastSweep = now
+	}
+
+	negativeCache.entries[key] = negativeEntry{err: err, expires: now.Add(ttl)}
+}
+
+// negativeCacheKey derives the negative cache key of a retry sequence.
+//
+// Parameters:
+//   - ctx: The context of the retry sequence.
+//   - cfg: The Configuration of the retry sequence.
+//
+// Returns:
+//   - key: The negative cache key, or an empty string if negative caching is disabled.
+func negativeCacheKey(ctx context.Context, cfg *Configuration) (key string) {
+	if cfg.negativeCacheKey == nil || cfg.negativeCacheTTL <= 0 {
+		return
+	}
+
+	key = cfg.negativeCacheKey(ctx)
+
+	return
+}
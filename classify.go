@@ -0,0 +1,151 @@
+package retrier
+
+import (
+	"context"
+	"errors"
+	"os"
+)
+
+// Class is the outcome of an attempt in a small taxonomy shared across services, so that dashboards
+// built on the statistics of different services use a consistent failure vocabulary.
+type Class int
+
+const (
+	// ClassSuccess is the class of successful attempts.
+	ClassSuccess Class = iota
+	// ClassTransientFailure is the class of failures expected to go away if retried.
+	ClassTransientFailure
+	// ClassThrottled is the class of failures caused by the dependency rejecting load, e.g., a 429.
+	ClassThrottled
+	// ClassTimeout is the class of failures caused by a deadline being exceeded.
+	ClassTimeout
+	// ClassPermanentFailure is the class of failures retrying cannot fix. It stops the retry sequence.
+	ClassPermanentFailure
+	// ClassAmbiguous is the class of failures after which it is unknown whether the operation took effect.
+	ClassAmbiguous
+)
+
+// classes is the number of classes of the taxonomy.
+const classes = int(ClassAmbiguous) + 1
+
+// String returns the name of the class, suitable as a metric label.
+//
+// Returns:
+//   - name: The name of the class.
+func (c Class) String() (name string) {
+	switch c {
+	case ClassSuccess:
+		name = "success"
+	case ClassTransientFailure:
+		name = "transient_failure"
+	case ClassThrottled:
+		name = "throttled"
+	case ClassTimeout:
+		name = "timeout"
+	case ClassPermanentFailure:
+		name = "permanent_failure"
+	case ClassAmbiguous:
+		name = "ambiguous"
+	default:
+		name = "unknown"
+	}
+
+	return
+}
+
+// Classifier is a function type that classifies the outcome of an attempt.
+//
+// Parameters:
+//   - err: The error of the attempt, or nil if it succeeded.
+//
+// Returns:
+//   - class: The class of the outcome.
+type Classifier func(err error) (class Class)
+
+// Classify is the default Classifier. It classifies nil as ClassSuccess, errors marked with Permanent
+// as ClassPermanentFailure, errors marked with Ambiguous and not with NotSent as ClassAmbiguous,
+// errors carrying a delay hinted by the server, through a RetryAfter() time.Duration method, as
+// ClassThrottled, deadline errors, including network timeouts, as ClassTimeout, and every other error
+// as ClassTransientFailure.
+//
+// Parameters:
+//   - err: The error of the attempt, or nil if it succeeded.
+//
+// Returns:
+//   - class: The class of the outcome.
+func Classify(err error) (class Class) {
+	var timeout interface{ Timeout() bool }
+
+	switch {
+	case err == nil:
+		class = ClassSuccess
+	case IsPermanent(err):
+		class = ClassPermanentFailure
+	case IsAmbiguous(err) && !IsNotSent(err):
+		class = ClassAmbiguous
+	case isHinted(err):
+		class = ClassThrottled
+	case errors.Is(err, context.DeadlineExceeded), errors.Is(err, os.ErrDeadlineExceeded),
+		errors.As(err, &timeout) && timeout.Timeout():
+		class = ClassTimeout
+	default:
+		class = ClassTransientFailure
+	}
+
+	return
+}
+
+// isHinted reports whether an error carries a positive delay hinted by the server.
+//
+// Parameters:
+//   - err: The error.
+//
+// Returns:
+//   - hinted: Whether err carries a hint.
+func isHinted(err error) (hinted bool) {
+	_, hinted = serverHint(err)
+
+	return
+}
+
+// ContextErrorTreatment determines how an operation returning context.Canceled or
+// context.DeadlineExceeded from its own sub-calls, e.g., an inner client with a short timeout, is
+// treated, while the context of the retry sequence itself is still live.
+type ContextErrorTreatment int
+
+const (
+	// ContextErrorsClassify defers to the Classifier, which retries them by default. This is the default.
+	ContextErrorsClassify ContextErrorTreatment = iota
+	// ContextErrorsRetry retries them, even if the Classifier classifies them as permanent.
+	ContextErrorsRetry
+	// ContextErrorsGiveUp stops the retry sequence, classifying them as permanent.
+	ContextErrorsGiveUp
+)
+
+// treatContextError applies a ContextErrorTreatment to the class of the outcome of an attempt.
+//
+// Parameters:
+//   - treatment: The ContextErrorTreatment.
+//   - err:       The error of the attempt.
+//   - class:     The class of the outcome, as classified by the Classifier.
+//
+// Returns:
+//   - treated: The class of the outcome once the treatment is applied.
+func treatContextError(treatment ContextErrorTreatment, err error, class Class) (treated Class) {
+	treated = class
+
+	if treatment == ContextErrorsClassify || !(errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded)) {
+		return
+	}
+
+	switch treatment {
+	case ContextErrorsRetry:
+		if class == ClassPermanentFailure {
+			treated = ClassTransientFailure
+		}
+	case ContextErrorsGiveUp:
+		treated = ClassPermanentFailure
+	}
+
+	return
+}
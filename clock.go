@@ -0,0 +1,81 @@
+package retrier
+
+import "time"
+
+// Clock is the source of time of a retry sequence, set through WithClock, so that tests can replace
+// the real passage of time with a virtual one, e.g., the fake clock of the retriertest package.
+type Clock interface {
+	// Now returns the current time.
+	Now() (now time.Time)
+	// Sleep blocks for a duration.
+	Sleep(d time.Duration)
+	// NewTimer returns a Timer firing once a duration has elapsed.
+	NewTimer(d time.Duration) (timer Timer)
+}
+
+// Timer is a single event of a Clock, as returned by Clock.NewTimer.
+type Timer interface {
+	// C returns the channel the time is sent on once the Timer fires.
+	C() (c <-chan time.Time)
+	// Stop prevents the Timer from firing, and reports whether it stopped it before it fired.
+	Stop() (stopped bool)
+}
+
+// systemClock is the Clock of the time package, the default.
+type systemClock struct{}
+
+// Now returns the current time, as time.Now.
+//
+// Returns:
+//   - now: The current time.
+func (systemClock) Now() (now time.Time) {
+	now = time.Now()
+
+	return
+}
+
+// Sleep blocks for d, as time.Sleep.
+//
+// Parameters:
+//   - d: The duration to sleep for.
+func (systemClock) Sleep(d time.Duration) {
+	time.Sleep(d)
+}
+
+// NewTimer returns a Timer firing once d has elapsed, backed by a time.Timer.
+//
+// Parameters:
+//   - d: The duration after which the Timer fires.
+//
+// Returns:
+//   - timer: The Timer.
+func (systemClock) NewTimer(d time.Duration) (timer Timer) {
+	timer = systemTimer{timer: time.NewTimer(d)}
+
+	return
+}
+
+// systemTimer is the Timer of the systemClock.
+type systemTimer struct {
+	timer *time.Timer
+}
+
+// C returns the channel of the underlying time.Timer.
+//
+// Returns:
+//   - c: The channel the time is sent on once the Timer fires.
+func (t systemTimer) C() (c <-chan time.Time) {
+	c = t.timer.C
+
+	return
+}
+
+// Stop stops the underlying time.Timer.
+//
+// Returns:
+//   - stopped: Whether the Timer was stopped before it fired.
+func (t systemTimer) Stop() (stopped bool) {
+	stopped = t.timer.Stop()
+
+	return
+}
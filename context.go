@@ -0,0 +1,125 @@
+package retrier
+
+import (
+	"context"
+	"slices"
+)
+
+// optionsKey is the context key under which ContextWithOptions stores the options.
+type optionsKey struct{}
+
+// ContextWithOptions returns a copy of ctx carrying retry options, appended to the ones already
+// carried by ctx. The options are picked up by integrations, such as HTTP transports, gRPC
+// interceptors, or database wrappers, that have no options configured on them, so that retries can be
+// tuned per request through the standard integration points.
+//
+// Parameters:
+//   - ctx:  The parent context.
+//   - opts: The retry options to carry.
+//
+// Returns:
+//   - optionsCtx: A context carrying the options.
+//
+// Example:
+//
+//	ctx = retrier.ContextWithOptions(ctx, retrier.WithMaxRetries(1))
+//	// Requests sent with ctx through a retrying integration are attempted once.
+func ContextWithOptions(ctx context.Context, opts ...Option) (optionsCtx context.Context) {
+	carried := OptionsFromContext(ctx)
+
+	optionsCtx = context.WithValue(ctx, optionsKey{}, append(slices.Clip(carried), opts...))
+
+	return
+}
+
+// OptionsFromContext returns the retry options carried by ctx, set through ContextWithOptions.
+//
+// Parameters:
+//   - ctx: The context carrying the options.
+//
+// Returns:
+//   - opts: The options carried by ctx, or nil if it carries none.
+func OptionsFromContext(ctx context.Context) (opts []Option) {
+	opts, _ = ctx.Value(optionsKey{}).([]Option)
+
+	return
+}
+
+// noRetryKey is the context key under which WithNoRetry marks a context.
+type noRetryKey struct{}
+
+// WithNoRetry returns a copy of ctx marking the request it carries as not to be retried. Every retry
+// sequence run with the context, by Retry and RetryWithData directly or by any integration built on
+// them, makes a single attempt, so that a request marked anywhere in the stack is never retried by any
+// of its layers.
+//
+// Parameters:
+//   - ctx: The parent context.
+//
+// Returns:
+//   - noRetryCtx: A context marking its requests as not to be retried.
+//
+// Example:
+//
+//	ctx = retrier.WithNoRetry(ctx)
+//	// Every retrying layer handling ctx attempts its operation once.
+func WithNoRetry(ctx context.Context) (noRetryCtx context.Context) {
+	noRetryCtx = context.WithValue(ctx, noRetryKey{}, true)
+
+	return
+}
+
+// WithMaxOneAttempt is WithNoRetry, spelled in terms of attempts.
+//
+// Parameters:
+//   - ctx: The parent context.
+//
+// Returns:
+//   - oneAttemptCtx: A context limiting its requests to a single attempt.
+func WithMaxOneAttempt(ctx context.Context) (oneAttemptCtx context.Context) {
+	oneAttemptCtx = WithNoRetry(ctx)
+
+	return
+}
+
+// NoRetry reports whether ctx was marked through WithNoRetry or WithMaxOneAttempt.
+//
+// Parameters:
+//   - ctx: The context.
+//
+// Returns:
+//   - marked: Whether the requests carried by ctx are not to be retried.
+func NoRetry(ctx context.Context) (marked bool) {
+	marked, _ = ctx.Value(noRetryKey{}).(bool)
+
+	return
+}
+
+// attemptKey is the context key under which the retry loop stores the AttemptMeta of an attempt.
+type attemptKey struct{}
+
+// AttemptFromContext returns the metadata of the attempt a context-aware operation, retried through
+// RetryCtx or RetryCtxWithData, is called for.
+//
+// Parameters:
+//   - ctx: The context the operation is called with.
+//
+// Returns:
+//   - meta: The metadata of the attempt.
+//   - ok:   Whether ctx carries the metadata of an attempt.
+//
+// Example:
+//
+//	err := retrier.RetryCtx(ctx, func(ctx context.Context) error {
+//	    endpoint := primary
+//	    if meta, _ := retrier.AttemptFromContext(ctx); meta.Number > 0 {
+//	        endpoint = secondary
+//	    }
+//
+//	    return call(ctx, endpoint)
+//	})
+func AttemptFromContext(ctx context.Context) (meta AttemptMeta, ok bool) {
+	meta, ok = ctx.Value(attemptKey{}).(AttemptMeta)
+
+	return
+}
@@ -0,0 +1,88 @@
+package retrier
+
+import "context"
+
+// Retrier is a retry policy resolved once from options and reused across calls, so that hot paths
+// making thousands of calls do not resolve and validate the same options on every one of them.
+//
+// A Retrier is safe for concurrent use, except for the destinations of WithStats and WithResultMeta,
+// which are shared by every call and should only be used by Retriers serving one call at a time.
+type Retrier struct {
+	cfg *Configuration
+	err error
+}
+
+// New returns a Retrier applying the provided options to every retry sequence it runs.
+//
+// Parameters:
+//   - opts: The configuration options of the policy.
+//
+// Returns:
+//   - r: The new Retrier. If the options cannot be resolved, e.g., with WithStrict, every call returns
+//     the configuration error, also reported by Err.
+//
+// Example:
+//
+//	r := retrier.New(retrier.WithMaxRetries(5), retrier.WithBackoff(backoff.ExponentialWithFullJitter()))
+//	err := r.Do(ctx, operation)
+func New(opts ...Option) (r *Retrier) {
+	r = &Retrier{}
+
+	r.cfg, r.err = NewValidated(opts...)
+
+	return
+}
+
+// Err returns the error resolving the options of the Retrier, if any.
+//
+// Returns:
+//   - err: The configuration error, or nil if the options were resolved.
+func (r *Retrier) Err() (err error) {
+	err = r.err
+
+	return
+}
+
+// Do runs the retry sequence of an operation, as Retry does, with the policy of the Retrier.
+//
+// Parameters:
+//   - ctx:       A context to control the lifetime of the retry sequence.
+//   - operation: The operation to be retried.
+//
+// Returns:
+//   - err: The error of the retry sequence, as returned by Retry.
+func (r *Retrier) Do(ctx context.Context, operation Operation) (err error) {
+	_, err = DoWithData(ctx, r, operation.withEmptyData())
+
+	return
+}
+
+// DoWithData runs the retry sequence of an operation returning data, as RetryWithData does, with the
+// policy of a Retrier. It is a function rather than a method as methods cannot have type parameters.
+//
+// Parameters:
+//   - ctx:       A context to control the lifetime of the retry sequence.
+//   - r:         The Retrier whose policy governs the retry sequence.
+//   - operation: The operation to be retried, which returns a value of type T and an error.
+//
+// Returns:
+//   - result: The result of the operation if it succeeds within the allowed retry attempts.
+//   - err:    The error of the retry sequence, as returned by RetryWithData.
+//
+// Example:
+//
+//	user, err := retrier.DoWithData(ctx, r, fetchUser)
+func DoWithData[T any](ctx context.Context, r *Retrier, operation OperationWithData[T]) (result T, err error) {
+	if r.err != nil {
+		err = r.err
+
+		return
+	}
+
+	// The retry sequence adjusts its own copy of the policy.
+	cfg := *r.cfg
+
+	result, err = retry(ctx, &cfg, operation.withContext(), false)
+
+	return
+}
@@ -0,0 +1,167 @@
+package retrier
+
+import (
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.source.hueristiq.com/retrier/backoff"
+	"go.source.hueristiq.com/retrier/policy"
+)
+
+// backoffNames maps the names of the backoff strategies accepted by FromEnv to the named strategy and
+// jitter of a policy.Policy.
+var backoffNames = map[string][2]string{
+	"exponential":                     {policy.StrategyExponential, policy.JitterNone},
+	"exponential-full-jitter":         {policy.StrategyExponential, policy.JitterFull},
+	"exponential-equal-jitter":        {policy.StrategyExponential, policy.JitterEqual},
+	"exponential-decorrelated-jitter": {policy.StrategyExponential, policy.JitterDecorrelated},
+	"linear":                          {policy.StrategyLinear, policy.JitterNone},
+	"linear-full-jitter":              {policy.StrategyLinear, policy.JitterFull},
+	"linear-equal-jitter":             {policy.StrategyLinear, policy.JitterEqual},
+	"constant":                        {policy.StrategyConstant, policy.JitterNone},
+}
+
+// FromEnv returns an option configuring the retry sequence from environment variables, so that
+// deployments can tune retries without recompiling. The variables are read when FromEnv is called,
+// and the unset ones leave the Configuration unchanged:
+//
+//   - PREFIX_RETRY_MAX: The maximum number of attempts, as for WithMaxRetries.
+//   - PREFIX_RETRY_WAIT_MIN: The minimum delay, e.g., "100ms", as for WithMinDelay.
+//   - PREFIX_RETRY_WAIT_MAX: The maximum delay, e.g., "10s", as for WithMaxDelay.
+//   - PREFIX_RETRY_MAX_ELAPSED: The maximum elapsed time, e.g., "1m", as for WithMaxElapsedTime.
+//   - PREFIX_BACKOFF: The backoff strategy: exponential, exponential-full-jitter,
+//     exponential-equal-jitter, exponential-decorrelated-jitter, linear, linear-full-jitter,
+//     linear-equal-jitter, constant, or a name registered with backoff.Register. The linear strategies
+//     grow by the minimum delay.
+//
+// Malformed values are reported as OptionErrors, which WithStrict turns into an error.
+//
+// Parameters:
+//   - prefix: The prefix of the variables, joined to their names with an underscore. An empty prefix
+//     reads the variables without one, e.g., RETRY_MAX.
+//
+// Returns:
+//   - Option: A functional option that modifies the Configuration according to the variables.
+//
+// Example:
+//
+//	// With PAYMENTS_RETRY_MAX=5 and PAYMENTS_BACKOFF=exponential-full-jitter:
+//	err := retrier.Retry(ctx, operation, retrier.WithMinDelay(time.Second), retrier.FromEnv("PAYMENTS"))
+func FromEnv(prefix string) Option {
+	if prefix != "" && !strings.HasSuffix(prefix, "_") {
+		prefix += "_"
+	}
+
+	lookup := func(name string) (value string, ok bool) {
+		value, ok = os.LookupEnv(prefix + name)
+
+		return
+	}
+
+	var opts []Option
+
+	if value, ok := lookup("RETRY_MAX"); ok {
+		opts = append(opts, envInt(prefix+"RETRY_MAX", value, WithMaxRetries))
+	}
+
+	durations := []struct {
+		name   string
+		option func(time.Duration) Option
+	}{
+		{"RETRY_WAIT_MIN", WithMinDelay},
+		{"RETRY_WAIT_MAX", WithMaxDelay},
+		{"RETRY_MAX_ELAPSED", WithMaxElapsedTime},
+	}
+
+	for _, duration := range durations {
+		if value, ok := lookup(duration.name); ok {
+			opts = append(opts, envDuration(prefix+duration.name, value, duration.option))
+		}
+	}
+
+	// The backoff strategy is built last, as the linear strategies grow by the minimum delay.
+	if value, ok := lookup("BACKOFF"); ok {
+		opts = append(opts, envBackoff(prefix+"BACKOFF", value))
+	}
+
+	return Options(opts...)
+}
+
+// envInt returns an option applying an integer environment variable, or rejecting it if it is malformed.
+//
+// Parameters:
+//   - name:   The name of the variable.
+//   - value:  The value of the variable.
+//   - option: The option the integer is passed to.
+//
+// Returns:
+//   - Option: The option applying the variable.
+func envInt(name, value string, option func(int) Option) Option {
+	n, err := strconv.Atoi(strings.TrimSpace(value))
+	if err != nil {
+		return func(c *Configuration) {
+			c.reject("FromEnv", name+": invalid integer "+strconv.Quote(value))
+		}
+	}
+
+	return option(n)
+}
+
+// envDuration returns an option applying a duration environment variable, or rejecting it if it is
+// malformed.
+//
+// Parameters:
+//   - name:   The name of the variable.
+//   - value:  The value of the variable.
+//   - option: The option the duration is passed to.
+//
+// Returns:
+//   - Option: The option applying the variable.
+func envDuration(name, value string, option func(time.Duration) Option) Option {
+	d, err := time.ParseDuration(strings.TrimSpace(value))
+	if err != nil {
+		return func(c *Configuration) {
+			c.reject("FromEnv", name+": invalid duration "+strconv.Quote(value))
+		}
+	}
+
+	return option(d)
+}
+
+// envBackoff returns an option setting the backoff strategy named by an environment variable, or
+// rejecting it if it is unknown.
+//
+// Parameters:
+//   - name:  The name of the variable.
+//   - value: The value of the variable.
+//
+// Returns:
+//   - Option: The option applying the variable.
+func envBackoff(name, value string) Option {
+	return func(c *Configuration) {
+		named, ok := backoffNames[strings.ToLower(strings.TrimSpace(value))]
+		if !ok {
+			// Fall back to the custom strategies registered with backoff.Register.
+			if strategy, registered := backoff.Get(strings.TrimSpace(value)); registered {
+				c.backoff = strategy
+
+				return
+			}
+
+			c.reject("FromEnv", name+": unknown backoff strategy "+strconv.Quote(value))
+
+			return
+		}
+
+		strategy, err := policy.Policy{Strategy: named[0], Jitter: named[1], MinDelay: c.minDelay}.BuildBackoff()
+		if err != nil {
+			c.reject("FromEnv", name+": "+err.Error())
+
+			return
+		}
+
+		c.backoff = strategy
+	}
+}
@@ -0,0 +1,338 @@
+package retrier
+
+import (
+	"errors"
+	"net"
+	"time"
+)
+
+// ambiguousError marks an error as an ambiguous failure.
+type ambiguousError struct {
+	err error
+}
+
+// Error implements the error interface by returning the wrapped error's message.
+//
+// Returns:
+//   - message: The wrapped error's message.
+func (e *ambiguousError) Error() (message string) {
+	message = e.err.Error()
+
+	return
+}
+
+// Unwrap returns the wrapped error.
+//
+// Returns:
+//   - err: The wrapped error.
+func (e *ambiguousError) Unwrap() (err error) {
+	err = e.err
+
+	return
+}
+
+// Ambiguous wraps an error to mark it as an ambiguous failure, i.e., a failure after which it is
+// unknown whether the operation took effect, such as a timeout after a request was sent. Retrying an
+// ambiguous failure replays the operation, so retries of non-idempotent operations (see WithIdempotent)
+// stop at the first ambiguous failure.
+//
+// Parameters:
+//   - err: The error to mark. A nil error is returned as is.
+//
+// Returns:
+//   - ambiguous: The marked error, which unwraps to err.
+//
+// Example:
+//
+//	if sent && errors.Is(err, os.ErrDeadlineExceeded) {
+//	    return retrier.Ambiguous(err)
+//	}
+func Ambiguous(err error) (ambiguous error) {
+	if err == nil {
+		return
+	}
+
+	ambiguous = &ambiguousError{err: err}
+
+	return
+}
+
+// IsAmbiguous reports whether an error, or any error it wraps, was marked with Ambiguous.
+//
+// Parameters:
+//   - err: The error to inspect.
+//
+// Returns:
+//   - ambiguous: Whether err is an ambiguous failure.
+func IsAmbiguous(err error) (ambiguous bool) {
+	var target *ambiguousError
+
+	ambiguous = errors.As(err, &target)
+
+	return
+}
+
+// permanentError marks an error as a permanent failure.
+type permanentError struct {
+	err error
+}
+
+// Error implements the error interface by returning the wrapped error's message.
+//
+// Returns:
+//   - message: The wrapped error's message.
+func (e *permanentError) Error() (message string) {
+	message = e.err.Error()
+
+	return
+}
+
+// Unwrap returns the wrapped error.
+//
+// Returns:
+//   - err: The wrapped error.
+func (e *permanentError) Unwrap() (err error) {
+	err = e.err
+
+	return
+}
+
+// Permanent wraps an error to mark it as a permanent failure, which retrying cannot fix, such as a
+// validation error. The retry sequence stops at the first permanent failure and returns the error
+// it wraps, regardless of the Classifier and of WithRetryIf.
+//
+// Parameters:
+//   - err: The error to mark. A nil error is returned as is.
+//
+// Returns:
+//   - permanent: The marked error, which unwraps to err.
+//
+// Example:
+//
+//	if res.StatusCode == http.StatusBadRequest {
+//	    return retrier.Permanent(ErrBadRequest)
+//	}
+func Permanent(err error) (permanent error) {
+	if err == nil {
+		return
+	}
+
+	permanent = &permanentError{err: err}
+
+	return
+}
+
+// IsPermanent reports whether an error, or any error it wraps, was marked with Permanent.
+//
+// Parameters:
+//   - err: The error to inspect.
+//
+// Returns:
+//   - permanent: Whether err is a permanent failure.
+func IsPermanent(err error) (permanent bool) {
+	var target *permanentError
+
+	permanent = errors.As(err, &target)
+
+	return
+}
+
+// unwrapPermanent returns the error marked with Permanent, if err is one, or err otherwise.
+//
+// Parameters:
+//   - err: The error to unwrap.
+//
+// Returns:
+//   - unwrapped: The error wrapped by Permanent, or err.
+func unwrapPermanent(err error) (unwrapped error) {
+	unwrapped = err
+
+	if permanent, ok := err.(*permanentError); ok { //nolint:errorlint // Only the outermost marker is removed, so that wrapping context is kept.
+		unwrapped = permanent.err
+	}
+
+	return
+}
+
+// notSentError marks an error as a failure that happened before the operation was sent.
+type notSentError struct {
+	err error
+}
+
+// Error implements the error interface by returning the wrapped error's message.
+//
+// Returns:
+//   - message: The wrapped error's message.
+func (e *notSentError) Error() (message string) {
+	message = e.err.Error()
+
+	return
+}
+
+// Unwrap returns the wrapped error.
+//
+// Returns:
+//   - err: The wrapped error.
+func (e *notSentError) Unwrap() (err error) {
+	err = e.err
+
+	return
+}
+
+// NotSent wraps an error to mark it as a failure that definitely happened before the operation was
+// sent, such as a refused connection or a failed DNS lookup. Unlike ambiguous failures, these are
+// always safe to retry, even for non-idempotent operations.
+//
+// Parameters:
+//   - err: The error to mark. A nil error is returned as is.
+//
+// Returns:
+//   - notSent: The marked error, which unwraps to err.
+//
+// Example:
+//
+//	conn, err := net.Dial("tcp", addr)
+//	if err != nil {
+//	    return retrier.NotSent(err)
+//	}
+func NotSent(err error) (notSent error) {
+	if err == nil {
+		return
+	}
+
+	notSent = &notSentError{err: err}
+
+	return
+}
+
+// IsNotSent reports whether an error, or any error it wraps, was marked with NotSent.
+//
+// Parameters:
+//   - err: The error to inspect.
+//
+// Returns:
+//   - notSent: Whether err is a failure that happened before the operation was sent.
+func IsNotSent(err error) (notSent bool) {
+	var target *notSentError
+
+	notSent = errors.As(err, &target)
+
+	return
+}
+
+// ClassifyNetworkError marks network errors whose outcome can be determined from the error itself:
+// failures to dial or resolve are marked with NotSent, while other network errors, which may happen
+// after the request was written, are marked with Ambiguous. Errors that are already marked, and
+// errors that are not network errors, are returned unchanged.
+//
+// Parameters:
+//   - err: The error to classify.
+//
+// Returns:
+//   - classified: The classified error, which unwraps to err.
+//
+// Example:
+//
+//	resp, err := client.Do(req)
+//	if err != nil {
+//	    return retrier.ClassifyNetworkError(err)
+//	}
+func ClassifyNetworkError(err error) (classified error) {
+	classified = err
+
+	if err == nil || IsNotSent(err) || IsAmbiguous(err) {
+		return
+	}
+
+	var (
+		dnsErr *net.DNSError
+		opErr  *net.OpError
+	)
+
+	switch {
+	case errors.As(err, &dnsErr):
+		classified = NotSent(err)
+	case errors.As(err, &opErr):
+		if opErr.Op == "dial" {
+			classified = NotSent(err)
+
+			return
+		}
+
+		classified = Ambiguous(err)
+	}
+
+	return
+}
+
+// hinter is implemented by errors carrying the delay a server asked clients to wait for before
+// retrying, e.g., from a Retry-After header.
+type hinter interface {
+	RetryAfter() (delay time.Duration)
+}
+
+// serverHint returns the delay hinted by an error, or any error it wraps, implementing RetryAfter.
+//
+// Parameters:
+//   - err: The error of the failed attempt.
+//
+// Returns:
+//   - hint: The hinted delay.
+//   - ok:   Whether err carries a positive hint.
+func serverHint(err error) (hint time.Duration, ok bool) {
+	var h hinter
+
+	if errors.As(err, &h) {
+		hint = h.RetryAfter()
+		ok = hint > 0
+	}
+
+	return
+}
+
+// RetryError is the error of a retry sequence that gave up without a permanent failure, e.g., out of
+// attempts, of elapsed time through WithMaxElapsedTime, of time within the SLO, or of retry budget,
+// describing how it did, so that callers and log pipelines can extract retry diagnostics without
+// parsing messages. Retry sequences stopped by their context or giving up on a permanent failure
+// return their error as is. Its message is the message of the last error, which errors.Is and
+// errors.As match through Unwrap, prefixed with the identifier of the retry sequence.
+//
+// Fields:
+//   - Attempts:   The number of attempts of the retry sequence.
+//   - TotalDelay: The cumulative time spent waiting between attempts.
+//   - Elapsed:    The time the retry sequence took.
+//   - LastErr:    The error the retry sequence gave up with, usually that of the last attempt.
+//   - SequenceID: The unique identifier of the retry sequence, as reported to its notifiers and in its
+//     Stats, so that the error can be correlated with the logs of the retry sequence.
+type RetryError struct {
+	Attempts   int
+	TotalDelay time.Duration
+	Elapsed    time.Duration
+	LastErr    error
+	SequenceID string
+}
+
+// Error implements the error interface by returning the last error's message, prefixed with the
+// identifier of the retry sequence, if any.
+//
+// Returns:
+//   - message: The last error's message.
+func (e *RetryError) Error() (message string) {
+	message = e.LastErr.Error()
+
+	if e.SequenceID != "" {
+		message = "retry sequence " + e.SequenceID + ": " + message
+	}
+
+	return
+}
+
+// Unwrap returns the error of the last attempt.
+//
+// Returns:
+//   - err: The last error.
+func (e *RetryError) Unwrap() (err error) {
+	err = e.LastErr
+
+	return
+}
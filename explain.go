@@ -0,0 +1,97 @@
+package retrier
+
+import (
+	"time"
+
+	"go.source.hueristiq.com/retrier/backoff"
+)
+
+// Explanation describes the decision the policy of a Retrier takes after a failed attempt, as
+// returned by Retrier.Explain.
+//
+// Fields:
+//   - Class:    The class of the failure, as classified by the Classifier, WithContextErrors, WithRetryIf,
+//     and Permanent.
+//   - Retry:    Whether the retry sequence retries the operation.
+//   - Reason:   Why the retry sequence retries or gives up.
+//   - Strategy: The backoff strategy of the policy.
+//   - Delay:    The delay before the next attempt if it is retried. Strategies applying jitter return a
+//     sample of their delays.
+type Explanation struct {
+	Class    Class
+	Retry    bool
+	Reason   string
+	Strategy backoff.StrategyInfo
+	Delay    time.Duration
+}
+
+// Explain returns the decision the policy of the Retrier takes after an attempt failed with err,
+// without executing anything, so that the composition of a policy can be unit-tested and debugged.
+// The sequence's context is assumed live, and a Classifier set through WithDynamicClassifier is not
+// consulted, as both depend on the context of a call.
+//
+// Parameters:
+//   - err:     The error of the failed attempt.
+//   - attempt: The zero-based number of the failed attempt within the retry sequence.
+//
+// Returns:
+//   - explanation: The decision of the policy.
+//
+// Example:
+//
+//	explanation := r.Explain(retrier.Permanent(err), 0)
+//	// explanation.Retry is false and explanation.Reason is "permanent failure".
+func (r *Retrier) Explain(err error, attempt int) (explanation Explanation) {
+	if r.err != nil {
+		explanation.Reason = "invalid configuration: " + r.err.Error()
+
+		return
+	}
+
+	cfg := r.cfg
+
+	explanation.Strategy = cfg.backoff.Describe()
+	explanation.Class = treatContextError(cfg.contextErrors, err, cfg.classifier(err))
+
+	if IsPermanent(err) || err != nil && cfg.retryIf != nil && !cfg.retryIf(err) {
+		explanation.Class = ClassPermanentFailure
+	}
+
+	next := attempt + 1
+
+	switch {
+	case err == nil:
+		explanation.Reason = "success"
+	case explanation.Class == ClassPermanentFailure:
+		explanation.Reason = "permanent failure"
+	case !cfg.retriesAmbiguous() && IsAmbiguous(err) && !IsNotSent(err):
+		explanation.Reason = "ambiguous failure of a non-idempotent operation"
+	case cfg.maxRetries >= 0 && next >= cfg.maxRetries:
+		explanation.Reason = "attempts exhausted"
+	case next == cfg.softGiveUp:
+		explanation.Reason = "soft give-up"
+	default:
+		explanation.Retry, explanation.Reason = true, "retryable failure"
+	}
+
+	if !explanation.Retry {
+		return
+	}
+
+	delay, unresolved := cfg.resolveDelay(cfg.backoff(cfg.minDelay, cfg.maxDelay, attempt+cfg.backoffAttemptOffset), err)
+	if unresolved != nil {
+		explanation.Retry, explanation.Reason = false, unresolved.Error()
+
+		return
+	}
+
+	if cfg.serverHints {
+		if hint, ok := cfg.hint(err); ok {
+			delay, explanation.Reason = hint, "retryable failure, delay hinted by the server"
+		}
+	}
+
+	explanation.Delay = delay
+
+	return
+}
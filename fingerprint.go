@@ -0,0 +1,58 @@
+package retrier
+
+import (
+	"fmt"
+	"strconv"
+)
+
+// Fingerprint returns a fingerprint of an error made of its type and message, so that failures
+// returning the same error are grouped by WithFingerprint. It is the fingerprint function used by
+// WithFingerprint when none is provided.
+//
+// Parameters:
+//   - err: The error to fingerprint.
+//
+// Returns:
+//   - fingerprint: The type and message of err.
+func Fingerprint(err error) (fingerprint string) {
+	if err == nil {
+		return
+	}
+
+	fingerprint = fmt.Sprintf("%T: %s", err, err.Error())
+
+	return
+}
+
+// RepeatedError is passed to the notifiers, with WithFingerprint, in place of the failures repeating
+// the previous one, so that they can report "same error x4" instead of four identical lines.
+//
+// Fields:
+//   - Err:         The last of the repeated errors.
+//   - Fingerprint: The fingerprint shared by the repeated errors.
+//   - Repeats:     The number of failures repeating the first one of the group, which was notified as usual.
+type RepeatedError struct {
+	Err         error
+	Fingerprint string
+	Repeats     int
+}
+
+// Error implements the error interface.
+//
+// Returns:
+//   - message: The message of the last repeated error and the number of repeats.
+func (e *RepeatedError) Error() (message string) {
+	message = e.Err.Error() + " (repeated " + strconv.Itoa(e.Repeats) + " times)"
+
+	return
+}
+
+// Unwrap returns the last of the repeated errors.
+//
+// Returns:
+//   - err: The last of the repeated errors.
+func (e *RepeatedError) Unwrap() (err error) {
+	err = e.Err
+
+	return
+}
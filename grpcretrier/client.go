@@ -0,0 +1,57 @@
+package grpcretrier
+
+import (
+	"slices"
+
+	"go.source.hueristiq.com/retrier"
+)
+
+// Classifier returns a retrier.Classifier for the errors of gRPC calls, retrying the calls failing
+// with one of the retryable status codes, UNAVAILABLE and RESOURCE_EXHAUSTED by default, and giving
+// up on the others. RESOURCE_EXHAUSTED is classified as throttled and DEADLINE_EXCEEDED as a timeout.
+//
+// As the package does not depend on gRPC, the status code of an error is read through a function,
+// typically wrapping status.Code. The interceptors of the grpcretrier/interceptor module are built
+// on it:
+//
+//	classifier := grpcretrier.Classifier(func(err error) uint32 { return uint32(status.Code(err)) })
+//
+// Parameters:
+//   - code:      The function returning the value of the gRPC status code of an error.
+//   - retryable: The values of the retryable status codes. None stands for UNAVAILABLE and
+//     RESOURCE_EXHAUSTED.
+//
+// Returns:
+//   - classifier: The Classifier of the errors of gRPC calls.
+func Classifier(code func(err error) uint32, retryable ...uint32) (classifier retrier.Classifier) {
+	if len(retryable) == 0 {
+		retryable = []uint32{CodeUnavailable, CodeResourceExhausted}
+	} else {
+		retryable = slices.Clone(retryable)
+	}
+
+	classifier = func(err error) (class retrier.Class) {
+		if err == nil {
+			class = retrier.ClassSuccess
+
+			return
+		}
+
+		c := code(err)
+
+		switch {
+		case !slices.Contains(retryable, c):
+			class = retrier.ClassPermanentFailure
+		case c == CodeResourceExhausted:
+			class = retrier.ClassThrottled
+		case c == CodeDeadlineExceeded:
+			class = retrier.ClassTimeout
+		default:
+			class = retrier.ClassTransientFailure
+		}
+
+		return
+	}
+
+	return
+}
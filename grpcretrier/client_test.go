@@ -0,0 +1,64 @@
+package grpcretrier_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.source.hueristiq.com/retrier"
+	"go.source.hueristiq.com/retrier/grpcretrier"
+)
+
+var errInvalidArgument = errors.New("invalid argument")
+
+// statusError stands for the error of a gRPC call with a status code.
+type statusError struct {
+	code uint32
+}
+
+func (e *statusError) Error() string {
+	return "rpc error"
+}
+
+func codeOf(err error) uint32 {
+	var status *statusError
+
+	if errors.As(err, &status) {
+		return status.code
+	}
+
+	return 2
+}
+
+func TestClassifier(t *testing.T) {
+	t.Parallel()
+
+	classifier := grpcretrier.Classifier(codeOf)
+
+	assert.Equal(t, retrier.ClassSuccess, classifier(nil), "Expected a successful call")
+	assert.Equal(t, retrier.ClassTransientFailure, classifier(&statusError{code: grpcretrier.CodeUnavailable}), "Expected UNAVAILABLE to be retried")
+	assert.Equal(t, retrier.ClassThrottled, classifier(&statusError{code: grpcretrier.CodeResourceExhausted}), "Expected RESOURCE_EXHAUSTED to be throttled")
+	assert.Equal(t, retrier.ClassPermanentFailure, classifier(&statusError{code: grpcretrier.CodeDeadlineExceeded}), "Expected DEADLINE_EXCEEDED not to be retried by default")
+	assert.Equal(t, retrier.ClassPermanentFailure, classifier(errInvalidArgument), "Expected other codes not to be retried")
+
+	classifier = grpcretrier.Classifier(codeOf, grpcretrier.CodeDeadlineExceeded)
+
+	assert.Equal(t, retrier.ClassTimeout, classifier(&statusError{code: grpcretrier.CodeDeadlineExceeded}), "Expected DEADLINE_EXCEEDED to be a timeout")
+
+	calls := 0
+
+	err := retrier.RetryCtx(context.Background(), func(context.Context) error {
+		calls++
+
+		if calls == 1 {
+			return &statusError{code: grpcretrier.CodeUnavailable}
+		}
+
+		return errInvalidArgument
+	}, retrier.WithMinDelay(0), retrier.WithClassifier(grpcretrier.Classifier(codeOf)))
+
+	require.ErrorIs(t, err, errInvalidArgument, "Expected the call to give up on a non-retryable code")
+	assert.Equal(t, 2, calls, "Expected a retry after UNAVAILABLE only")
+}
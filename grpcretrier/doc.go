@@ -0,0 +1,19 @@
+// Package grpcretrier integrates the retrier with gRPC.
+//
+// On the server side, Pushback computes the delay a server asks clients to wait for before retrying
+// a call rejected with RESOURCE_EXHAUSTED or UNAVAILABLE, from the retry policy of the clients and the
+// attempt number announced by the gRPC client in the grpc-previous-rpc-attempts header. The delay is
+// attached to the rejected call in the grpc-retry-pushback-ms trailer, which gRPC clients with a
+// retry policy honor natively:
+//
+//	if pushback, ok := grpcretrier.Pushback(code, md.Get(grpcretrier.PreviousAttemptsHeader), p); ok {
+//	    _ = grpc.SetTrailer(ctx, metadata.Pairs(grpcretrier.PushbackTrailer, grpcretrier.FormatPushback(pushback)))
+//	}
+//
+// On the client side, Classifier classifies the errors of gRPC calls by status code, retrying
+// UNAVAILABLE and RESOURCE_EXHAUSTED by default, for client interceptors built on retrier.RetryCtx.
+//
+// The package works on the plain values of gRPC codes and metadata, so that it does not depend on gRPC.
+// The unary and stream client interceptors, which do, live in the grpcretrier/interceptor package, a
+// module of its own.
+package grpcretrier
@@ -0,0 +1,22 @@
+// Package interceptor provides gRPC client interceptors retrying calls with the retrier.
+//
+// UnaryClientInterceptor retries unary calls failing with a retryable status code, UNAVAILABLE and
+// RESOURCE_EXHAUSTED by default or those set with WithRetryableCodes, through grpcretrier.Classifier,
+// and waits for the delay a server asked for in a google.rpc.RetryInfo detail of the status instead
+// of the backoff delay:
+//
+//	conn, err := grpc.NewClient(target,
+//	    grpc.WithUnaryInterceptor(interceptor.UnaryClientInterceptor(retrier.WithMaxRetries(5))),
+//	    grpc.WithStreamInterceptor(interceptor.StreamClientInterceptor(retrier.WithMaxRetries(5))),
+//	)
+//
+// StreamClientInterceptor retries establishing streams and, for the streams whose client sends a
+// single message, replays the message on a new stream until the first response is received.
+//
+// The options of the interceptors are overridden per call with the WithRetryOptions call option, or
+// through the context of the call with retrier.ContextWithOptions:
+//
+//	err := conn.Invoke(ctx, method, req, reply, interceptor.WithRetryOptions(retrier.WithMaxRetries(0)))
+//
+// The package lives in its own module, so that the retrier module itself does not depend on gRPC.
+package interceptor
@@ -0,0 +1,22 @@
+module go.source.hueristiq.com/retrier/grpcretrier/interceptor
+
+go 1.23.3
+
+require (
+	github.com/stretchr/testify v1.10.0
+	go.source.hueristiq.com/retrier v0.0.0
+	google.golang.org/genproto/googleapis/rpc v0.0.0-20250707201910-8d1bb00bc6a7
+	google.golang.org/grpc v1.75.1
+	google.golang.org/protobuf v1.36.6
+)
+
+require (
+	github.com/davecgh/go-spew v1.1.1 // indirect
+	github.com/pmezard/go-difflib v1.0.0 // indirect
+	golang.org/x/net v0.41.0 // indirect
+	golang.org/x/sys v0.33.0 // indirect
+	golang.org/x/text v0.26.0 // indirect
+	gopkg.in/yaml.v3 v3.0.1 // indirect
+)
+
+replace go.source.hueristiq.com/retrier => ../..
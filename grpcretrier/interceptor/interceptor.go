@@ -0,0 +1,342 @@
+package interceptor
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"go.source.hueristiq.com/retrier"
+	"go.source.hueristiq.com/retrier/grpcretrier"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// callOption is the grpc.CallOption carrying the retry options of a call, set with WithRetryOptions.
+type callOption struct {
+	grpc.EmptyCallOption
+
+	opts []retrier.Option
+}
+
+// WithRetryOptions returns a grpc.CallOption overriding the retry options of the interceptors for a
+// single call. The options are applied after those of the interceptor and those carried by the
+// context of the call.
+//
+// Parameters:
+//   - opts: The retry options of the call.
+//
+// Returns:
+//   - option: The call option carrying opts.
+//
+// Example:
+//
+//	err := client.Get(ctx, req, interceptor.WithRetryOptions(retrier.WithMaxRetries(1)))
+func WithRetryOptions(opts ...retrier.Option) (option grpc.CallOption) {
+	option = callOption{opts: opts}
+
+	return
+}
+
+// WithRetryableCodes returns a retry option retrying the calls failing with one of the given status
+// codes, instead of UNAVAILABLE and RESOURCE_EXHAUSTED, and giving up on the others. It is passed to
+// the interceptors, carried by the context of a call, or set per call with WithRetryOptions.
+//
+// Parameters:
+//   - retryable: The retryable status codes. None stands for UNAVAILABLE and RESOURCE_EXHAUSTED.
+//
+// Returns:
+//   - option: The retry option classifying the errors of the calls by status code.
+//
+// Example:
+//
+//	interceptor.UnaryClientInterceptor(interceptor.WithRetryableCodes(codes.Unavailable, codes.Aborted))
+func WithRetryableCodes(retryable ...codes.Code) (option retrier.Option) {
+	values := make([]uint32, 0, len(retryable))
+
+	for _, c := range retryable {
+		values = append(values, uint32(c))
+	}
+
+	option = retrier.WithClassifier(grpcretrier.Classifier(code, values...))
+
+	return
+}
+
+// RetryInfo returns the delay a server asked the client to wait for before retrying a call, carried
+// by a google.rpc.RetryInfo detail of the status of the error of the call.
+//
+// Parameters:
+//   - err: The error of the call.
+//
+// Returns:
+//   - delay: The delay the server asked for.
+//   - ok:    Whether the status of err carries a RetryInfo with a delay.
+func RetryInfo(err error) (delay time.Duration, ok bool) {
+	s, is := status.FromError(err)
+	if !is {
+		return
+	}
+
+	for _, detail := range s.Details() {
+		info, is := detail.(*errdetails.RetryInfo)
+		if !is || info.GetRetryDelay() == nil {
+			continue
+		}
+
+		delay, ok = info.GetRetryDelay().AsDuration(), true
+
+		return
+	}
+
+	return
+}
+
+// UnaryClientInterceptor returns a grpc.UnaryClientInterceptor retrying unary calls failing with a
+// retryable status code, UNAVAILABLE and RESOURCE_EXHAUSTED by default, and waiting for the delay
+// of a RetryInfo detail instead of the backoff delay when the server sets one. Each attempt is
+// invoked with the attempt context of retrier.RetryCtx, so that retrier.AttemptFromContext works in
+// the interceptors chained after it.
+//
+// Parameters:
+//   - opts: The retry options of the calls, overridden by those carried by the context of a call and
+//     by its WithRetryOptions call options.
+//
+// Returns:
+//   - interceptor: The unary client interceptor.
+func UnaryClientInterceptor(opts ...retrier.Option) (interceptor grpc.UnaryClientInterceptor) {
+	interceptor = func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, callOpts ...grpc.CallOption) (err error) {
+		callOpts, overrides := split(callOpts)
+
+		err = retrier.RetryCtx(ctx, func(ctx context.Context) (err error) {
+			err = invoker(ctx, method, req, reply, cc, callOpts...)
+
+			return
+		}, options(ctx, opts, overrides)...)
+
+		return
+	}
+
+	return
+}
+
+// StreamClientInterceptor returns a grpc.StreamClientInterceptor retrying establishing streams that
+// fail with a retryable status code. For the streams whose client sends a single message, i.e.,
+// server streaming calls, the failures are usually only reported by the first receive, so the message
+// is kept and replayed on a new stream until the first response is received. Errors occurring after
+// the first response, and on client streaming calls after the stream is established, are returned
+// to the caller.
+//
+// Parameters:
+//   - opts: The retry options of the calls, overridden by those carried by the context of a call and
+//     by its WithRetryOptions call options.
+//
+// Returns:
+//   - interceptor: The stream client interceptor.
+func StreamClientInterceptor(opts ...retrier.Option) (interceptor grpc.StreamClientInterceptor) {
+	interceptor = func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, callOpts ...grpc.CallOption) (stream grpc.ClientStream, err error) {
+		callOpts, overrides := split(callOpts)
+
+		all := options(ctx, opts, overrides)
+
+		// Streams outlive the attempt that establishes them, so they are established with the
+		// context of the call rather than with the attempt context.
+		open := func() (stream grpc.ClientStream, err error) {
+			stream, err = streamer(ctx, desc, cc, method, callOpts...)
+
+			return
+		}
+
+		stream, err = retrier.RetryWithData(ctx, open, all...)
+		if err != nil || desc.ClientStreams {
+			return
+		}
+
+		stream = &replayingStream{ClientStream: stream, ctx: ctx, open: open, opts: all}
+
+		return
+	}
+
+	return
+}
+
+// replayingStream is a grpc.ClientStream whose client sends a single message, replaying the message
+// on a new stream until the first response is received.
+type replayingStream struct {
+	grpc.ClientStream
+
+	mutex sync.Mutex
+
+	ctx  context.Context //nolint:containedctx // The stream replays the call with its context.
+	open func() (stream grpc.ClientStream, err error)
+	opts []retrier.Option
+
+	message  any
+	sent     bool
+	closed   bool
+	received bool
+}
+
+// SendMsg sends m on the stream, keeping it to be replayed.
+//
+// Parameters:
+//   - m: The message to send.
+//
+// Returns:
+//   - err: The error of sending the message.
+func (s *replayingStream) SendMsg(m any) (err error) {
+	s.mutex.Lock()
+
+	defer s.mutex.Unlock()
+
+	s.message, s.sent = m, true
+
+	err = s.ClientStream.SendMsg(m)
+
+	return
+}
+
+// CloseSend closes the sending side of the stream, to be closed on the replayed streams too.
+//
+// Returns:
+//   - err: The error of closing the sending side.
+func (s *replayingStream) CloseSend() (err error) {
+	s.mutex.Lock()
+
+	defer s.mutex.Unlock()
+
+	s.closed = true
+
+	err = s.ClientStream.CloseSend()
+
+	return
+}
+
+// RecvMsg receives the next response into m. Until the first response is received, failures with a
+// retryable status code open a new stream, replay the message sent, and receive again.
+//
+// Parameters:
+//   - m: The message to receive into.
+//
+// Returns:
+//   - err: The error of receiving the response.
+func (s *replayingStream) RecvMsg(m any) (err error) {
+	s.mutex.Lock()
+
+	defer s.mutex.Unlock()
+
+	if s.received {
+		err = s.ClientStream.RecvMsg(m)
+
+		return
+	}
+
+	attempt := 0
+
+	err = retrier.Retry(s.ctx, func() (err error) {
+		attempt++
+
+		if attempt > 1 {
+			if err = s.replay(); err != nil {
+				return
+			}
+		}
+
+		err = s.ClientStream.RecvMsg(m)
+
+		return
+	}, s.opts...)
+
+	s.received = err == nil
+
+	return
+}
+
+// replay opens a new stream and replays on it the message sent and the closing of the sending side.
+//
+// Returns:
+//   - err: The error of opening the stream or replaying on it.
+func (s *replayingStream) replay() (err error) {
+	stream, err := s.open()
+	if err != nil {
+		return
+	}
+
+	if s.sent {
+		if err = stream.SendMsg(s.message); err != nil {
+			return
+		}
+	}
+
+	if s.closed {
+		if err = stream.CloseSend(); err != nil {
+			return
+		}
+	}
+
+	s.ClientStream = stream
+
+	return
+}
+
+// split separates the retry options carried by WithRetryOptions from the other call options.
+//
+// Parameters:
+//   - callOpts: The options of a call.
+//
+// Returns:
+//   - grpcOpts:  The call options, without those carrying retry options.
+//   - retryOpts: The retry options carried by callOpts.
+func split(callOpts []grpc.CallOption) (grpcOpts []grpc.CallOption, retryOpts []retrier.Option) {
+	grpcOpts = make([]grpc.CallOption, 0, len(callOpts))
+
+	for _, option := range callOpts {
+		if option, ok := option.(callOption); ok {
+			retryOpts = append(retryOpts, option.opts...)
+
+			continue
+		}
+
+		grpcOpts = append(grpcOpts, option)
+	}
+
+	return
+}
+
+// options returns the retry options of a call: classifying errors by status code and honoring the
+// RetryInfo details, followed by the options of the interceptor, those carried by ctx, and those of
+// the call.
+//
+// Parameters:
+//   - ctx:       The context of the call.
+//   - opts:      The options of the interceptor.
+//   - overrides: The options of the call.
+//
+// Returns:
+//   - all: The retry options.
+func options(ctx context.Context, opts, overrides []retrier.Option) (all []retrier.Option) {
+	carried := retrier.OptionsFromContext(ctx)
+
+	all = make([]retrier.Option, 0, 2+len(opts)+len(carried)+len(overrides))
+
+	all = append(all, retrier.WithClassifier(grpcretrier.Classifier(code)), retrier.WithRetryAfterFrom(RetryInfo))
+	all = append(all, opts...)
+	all = append(all, carried...)
+	all = append(all, overrides...)
+
+	return
+}
+
+// code returns the value of the gRPC status code of an error.
+//
+// Parameters:
+//   - err: The error of a call.
+//
+// Returns:
+//   - value: The value of the status code of err.
+func code(err error) (value uint32) {
+	value = uint32(status.Code(err))
+
+	return
+}
@@ -0,0 +1,238 @@
+package interceptor_test
+
+import (
+	"context"
+	"net"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.source.hueristiq.com/retrier"
+	"go.source.hueristiq.com/retrier/grpcretrier/interceptor"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+// flakyHealthServer is a health server failing its first calls with UNAVAILABLE.
+type flakyHealthServer struct {
+	grpc_health_v1.UnimplementedHealthServer
+
+	mutex    sync.Mutex
+	failures int
+	calls    int
+	delay    time.Duration
+	code     codes.Code
+}
+
+func (s *flakyHealthServer) fail() (err error) {
+	s.mutex.Lock()
+
+	defer s.mutex.Unlock()
+
+	s.calls++
+
+	if s.calls > s.failures {
+		return
+	}
+
+	code := s.code
+	if code == codes.OK {
+		code = codes.Unavailable
+	}
+
+	st := status.New(code, "unavailable")
+
+	if s.delay > 0 {
+		st, _ = st.WithDetails(&errdetails.RetryInfo{RetryDelay: durationpb.New(s.delay)})
+	}
+
+	err = st.Err()
+
+	return
+}
+
+func (s *flakyHealthServer) Check(context.Context, *grpc_health_v1.HealthCheckRequest) (*grpc_health_v1.HealthCheckResponse, error) {
+	if err := s.fail(); err != nil {
+		return nil, err
+	}
+
+	return &grpc_health_v1.HealthCheckResponse{Status: grpc_health_v1.HealthCheckResponse_SERVING}, nil
+}
+
+func (s *flakyHealthServer) Watch(req *grpc_health_v1.HealthCheckRequest, stream grpc.ServerStreamingServer[grpc_health_v1.HealthCheckResponse]) error {
+	if err := s.fail(); err != nil {
+		return err
+	}
+
+	if req.GetService() != "watched" {
+		return status.Error(codes.NotFound, "unknown service")
+	}
+
+	return stream.Send(&grpc_health_v1.HealthCheckResponse{Status: grpc_health_v1.HealthCheckResponse_SERVING})
+}
+
+func dial(t *testing.T, server *flakyHealthServer, opts ...retrier.Option) (client grpc_health_v1.HealthClient) {
+	t.Helper()
+
+	listener := bufconn.Listen(1 << 20)
+
+	s := grpc.NewServer()
+
+	grpc_health_v1.RegisterHealthServer(s, server)
+
+	go func() {
+		_ = s.Serve(listener)
+	}()
+
+	t.Cleanup(s.Stop)
+
+	opts = append([]retrier.Option{retrier.WithMinDelay(time.Millisecond), retrier.WithMaxDelay(time.Millisecond)}, opts...)
+
+	conn, err := grpc.NewClient("passthrough:///bufconn",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return listener.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithUnaryInterceptor(interceptor.UnaryClientInterceptor(opts...)),
+		grpc.WithStreamInterceptor(interceptor.StreamClientInterceptor(opts...)),
+	)
+
+	require.NoError(t, err, "Expected the client to be created")
+
+	t.Cleanup(func() {
+		_ = conn.Close()
+	})
+
+	client = grpc_health_v1.NewHealthClient(conn)
+
+	return
+}
+
+func TestUnaryClientInterceptor(t *testing.T) {
+	t.Parallel()
+
+	server := &flakyHealthServer{failures: 2}
+
+	client := dial(t, server)
+
+	res, err := client.Check(context.Background(), &grpc_health_v1.HealthCheckRequest{})
+
+	require.NoError(t, err, "Expected the call to succeed after retrying")
+	assert.Equal(t, grpc_health_v1.HealthCheckResponse_SERVING, res.GetStatus(), "Expected the response of the last attempt")
+	assert.Equal(t, 3, server.calls, "Expected the call to be retried twice")
+}
+
+func TestUnaryClientInterceptor_WithRetryOptions(t *testing.T) {
+	t.Parallel()
+
+	server := &flakyHealthServer{failures: 2}
+
+	client := dial(t, server)
+
+	_, err := client.Check(context.Background(), &grpc_health_v1.HealthCheckRequest{}, interceptor.WithRetryOptions(retrier.WithMaxRetries(2)))
+
+	require.Error(t, err, "Expected the call to fail after two attempts")
+	assert.Equal(t, codes.Unavailable, status.Code(err), "Expected the status of the last attempt")
+	assert.Equal(t, 2, server.calls, "Expected the call options to override the interceptor options")
+}
+
+func TestUnaryClientInterceptor_WithRetryableCodes(t *testing.T) {
+	t.Parallel()
+
+	server := &flakyHealthServer{failures: 2, code: codes.Aborted}
+
+	client := dial(t, server)
+
+	_, err := client.Check(context.Background(), &grpc_health_v1.HealthCheckRequest{})
+
+	assert.Equal(t, codes.Aborted, status.Code(err), "Expected ABORTED not to be retried by default")
+	assert.Equal(t, 1, server.calls, "Expected a single attempt")
+
+	server = &flakyHealthServer{failures: 2, code: codes.Aborted}
+
+	client = dial(t, server, interceptor.WithRetryableCodes(codes.Aborted))
+
+	_, err = client.Check(context.Background(), &grpc_health_v1.HealthCheckRequest{})
+
+	require.NoError(t, err, "Expected the call to succeed after retrying")
+	assert.Equal(t, 3, server.calls, "Expected ABORTED to be retried")
+
+	server = &flakyHealthServer{failures: 2}
+
+	client = dial(t, server)
+
+	_, err = client.Check(context.Background(), &grpc_health_v1.HealthCheckRequest{}, interceptor.WithRetryOptions(interceptor.WithRetryableCodes(codes.Aborted)))
+
+	assert.Equal(t, codes.Unavailable, status.Code(err), "Expected the codes of the call to replace the default ones")
+	assert.Equal(t, 1, server.calls, "Expected a single attempt")
+}
+
+func TestUnaryClientInterceptor_RetryInfo(t *testing.T) {
+	t.Parallel()
+
+	server := &flakyHealthServer{failures: 1, delay: 20 * time.Millisecond}
+
+	var backoffs []time.Duration
+
+	client := dial(t, server, retrier.WithNotifierV2(func(info retrier.AttemptInfo) {
+		backoffs = append(backoffs, info.Backoff)
+	}))
+
+	_, err := client.Check(context.Background(), &grpc_health_v1.HealthCheckRequest{})
+
+	require.NoError(t, err, "Expected the call to succeed after retrying")
+	assert.Equal(t, []time.Duration{20 * time.Millisecond}, backoffs, "Expected the delay of the RetryInfo to replace the backoff delay")
+}
+
+func TestRetryInfo(t *testing.T) {
+	t.Parallel()
+
+	st, err := status.New(codes.ResourceExhausted, "throttled").WithDetails(&errdetails.RetryInfo{RetryDelay: durationpb.New(time.Second)})
+
+	require.NoError(t, err, "Expected the detail to be attached")
+
+	delay, ok := interceptor.RetryInfo(st.Err())
+
+	assert.True(t, ok, "Expected the RetryInfo to be found")
+	assert.Equal(t, time.Second, delay, "Expected the delay of the RetryInfo")
+
+	delay, ok = interceptor.RetryInfo(status.Error(codes.Unavailable, "unavailable"))
+
+	assert.False(t, ok, "Expected no RetryInfo")
+	assert.Zero(t, delay, "Expected no delay")
+}
+
+func TestStreamClientInterceptor(t *testing.T) {
+	t.Parallel()
+
+	server := &flakyHealthServer{failures: 2}
+
+	client := dial(t, server)
+
+	stream, err := client.Watch(context.Background(), &grpc_health_v1.HealthCheckRequest{Service: "watched"})
+
+	require.NoError(t, err, "Expected the stream to be established")
+
+	res, err := stream.Recv()
+
+	require.NoError(t, err, "Expected the first response after replaying the request")
+	assert.Equal(t, grpc_health_v1.HealthCheckResponse_SERVING, res.GetStatus(), "Expected the response of the replayed stream")
+	assert.Equal(t, 3, server.calls, "Expected the request to be replayed twice")
+
+	stream, err = client.Watch(context.Background(), &grpc_health_v1.HealthCheckRequest{Service: "unknown"})
+
+	require.NoError(t, err, "Expected the stream to be established")
+
+	_, err = stream.Recv()
+
+	assert.Equal(t, codes.NotFound, status.Code(err), "Expected permanent errors not to be retried")
+	assert.Equal(t, 4, server.calls, "Expected no replay of permanent errors")
+}
@@ -0,0 +1,70 @@
+package grpcretrier
+
+import (
+	"strconv"
+	"time"
+
+	"go.source.hueristiq.com/retrier/policy"
+)
+
+const (
+	// PreviousAttemptsHeader is the header in which gRPC clients announce the number of previous
+	// attempts of a retried call.
+	PreviousAttemptsHeader = "grpc-previous-rpc-attempts"
+	// PushbackTrailer is the trailer in which gRPC servers ask clients to wait, in milliseconds,
+	// before retrying a call.
+	PushbackTrailer = "grpc-retry-pushback-ms"
+)
+
+const (
+	// CodeDeadlineExceeded is the value of the DEADLINE_EXCEEDED gRPC status code.
+	CodeDeadlineExceeded uint32 = 4
+	// CodeResourceExhausted is the value of the RESOURCE_EXHAUSTED gRPC status code.
+	CodeResourceExhausted uint32 = 8
+	// CodeUnavailable is the value of the UNAVAILABLE gRPC status code.
+	CodeUnavailable uint32 = 14
+)
+
+// Pushback computes the delay a server asks a client to wait for before retrying a call rejected with
+// the given status code, using the retry policy of the clients and the attempt number announced in
+// the PreviousAttemptsHeader of the call, so that pushback delays grow with the attempts like the
+// clients' own backoff.
+//
+// Parameters:
+//   - code:             The value of the gRPC status code of the rejected call.
+//   - previousAttempts: The values of the PreviousAttemptsHeader of the call, if any.
+//   - p:                The retry policy of the clients.
+//
+// Returns:
+//   - pushback: The delay the client should wait for before its next attempt.
+//   - ok:       Whether the code calls for a pushback, i.e., is RESOURCE_EXHAUSTED or UNAVAILABLE.
+func Pushback(code uint32, previousAttempts []string, p policy.Policy) (pushback time.Duration, ok bool) {
+	if code != CodeResourceExhausted && code != CodeUnavailable {
+		return
+	}
+
+	attempt := 0
+
+	if len(previousAttempts) > 0 {
+		if n, err := strconv.Atoi(previousAttempts[0]); err == nil && n > 0 {
+			attempt = n
+		}
+	}
+
+	pushback, ok = p.Delay(attempt), true
+
+	return
+}
+
+// FormatPushback formats a delay as the value of the PushbackTrailer.
+//
+// Parameters:
+//   - pushback: The delay.
+//
+// Returns:
+//   - value: The delay in milliseconds.
+func FormatPushback(pushback time.Duration) (value string) {
+	value = strconv.FormatInt(pushback.Milliseconds(), 10)
+
+	return
+}
@@ -0,0 +1,38 @@
+package grpcretrier_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.source.hueristiq.com/retrier/grpcretrier"
+	"go.source.hueristiq.com/retrier/policy"
+)
+
+func TestPushback(t *testing.T) {
+	t.Parallel()
+
+	p := policy.Policy{MaxRetries: 5, MinDelay: 100 * time.Millisecond, MaxDelay: time.Second}
+
+	tests := []struct {
+		code             uint32
+		previousAttempts []string
+		expected         time.Duration
+		ok               bool
+	}{
+		{grpcretrier.CodeUnavailable, nil, 100 * time.Millisecond, true},
+		{grpcretrier.CodeResourceExhausted, []string{"2"}, 400 * time.Millisecond, true},
+		{grpcretrier.CodeUnavailable, []string{"invalid"}, 100 * time.Millisecond, true},
+		{grpcretrier.CodeUnavailable, []string{"10"}, time.Second, true},
+		{5, []string{"1"}, 0, false},
+	}
+
+	for _, tt := range tests {
+		pushback, ok := grpcretrier.Pushback(tt.code, tt.previousAttempts, p)
+
+		assert.Equal(t, tt.ok, ok, "Unexpected pushback decision for code %d", tt.code)
+		assert.Equal(t, tt.expected, pushback, "Unexpected pushback for code %d after %v attempts", tt.code, tt.previousAttempts)
+	}
+
+	assert.Equal(t, "400", grpcretrier.FormatPushback(400*time.Millisecond), "Expected the pushback in milliseconds")
+}
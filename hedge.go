@@ -0,0 +1,110 @@
+package retrier
+
+import (
+	"context"
+	"time"
+)
+
+// RetryHedged runs a retry sequence whose attempts are hedged: every attempt launches the operation,
+// then, if it is still in flight after the hedging delay, a speculative second call of it, and
+// completes with whichever call succeeds first, cancelling the other. An attempt fails once every call
+// it launched failed, with the error of the last one; a call failing before the hedging delay is
+// retried as usual rather than hedged. Hedging trades extra load for a shorter tail latency,
+// so the operation must be idempotent.
+//
+// Parameters:
+//   - ctx:       A context to control the lifetime of the retry sequence.
+//   - operation: The operation to call. The context it receives is derived from the context of the
+//     attempt, as described by RetryCtx, so that AttemptFromContext works in every call and abandoning
+//     or superseding the attempt cancels the calls in flight, and is cancelled once the attempt
+//     completes, so that the losing call stops.
+//   - delay:     The hedging delay after which the speculative call is launched. A non-positive delay
+//     launches both calls at once.
+//   - opts:      Optional configuration options.
+//
+// Returns:
+//   - result: The result of the first successful call.
+//   - err:    The error of the retry sequence, as returned by RetryCtxWithData.
+//
+// Example:
+//
+//	user, err := retrier.RetryHedged(ctx, fetchUser, 50*time.Millisecond, retrier.WithMaxRetries(3))
+//	// A call still in flight after 50ms is raced by a second one.
+func RetryHedged[T any](ctx context.Context, operation func(ctx context.Context) (T, error), delay time.Duration, opts ...Option) (result T, err error) {
+	cfg, err := NewValidated(opts...)
+	if err != nil {
+		return
+	}
+
+	result, err = retry(ctx, cfg, func(ctx context.Context) (result T, err error) {
+		result, err = hedge(ctx, cfg.clock, operation, delay)
+
+		return
+	}, true)
+
+	return
+}
+
+// outcome is the outcome of a call of an operation returning data.
+type outcome[T any] struct {
+	result T
+	err    error
+}
+
+// hedge runs one hedged attempt of an operation.
+//
+// Parameters:
+//   - ctx:       The context of the attempt.
+//   - clock:     The clock timing the hedging delay.
+//   - operation: The operation to call.
+//   - delay:     The hedging delay.
+//
+// Returns:
+//   - result: The result of the first successful call.
+//   - err:    The error of the last call if every launched call failed, or the context's error.
+func hedge[T any](ctx context.Context, clock Clock, operation func(ctx context.Context) (T, error), delay time.Duration) (result T, err error) {
+	ctx, cancel := context.WithCancel(ctx)
+
+	defer cancel()
+
+	// The channel is buffered so that the losing call does not block once the attempt completed.
+	outcomes := make(chan outcome[T], 2)
+
+	call := func() {
+		result, err := operation(ctx)
+
+		outcomes <- outcome[T]{result: result, err: err}
+	}
+
+	go call()
+
+	timer := clock.NewTimer(max(delay, 0))
+
+	defer timer.Stop()
+
+	launched, completed := 1, 0
+
+	for {
+		select {
+		case <-timer.C():
+			if launched == 1 {
+				launched++
+
+				go call()
+			}
+		case o := <-outcomes:
+			completed++
+
+			result, err = o.result, o.err
+
+			// The first success wins, and a failure completes the attempt unless another call is in flight.
+			if err == nil || completed == launched {
+				return
+			}
+		case <-ctx.Done():
+			err = ctx.Err()
+
+			return
+		}
+	}
+}
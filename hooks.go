@@ -0,0 +1,285 @@
+package retrier
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ErrHookPanicked is wrapped by every HookPanicError.
+var ErrHookPanicked = errors.New("hook panicked")
+
+// HookKind is the kind of hook that panicked, as recorded by a HookPanicError.
+type HookKind int
+
+const (
+	// HookNotifier is the kind of the notifiers, registered with WithNotifier and WithNotifierV2.
+	HookNotifier HookKind = iota
+	// HookLifecycle is the kind of the lifecycle hooks, registered with WithHooks.
+	HookLifecycle
+)
+
+// String returns the name of the kind of hook.
+//
+// Returns:
+//   - name: The name of the kind of hook.
+func (k HookKind) String() (name string) {
+	switch k {
+	case HookNotifier:
+		name = "notifier"
+	case HookLifecycle:
+		name = "lifecycle hook"
+	default:
+		name = "hook"
+	}
+
+	return
+}
+
+// HookPanicError records a hook, such as a notifier, that panicked. The panic is recovered so that a
+// faulty hook cannot stop the retry sequence.
+//
+// Fields:
+//   - Kind: The kind of the hook, HookNotifier or HookLifecycle.
+//   - Hook: The zero-based index of the hook in registration order among the hooks of its Kind, i.e.,
+//     among the notifiers, or among the Hooks registered with WithHooks.
+//   - Value: The value the hook panicked with.
+type HookPanicError struct {
+	Kind  HookKind
+	Hook  int
+	Value any
+}
+
+// Error implements the error interface.
+//
+// Returns:
+//   - message: The description of the panic.
+func (e *HookPanicError) Error() (message string) {
+	message = fmt.Sprintf("%s: %s %d: %v", ErrHookPanicked, e.Kind, e.Hook, e.Value)
+
+	return
+}
+
+// Unwrap returns ErrHookPanicked, so that errors.Is(err, ErrHookPanicked) reports hook panics.
+//
+// Returns:
+//   - err: ErrHookPanicked.
+func (e *HookPanicError) Unwrap() (err error) {
+	err = ErrHookPanicked
+
+	return
+}
+
+// Hooks are callbacks run at the stages of the lifecycle of a retry sequence, set through WithHooks.
+// Unlike the notifiers, which run on every failed attempt whether the retry sequence retries or not,
+// they tell the stages apart, e.g., a sleep before the next attempt from giving up. They run inline,
+// on every retry sequence regardless of WithSampling, and nil callbacks are skipped.
+//
+// Fields:
+//   - BeforeAttempt: Called before every attempt with its zero-based number.
+//   - AfterFailure: Called after every failed attempt with its zero-based number and error.
+//   - BeforeSleep: Called before every backoff delay followed by another attempt, with the zero-based
+//     number of the failed attempt it follows and its duration.
+//   - OnSuccess: Called once the retry sequence succeeds, with the number of attempts it took.
+//   - OnGiveUp: Called once the retry sequence gives up, with the number of attempts it took and the
+//     error it returns, a *RetryError unless it gave up on a permanent failure. It is not called for
+//     a retry sequence handed over to the background by WithSoftGiveUp.
+type Hooks struct {
+	BeforeAttempt func(attempt int)
+	AfterFailure  func(attempt int, err error)
+	BeforeSleep   func(attempt int, delay time.Duration)
+	OnSuccess     func(attempts int)
+	OnGiveUp      func(attempts int, err error)
+}
+
+// HookTiming determines when the notifiers of a failed attempt run relative to the backoff delay
+// that follows it.
+type HookTiming int
+
+const (
+	// HookTimingBeforeSleep runs the notifiers inline before the delay, which the time they take
+	// lengthens. This is the default.
+	HookTimingBeforeSleep HookTiming = iota
+	// HookTimingAfterSleep runs the notifiers inline once the delay is over, before the next attempt,
+	// or before returning if the retry sequence ends instead.
+	HookTimingAfterSleep
+	// HookTimingAsync runs the notifiers in the background, so that they never delay the retry
+	// sequence. Notifications are still delivered in order, but may outlive the retry sequence, and
+	// their panics are recovered without being recorded in Stats.HookFailures.
+	HookTimingAsync
+)
+
+// notification is a failed attempt to notify of.
+type notification struct {
+	info AttemptInfo
+}
+
+// dispatcher delivers the notifications of a retry sequence according to a HookTiming, grouping
+// consecutive failures with the same fingerprint if configured.
+type dispatcher struct {
+	timing      HookTiming
+	notifiers   []NotifierV2
+	fingerprint func(err error) string
+	failures    []error
+
+	// lifecycle holds the Hooks of the retry sequence.
+	lifecycle []Hooks
+
+	// pending holds the notifications deferred by HookTimingAfterSleep.
+	pending []notification
+
+	// delivered is closed once the last notification dispatched by HookTimingAsync is delivered.
+	delivered chan struct{}
+
+	// grouped, key, and repeated track the group of failures with the same fingerprint.
+	grouped  bool
+	key      string
+	repeated []notification
+}
+
+// dispatch notifies of a failed attempt, at the point where HookTimingBeforeSleep runs the notifiers.
+//
+// Parameters:
+//   - info: The failed attempt.
+func (d *dispatcher) dispatch(info AttemptInfo) {
+	if len(d.notifiers) == 0 {
+		return
+	}
+
+	if d.fingerprint != nil {
+		key := d.fingerprint(info.Err)
+
+		if d.grouped && key == d.key {
+			d.repeated = append(d.repeated, notification{info: info})
+
+			return
+		}
+
+		d.summarize()
+
+		d.grouped, d.key = true, key
+	}
+
+	d.deliver(notification{info: info})
+}
+
+// summarize notifies of the failures repeating the first one of the current group, if any, as a
+// single *RepeatedError.
+func (d *dispatcher) summarize() {
+	if len(d.repeated) == 0 {
+		return
+	}
+
+	last := d.repeated[len(d.repeated)-1].info
+
+	last.Err = &RepeatedError{Err: last.Err, Fingerprint: d.key, Repeats: len(d.repeated)}
+
+	d.deliver(notification{info: last})
+
+	d.repeated = d.repeated[:0]
+}
+
+// deliver runs the notifiers according to the HookTiming.
+//
+// Parameters:
+//   - n: The notification to deliver.
+func (d *dispatcher) deliver(n notification) {
+	switch d.timing {
+	case HookTimingBeforeSleep:
+		d.failures = notify(d.notifiers, n.info, d.failures)
+	case HookTimingAfterSleep:
+		d.pending = append(d.pending, n)
+	case HookTimingAsync:
+		notifiers, previous, delivered := d.notifiers, d.delivered, make(chan struct{})
+
+		d.delivered = delivered
+
+		go func() {
+			defer close(delivered)
+
+			if previous != nil {
+				<-previous
+			}
+
+			notify(notifiers, n.info, nil)
+		}()
+	}
+}
+
+// flush delivers the notifications deferred by HookTimingAfterSleep, if any.
+func (d *dispatcher) flush() {
+	for _, n := range d.pending {
+		d.failures = notify(d.notifiers, n.info, d.failures)
+	}
+
+	clear(d.pending)
+
+	d.pending = d.pending[:0]
+}
+
+// close delivers every notification still held once the retry sequence ends.
+func (d *dispatcher) close() {
+	d.summarize()
+	d.flush()
+}
+
+// notify calls the notifiers in registration order, isolating each one from the panics of the others.
+//
+// Parameters:
+//   - notifiers: The notifiers to call.
+//   - info:      The failed attempt.
+//   - failures:  The hook failures recorded so far.
+//
+// Returns:
+//   - recorded: The hook failures, with the panics of the notifiers appended.
+func notify(notifiers []NotifierV2, info AttemptInfo, failures []error) (recorded []error) {
+	recorded = failures
+
+	for i, notifier := range notifiers {
+		if failure := callNotifier(i, notifier, info); failure != nil {
+			recorded = append(recorded, failure)
+		}
+	}
+
+	return
+}
+
+// callNotifier calls a notifier, recovering from its panic.
+//
+// Parameters:
+//   - index:    The index of the notifier in registration order.
+//   - notifier: The notifier to call.
+//   - info:     The failed attempt.
+//
+// Returns:
+//   - failure: A *HookPanicError if the notifier panicked, or nil otherwise.
+func callNotifier(index int, notifier NotifierV2, info AttemptInfo) (failure error) {
+	defer func() {
+		if value := recover(); value != nil {
+			failure = &HookPanicError{Kind: HookNotifier, Hook: index, Value: value}
+		}
+	}()
+
+	notifier(info)
+
+	return
+}
+
+// run calls a callback of every Hooks of the retry sequence, in registration order, isolating each one
+// from the panics of the others.
+//
+// Parameters:
+//   - call: The function calling the callback of a Hooks, if set.
+func (d *dispatcher) run(call func(hooks Hooks)) {
+	for i, hooks := range d.lifecycle {
+		func() {
+			defer func() {
+				if value := recover(); value != nil {
+					d.failures = append(d.failures, &HookPanicError{Kind: HookLifecycle, Hook: i, Value: value})
+				}
+			}()
+
+			call(hooks)
+		}()
+	}
+}
@@ -0,0 +1,16 @@
+// Package httpretrier integrates the retrier with net/http.
+//
+// On the server side, Headers is a middleware stamping responses with the attempt number announced
+// by the client and a hint of the delay its retry policy waits before the next attempt, which helps
+// debugging retry amplification across services. Under overload, RetryAfter, SetRetryAfter, and
+// Overloaded derive the Retry-After delays advertised to clients from their retry policy, so that the
+// advertised delays grow with the attempts like the clients' own backoff.
+//
+// On the client side, Transport is an http.RoundTripper retrying requests, rewinding their bodies
+// through GetBody, so that an http.Client retries by swapping its transport. Non-idempotent requests
+// are only retried after failures that happened before they were sent. CheckResponse
+// turns retryable responses into a *StatusError carrying the delay the server asked for, which the
+// retrier waits for instead of its backoff delay unless configured with retrier.WithServerHints(false).
+// Reresolve is a hook for retrier.WithReresolve dropping the pooled connections of a transport after
+// a long delay, so that the next attempt re-resolves the host.
+package httpretrier
@@ -0,0 +1,127 @@
+package httpretrier
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"go.source.hueristiq.com/retrier/policy"
+)
+
+const (
+	// AttemptHeader is the header carrying the zero-based number of the attempt a request belongs to.
+	// Clients announce it on requests; Headers echoes it on responses.
+	AttemptHeader = "X-Retry-Attempt"
+	// RetryAfterHintHeader is the header carrying the delay, in milliseconds, the retry policy waits
+	// for before the next attempt.
+	RetryAfterHintHeader = "X-Retry-After-Hint"
+)
+
+// Headers returns a middleware stamping responses with the attempt number announced by the client in
+// the AttemptHeader of the request, and, for responses the client retries, i.e., 429 Too Many
+// Requests and 5xx ones, with the delay the policy waits for before the next attempt in the
+// RetryAfterHintHeader. Requests without a valid AttemptHeader are treated as first attempts.
+//
+// Parameters:
+//   - p: The retry policy of the clients, whose delays are hinted.
+//
+// Returns:
+//   - middleware: The middleware wrapping a handler.
+//
+// Example:
+//
+//	handler = httpretrier.Headers(policy.Policy{MaxRetries: 3, MinDelay: 100 * time.Millisecond, MaxDelay: time.Second})(handler)
+//	// A 503 answered to the second attempt carries "X-Retry-Attempt: 1" and "X-Retry-After-Hint: 200".
+func Headers(p policy.Policy) (middleware func(next http.Handler) http.Handler) {
+	middleware = func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			next.ServeHTTP(&stampingWriter{ResponseWriter: w, policy: p, attempt: attemptOf(r)}, r)
+		})
+	}
+
+	return
+}
+
+// attemptOf returns the attempt number announced by the client in the AttemptHeader of a request.
+//
+// Parameters:
+//   - r: The request.
+//
+// Returns:
+//   - attempt: The announced attempt number, or 0 if the header is missing or invalid.
+func attemptOf(r *http.Request) (attempt int) {
+	attempt, err := strconv.Atoi(r.Header.Get(AttemptHeader))
+	if err != nil || attempt < 0 {
+		attempt = 0
+	}
+
+	return
+}
+
+// retryable reports whether clients retry responses with the given status code.
+//
+// Parameters:
+//   - status: The HTTP status code.
+//
+// Returns:
+//   - ok: Whether the status is 429 Too Many Requests or 5xx.
+func retryable(status int) (ok bool) {
+	ok = status == http.StatusTooManyRequests || status >= http.StatusInternalServerError
+
+	return
+}
+
+// formatHint formats a delay as the value of the RetryAfterHintHeader.
+//
+// Parameters:
+//   - delay: The hinted delay.
+//
+// Returns:
+//   - value: The delay in milliseconds.
+func formatHint(delay time.Duration) (value string) {
+	value = strconv.FormatInt(delay.Milliseconds(), 10)
+
+	return
+}
+
+// stampingWriter is an http.ResponseWriter stamping the retry headers before the response header is written.
+type stampingWriter struct {
+	http.ResponseWriter
+
+	policy  policy.Policy
+	attempt int
+	written bool
+}
+
+// WriteHeader stamps the retry headers and writes the response header.
+func (w *stampingWriter) WriteHeader(status int) {
+	if !w.written {
+		w.written = true
+
+		header := w.Header()
+
+		header.Set(AttemptHeader, strconv.Itoa(w.attempt))
+
+		if retryable(status) && header.Get(RetryAfterHintHeader) == "" {
+			header.Set(RetryAfterHintHeader, formatHint(w.policy.Delay(w.attempt)))
+		}
+	}
+
+	w.ResponseWriter.WriteHeader(status)
+}
+
+// Write stamps the retry headers of an implicit 200 OK response and writes the body.
+func (w *stampingWriter) Write(body []byte) (n int, err error) {
+	if !w.written {
+		w.WriteHeader(http.StatusOK)
+	}
+
+	return w.ResponseWriter.Write(body)
+}
+
+// Unwrap returns the wrapped http.ResponseWriter, for http.ResponseController.
+func (w *stampingWriter) Unwrap() (writer http.ResponseWriter) {
+	writer = w.ResponseWriter
+
+	return
+}
@@ -0,0 +1,65 @@
+package httpretrier_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.source.hueristiq.com/retrier/httpretrier"
+	"go.source.hueristiq.com/retrier/policy"
+)
+
+func TestHeaders(t *testing.T) {
+	t.Parallel()
+
+	p := policy.Policy{MaxRetries: 3, MinDelay: 100 * time.Millisecond, MaxDelay: time.Second}
+
+	tests := []struct {
+		attempt  string
+		status   int
+		expected string
+		hint     string
+	}{
+		{"", http.StatusOK, "0", ""},
+		{"1", http.StatusServiceUnavailable, "1", "200"},
+		{"2", http.StatusTooManyRequests, "2", "400"},
+		{"invalid", http.StatusInternalServerError, "0", "100"},
+		{"1", http.StatusNotFound, "1", ""},
+	}
+
+	for _, tt := range tests {
+		handler := httpretrier.Headers(p)(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			w.WriteHeader(tt.status)
+		}))
+
+		req := httptest.NewRequest(http.MethodGet, "/", http.NoBody)
+		if tt.attempt != "" {
+			req.Header.Set(httpretrier.AttemptHeader, tt.attempt)
+		}
+
+		rec := httptest.NewRecorder()
+
+		handler.ServeHTTP(rec, req)
+
+		assert.Equal(t, tt.status, rec.Code, "Expected the status to be preserved")
+		assert.Equal(t, tt.expected, rec.Header().Get(httpretrier.AttemptHeader), "Unexpected attempt for %q", tt.attempt)
+		assert.Equal(t, tt.hint, rec.Header().Get(httpretrier.RetryAfterHintHeader), "Unexpected hint for %q", tt.attempt)
+	}
+}
+
+func TestHeaders_ImplicitStatus(t *testing.T) {
+	t.Parallel()
+
+	handler := httpretrier.Headers(policy.Policy{})(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte("ok"))
+	}))
+
+	rec := httptest.NewRecorder()
+
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", http.NoBody))
+
+	assert.Equal(t, "0", rec.Header().Get(httpretrier.AttemptHeader), "Expected the attempt to be stamped on implicit responses")
+	assert.Equal(t, "ok", rec.Body.String(), "Expected the body to be written")
+}
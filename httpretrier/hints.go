@@ -0,0 +1,163 @@
+package httpretrier
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"go.source.hueristiq.com/retrier/backoff"
+	"go.source.hueristiq.com/retrier/policy"
+)
+
+// RetryAfter computes the delay a server asks a client to wait for before retrying a request, using
+// the retry policy of the clients and the attempt number announced in the AttemptHeader of the
+// request, so that the delays advertised under overload grow consistently with the attempts instead
+// of being a constant every client retries after at once.
+//
+// Parameters:
+//   - r: The request being rejected.
+//   - p: The retry policy of the clients.
+//
+// Returns:
+//   - delay: The delay the client should wait for before its next attempt.
+func RetryAfter(r *http.Request, p policy.Policy) (delay time.Duration) {
+	delay = p.Delay(attemptOf(r))
+
+	return
+}
+
+// SetRetryAfter sets the Retry-After header, rounded up to the second as the header requires, and the
+// more precise RetryAfterHintHeader of a response, to the delay computed by RetryAfter. It must be
+// called before the response header is written.
+//
+// Parameters:
+//   - w: The response writer.
+//   - r: The request being rejected.
+//   - p: The retry policy of the clients.
+//
+// Example:
+//
+//	httpretrier.SetRetryAfter(w, r, p)
+//	w.WriteHeader(http.StatusServiceUnavailable)
+func SetRetryAfter(w http.ResponseWriter, r *http.Request, p policy.Policy) {
+	delay := RetryAfter(r, p)
+
+	header := w.Header()
+
+	header.Set("Retry-After", strconv.FormatInt(int64((delay+time.Second-1)/time.Second), 10))
+	header.Set(RetryAfterHintHeader, formatHint(delay))
+}
+
+// Overloaded returns a handler rejecting every request with 503 Service Unavailable and the delays
+// computed by SetRetryAfter, to be served in place of the regular handler while a server sheds load.
+//
+// Parameters:
+//   - p: The retry policy of the clients.
+//
+// Returns:
+//   - handler: The handler rejecting requests.
+func Overloaded(p policy.Policy) (handler http.Handler) {
+	handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		SetRetryAfter(w, r, p)
+
+		w.WriteHeader(http.StatusServiceUnavailable)
+	})
+
+	return
+}
+
+// StatusError is the error of a response clients retry, i.e., a 429 Too Many Requests or 5xx one. It
+// carries the delay the server asked for, which the retrier waits for instead of its backoff delay
+// unless configured with retrier.WithServerHints(false).
+//
+// Fields:
+//   - StatusCode: The HTTP status code of the response.
+//   - Hint: The delay the server asked for, or 0 if it did not.
+type StatusError struct {
+	StatusCode int
+	Hint       time.Duration
+}
+
+// Error implements the error interface.
+//
+// Returns:
+//   - message: The description of the response.
+func (e *StatusError) Error() (message string) {
+	message = "httpretrier: retryable response: " + strconv.Itoa(e.StatusCode) + " " + http.StatusText(e.StatusCode)
+
+	return
+}
+
+// RetryAfter returns the delay the server asked for.
+//
+// Returns:
+//   - delay: The hinted delay, or 0 if the server did not ask for one.
+func (e *StatusError) RetryAfter() (delay time.Duration) {
+	delay = e.Hint
+
+	return
+}
+
+// CheckResponse returns a *StatusError for responses clients retry, i.e., 429 Too Many Requests and
+// 5xx ones, carrying the delay from the RetryAfterHintHeader if present, or from the Retry-After
+// header, in seconds or as an HTTP date, otherwise. Other responses yield nil.
+//
+// Parameters:
+//   - res: The response to check.
+//
+// Returns:
+//   - err: A *StatusError for retryable responses, or nil otherwise.
+//
+// Example:
+//
+//	err := retrier.Retry(ctx, func() error {
+//	    res, err := client.Do(req)
+//	    if err != nil {
+//	        return err
+//	    }
+//
+//	    defer res.Body.Close()
+//
+//	    return httpretrier.CheckResponse(res)
+//	})
+func CheckResponse(res *http.Response) (err error) {
+	if !retryable(res.StatusCode) {
+		return
+	}
+
+	err = &StatusError{StatusCode: res.StatusCode, Hint: parseHint(res.Header)}
+
+	return
+}
+
+// parseHint returns the delay a server asked for in the headers of a response.
+//
+// Parameters:
+//   - header: The headers of the response.
+//
+// Returns:
+//   - delay: The hinted delay, or 0 if the headers do not carry a valid one.
+func parseHint(header http.Header) (delay time.Duration) {
+	if ms, err := strconv.ParseInt(header.Get(RetryAfterHintHeader), 10, 64); err == nil && ms > 0 {
+		delay = backoff.SafeMul(time.Millisecond, float64(ms))
+
+		return
+	}
+
+	value := header.Get("Retry-After")
+	if value == "" {
+		return
+	}
+
+	if seconds, err := strconv.ParseInt(value, 10, 64); err == nil {
+		delay = backoff.SafeMul(time.Second, float64(max(seconds, 0)))
+
+		return
+	}
+
+	if at, err := http.ParseTime(value); err == nil {
+		delay = max(time.Until(at), 0)
+	}
+
+	return
+}
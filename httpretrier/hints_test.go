@@ -0,0 +1,103 @@
+package httpretrier_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.source.hueristiq.com/retrier"
+	"go.source.hueristiq.com/retrier/httpretrier"
+	"go.source.hueristiq.com/retrier/policy"
+)
+
+func TestCheckResponse(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		status   int
+		header   http.Header
+		expected time.Duration
+		err      bool
+	}{
+		{http.StatusOK, nil, 0, false},
+		{http.StatusNotFound, nil, 0, false},
+		{http.StatusServiceUnavailable, nil, 0, true},
+		{http.StatusTooManyRequests, http.Header{"Retry-After": {"2"}}, 2 * time.Second, true},
+		{http.StatusTooManyRequests, http.Header{"Retry-After": {"2"}, httpretrier.RetryAfterHintHeader: {"1500"}}, 1500 * time.Millisecond, true},
+		{http.StatusBadGateway, http.Header{"Retry-After": {"invalid"}}, 0, true},
+	}
+
+	for _, tt := range tests {
+		err := httpretrier.CheckResponse(&http.Response{StatusCode: tt.status, Header: tt.header})
+		if !tt.err {
+			assert.NoError(t, err, "Expected no error for status %d", tt.status)
+
+			continue
+		}
+
+		var target *httpretrier.StatusError
+
+		require.ErrorAs(t, err, &target, "Expected a StatusError for status %d", tt.status)
+		assert.Equal(t, tt.status, target.StatusCode, "Unexpected status code")
+		assert.Equal(t, tt.expected, target.RetryAfter(), "Unexpected hint for status %d", tt.status)
+	}
+}
+
+func TestOverloaded_ServerHints(t *testing.T) {
+	t.Parallel()
+
+	p := policy.Policy{MaxRetries: 3, MinDelay: 5 * time.Millisecond, MaxDelay: 20 * time.Millisecond}
+
+	overloaded := httpretrier.Overloaded(p)
+	requests := 0
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+
+		if requests < 3 {
+			overloaded.ServeHTTP(w, r)
+
+			return
+		}
+
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	var stats retrier.Stats
+
+	attempt := 0
+
+	err := retrier.Retry(context.Background(), func() error {
+		req, err := http.NewRequestWithContext(context.Background(), http.MethodGet, server.URL, http.NoBody)
+		if err != nil {
+			return err
+		}
+
+		req.Header.Set(httpretrier.AttemptHeader, strconv.Itoa(attempt))
+
+		attempt++
+
+		res, err := server.Client().Do(req)
+		if err != nil {
+			return err
+		}
+
+		defer res.Body.Close()
+
+		return httpretrier.CheckResponse(res)
+	},
+		retrier.WithMaxRetries(3),
+		retrier.WithMinDelay(time.Millisecond),
+		retrier.WithMaxDelay(time.Millisecond),
+		retrier.WithServerHints(true),
+		retrier.WithStats(&stats))
+
+	require.NoError(t, err, "Expected the request to succeed once the server recovers")
+	assert.Equal(t, 15*time.Millisecond, stats.TotalDelay, "Expected the client to wait for the hinted delays")
+}
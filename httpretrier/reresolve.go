@@ -0,0 +1,36 @@
+package httpretrier
+
+import (
+	"context"
+	"net/http"
+)
+
+// Reresolve returns a hook for retrier.WithReresolve closing the idle connections of a transport, so
+// that the next attempt dials a new connection, re-resolving the host, instead of reusing a
+// connection to an address that may have died during the delay, e.g., after a failover.
+//
+// Parameters:
+//   - transport: The transport the attempts are sent through, e.g., *http.Transport. A nil transport
+//     stands for http.DefaultTransport.
+//
+// Returns:
+//   - reresolve: The hook closing the idle connections of transport.
+//
+// Example:
+//
+//	err := retrier.Retry(ctx, operation, retrier.WithReresolve(10*time.Second, httpretrier.Reresolve(nil)))
+func Reresolve(transport http.RoundTripper) (reresolve func(ctx context.Context)) {
+	if transport == nil {
+		transport = http.DefaultTransport
+	}
+
+	closer, ok := transport.(interface{ CloseIdleConnections() })
+
+	reresolve = func(_ context.Context) {
+		if ok {
+			closer.CloseIdleConnections()
+		}
+	}
+
+	return
+}
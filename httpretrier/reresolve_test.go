@@ -0,0 +1,58 @@
+package httpretrier_test
+
+import (
+	"context"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.source.hueristiq.com/retrier/httpretrier"
+)
+
+func TestReresolve(t *testing.T) {
+	t.Parallel()
+
+	var dials atomic.Int32
+
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	server.Config.ConnState = func(_ net.Conn, state http.ConnState) {
+		if state == http.StateNew {
+			dials.Add(1)
+		}
+	}
+
+	server.Start()
+	defer server.Close()
+
+	transport := &http.Transport{}
+	defer transport.CloseIdleConnections()
+
+	client := &http.Client{Transport: transport}
+
+	get := func() {
+		res, err := client.Get(server.URL)
+		require.NoError(t, err, "Expected the request to succeed")
+
+		_, _ = io.Copy(io.Discard, res.Body)
+		_ = res.Body.Close()
+	}
+
+	get()
+	get()
+
+	assert.Equal(t, int32(1), dials.Load(), "Expected the connection to be reused")
+
+	httpretrier.Reresolve(transport)(context.Background())
+
+	get()
+
+	assert.Equal(t, int32(2), dials.Load(), "Expected a new connection after re-resolving")
+}
@@ -0,0 +1,190 @@
+package httpretrier
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+
+	"go.source.hueristiq.com/retrier"
+)
+
+// Transport is an http.RoundTripper retrying requests with the retrier, so that an http.Client
+// retries by swapping its transport. Retryable responses, i.e., 429 Too Many Requests and
+// 5xx ones, are retried after the delay the server asked for, if any, and the last of them is returned
+// as is, as a response rather than an error, once the retry sequence gives up.
+type Transport struct {
+	base http.RoundTripper
+	opts []retrier.Option
+}
+
+// NewTransport returns a Transport sending the attempts of requests through a base RoundTripper.
+//
+// The retry options honor the Retry-After delays of the responses, as the retrier trusts the delays
+// servers ask for unless given retrier.WithServerHints(false), and are followed by the options
+// carried by the context of every request, set through retrier.ContextWithOptions, so that retries
+// can be tuned per request.
+//
+// Parameters:
+//   - base: The RoundTripper sending the attempts. A nil base stands for http.DefaultTransport.
+//   - opts: The retry options.
+//
+// Returns:
+//   - transport: The Transport.
+//
+// Example:
+//
+//	client := &http.Client{Transport: httpretrier.NewTransport(nil, retrier.WithMaxRetries(5))}
+func NewTransport(base http.RoundTripper, opts ...retrier.Option) (transport *Transport) {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+
+	transport = &Transport{base: base, opts: opts}
+
+	return
+}
+
+// RoundTrip implements http.RoundTripper. Requests with an idempotent method, i.e., GET, HEAD, OPTIONS,
+// TRACE, PUT, and DELETE, or with an Idempotency-Key or X-Idempotency-Key header, are retried on any
+// retryable failure. Other requests are retried as non-idempotent operations, see
+// retrier.WithIdempotent: only after failures that definitely happened before the request was sent,
+// such as failures to dial, while responses and other failures, which the server may have acted on,
+// are marked with retrier.Ambiguous and end the retry sequence. Requests whose body cannot be rewound
+// through GetBody are sent once. Retries announce their attempt number in the AttemptHeader.
+//
+// Parameters:
+//   - req: The request to send.
+//
+// Returns:
+//   - res: The response to the last attempt.
+//   - err: The error of the retry sequence, if it did not get a response.
+func (t *Transport) RoundTrip(req *http.Request) (res *http.Response, err error) {
+	if !rewindable(req) {
+		res, err = t.base.RoundTrip(req)
+
+		return
+	}
+
+	ctx := req.Context()
+
+	replayable := idempotent(req)
+
+	opts := make([]retrier.Option, 0, 1+len(t.opts))
+
+	opts = append(opts, retrier.WithIdempotent(replayable))
+	opts = append(opts, t.opts...)
+	opts = append(opts, retrier.OptionsFromContext(ctx)...)
+
+	// The response to the last retryable attempt is kept until the next attempt, to be returned if
+	// the retry sequence gives up on it.
+	var last *http.Response
+
+	attempt := 0
+
+	res, err = retrier.RetryWithData(ctx, func() (res *http.Response, err error) {
+		discard(last)
+
+		last = nil
+
+		r := req
+
+		if attempt > 0 {
+			r = req.Clone(ctx)
+
+			if req.Body != nil && req.Body != http.NoBody {
+				if r.Body, err = req.GetBody(); err != nil {
+					err = retrier.Permanent(err)
+
+					return
+				}
+			}
+
+			r.Header.Set(AttemptHeader, strconv.Itoa(attempt))
+		}
+
+		attempt++
+
+		if res, err = t.base.RoundTrip(r); err != nil {
+			err = retrier.ClassifyNetworkError(err)
+
+			// Replaying a request the server may have acted on may apply it twice.
+			if !replayable && !retrier.IsNotSent(err) {
+				err = retrier.Ambiguous(err)
+			}
+
+			return
+		}
+
+		if err = CheckResponse(res); err != nil {
+			last, res = res, nil
+
+			if !replayable {
+				err = retrier.Ambiguous(err)
+			}
+		}
+
+		return
+	}, opts...)
+
+	var statusErr *StatusError
+
+	if err != nil && last != nil && errors.As(err, &statusErr) {
+		res, err = last, nil
+
+		return
+	}
+
+	discard(last)
+
+	return
+}
+
+// maxDiscard is the maximum number of bytes of the body of a discarded response drained to reuse its
+// connection; the connection of a longer body is closed instead.
+const maxDiscard = 4 << 10
+
+// idempotent reports whether a request can safely be sent more than once.
+//
+// Parameters:
+//   - req: The request.
+//
+// Returns:
+//   - ok: Whether the request has an idempotent method or an idempotency key.
+func idempotent(req *http.Request) (ok bool) {
+	switch req.Method {
+	case "", http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodTrace, http.MethodPut, http.MethodDelete:
+		ok = true
+	default:
+		ok = req.Header.Get("Idempotency-Key") != "" || req.Header.Get("X-Idempotency-Key") != ""
+	}
+
+	return
+}
+
+// rewindable reports whether the body of a request, if any, can be sent again.
+//
+// Parameters:
+//   - req: The request.
+//
+// Returns:
+//   - ok: Whether the request has no body or a body that can be rewound through GetBody.
+func rewindable(req *http.Request) (ok bool) {
+	ok = req.Body == nil || req.Body == http.NoBody || req.GetBody != nil
+
+	return
+}
+
+// discard drains and closes the body of a response that is not returned, so that its connection can
+// be reused.
+//
+// Parameters:
+//   - res: The response, or nil.
+func discard(res *http.Response) {
+	if res == nil {
+		return
+	}
+
+	_, _ = io.CopyN(io.Discard, res.Body, maxDiscard)
+	_ = res.Body.Close()
+}
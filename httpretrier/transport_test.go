@@ -0,0 +1,147 @@
+package httpretrier_test
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.source.hueristiq.com/retrier"
+	"go.source.hueristiq.com/retrier/httpretrier"
+	"go.source.hueristiq.com/retrier/retriertest"
+)
+
+func TestTransport(t *testing.T) {
+	t.Parallel()
+
+	var (
+		mutex    sync.Mutex
+		attempts []string
+		bodies   []string
+	)
+
+	server := retriertest.NewFlakyServer(
+		retriertest.WithFailures(2),
+		retriertest.WithHandler(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			_, _ = io.WriteString(w, "ok")
+		})))
+	defer server.Close()
+
+	record := func(next http.RoundTripper) http.RoundTripper {
+		return roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			var body []byte
+
+			if req.Body != nil {
+				body, _ = io.ReadAll(req.Body)
+				req.Body = io.NopCloser(strings.NewReader(string(body)))
+			}
+
+			mutex.Lock()
+			attempts = append(attempts, req.Header.Get(httpretrier.AttemptHeader))
+			bodies = append(bodies, string(body))
+			mutex.Unlock()
+
+			return next.RoundTrip(req)
+		})
+	}
+
+	client := &http.Client{Transport: httpretrier.NewTransport(record(http.DefaultTransport),
+		retrier.WithMinDelay(time.Millisecond),
+		retrier.WithMaxDelay(time.Millisecond))}
+
+	req, err := http.NewRequestWithContext(context.Background(), http.MethodPut, server.URL, strings.NewReader("payload"))
+	require.NoError(t, err, "Expected the request to be created")
+
+	res, err := client.Do(req)
+	require.NoError(t, err, "Expected the request to succeed")
+
+	body, _ := io.ReadAll(res.Body)
+	_ = res.Body.Close()
+
+	assert.Equal(t, http.StatusOK, res.StatusCode, "Expected the response of the third attempt")
+	assert.Equal(t, "ok", string(body), "Expected the body of the response")
+	assert.Equal(t, []string{"", "1", "2"}, attempts, "Expected the retries to announce their attempt number")
+	assert.Equal(t, []string{"payload", "payload", "payload"}, bodies, "Expected the body to be rewound for every attempt")
+}
+
+func TestTransport_GivesUp(t *testing.T) {
+	t.Parallel()
+
+	server := retriertest.NewFlakyServer(retriertest.WithFailures(10))
+	defer server.Close()
+
+	client := &http.Client{Transport: httpretrier.NewTransport(nil,
+		retrier.WithMaxRetries(2),
+		retrier.WithMinDelay(time.Millisecond),
+		retrier.WithMaxDelay(time.Millisecond))}
+
+	res, err := client.Get(server.URL)
+	require.NoError(t, err, "Expected the last retryable response to be returned as a response")
+
+	_ = res.Body.Close()
+
+	assert.Equal(t, http.StatusServiceUnavailable, res.StatusCode, "Expected the status of the last attempt")
+	assert.Equal(t, 2, server.Requests(), "Expected every attempt to be sent")
+}
+
+func TestTransport_NonIdempotent(t *testing.T) {
+	t.Parallel()
+
+	server := retriertest.NewFlakyServer()
+	defer server.Close()
+
+	client := &http.Client{Transport: httpretrier.NewTransport(nil,
+		retrier.WithMinDelay(time.Millisecond),
+		retrier.WithMaxDelay(time.Millisecond))}
+
+	res, err := client.Post(server.URL, "text/plain", strings.NewReader("payload"))
+	require.NoError(t, err, "Expected the request to get a response")
+
+	_ = res.Body.Close()
+
+	assert.Equal(t, http.StatusServiceUnavailable, res.StatusCode, "Expected a non-idempotent request not to be retried")
+	assert.Equal(t, 1, server.Requests(), "Expected a single attempt")
+
+	req, err := http.NewRequestWithContext(context.Background(), http.MethodPost, server.URL, strings.NewReader("payload"))
+	require.NoError(t, err, "Expected the request to be created")
+
+	req.Header.Set("Idempotency-Key", "key")
+
+	res, err = client.Do(req)
+	require.NoError(t, err, "Expected the request to succeed")
+
+	_ = res.Body.Close()
+
+	assert.Equal(t, http.StatusOK, res.StatusCode, "Expected a request with an idempotency key to be retried")
+
+	closed := retriertest.NewFlakyServer()
+	closed.Close()
+
+	attempts := 0
+
+	client = &http.Client{Transport: httpretrier.NewTransport(roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		attempts++
+
+		return http.DefaultTransport.RoundTrip(req)
+	}),
+		retrier.WithMaxRetries(3),
+		retrier.WithMinDelay(time.Millisecond),
+		retrier.WithMaxDelay(time.Millisecond))}
+
+	_, err = client.Post(closed.URL, "text/plain", strings.NewReader("payload"))
+	require.Error(t, err, "Expected the request to fail")
+
+	assert.Equal(t, 3, attempts, "Expected a non-idempotent request that was not sent to be retried")
+}
+
+// roundTripperFunc adapts a function into an http.RoundTripper.
+type roundTripperFunc func(req *http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
@@ -0,0 +1,7 @@
+// Package ioretrier provides file I/O helpers retrying on transient filesystem errors.
+//
+// Agents writing to network filesystems routinely see calls fail with errors that go away on their
+// own: interrupted system calls (EINTR), resources temporarily unavailable (EAGAIN), or stale NFS file
+// handles (ESTALE). ReadFile and WriteFileAtomic retry on these errors with the configured policy,
+// and give up immediately on any other error, such as a missing file or a permission error.
+package ioretrier
@@ -0,0 +1,156 @@
+package ioretrier
+
+import (
+	"context"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"syscall"
+
+	"go.source.hueristiq.com/retrier"
+)
+
+// IsTransient reports whether a filesystem error is transient, i.e., whether it, or any error it
+// wraps, is EINTR, EAGAIN, or ESTALE.
+//
+// Parameters:
+//   - err: The filesystem error.
+//
+// Returns:
+//   - transient: Whether the operation may succeed if retried.
+func IsTransient(err error) (transient bool) {
+	transient = errors.Is(err, syscall.EINTR) || errors.Is(err, syscall.EAGAIN) || errors.Is(err, syscall.ESTALE)
+
+	return
+}
+
+// ReadFile reads the named file like os.ReadFile, retrying on transient filesystem errors.
+//
+// Parameters:
+//   - ctx:  A context to control the lifetime of the retries.
+//   - name: The name of the file to read.
+//   - opts: Optional retry options.
+//
+// Returns:
+//   - data: The contents of the file.
+//   - err:  The first non-transient error, the error of the last attempt, or the context's error.
+//
+// Example:
+//
+//	data, err := ioretrier.ReadFile(ctx, "/mnt/nfs/state.json", retrier.WithMaxRetries(5))
+func ReadFile(ctx context.Context, name string, opts ...retrier.Option) (data []byte, err error) {
+	data, err = retryTransient(ctx, func() ([]byte, error) {
+		return os.ReadFile(name)
+	}, opts)
+
+	return
+}
+
+// WriteFileAtomic writes data to the named file, creating it with permissions perm if necessary,
+// retrying on transient filesystem errors. The data is written to a temporary file in the same
+// directory, synced, and renamed over the named file, so that readers never observe a partially
+// written file, even if an attempt fails midway.
+//
+// Parameters:
+//   - ctx:  A context to control the lifetime of the retries.
+//   - name: The name of the file to write.
+//   - data: The contents to write.
+//   - perm: The permissions of the file.
+//   - opts: Optional retry options.
+//
+// Returns:
+//   - err: The first non-transient error, the error of the last attempt, or the context's error.
+//
+// Example:
+//
+//	err := ioretrier.WriteFileAtomic(ctx, "/mnt/nfs/state.json", state, 0o644, retrier.WithMaxRetries(5))
+func WriteFileAtomic(ctx context.Context, name string, data []byte, perm fs.FileMode, opts ...retrier.Option) (err error) {
+	_, err = retryTransient(ctx, func() (struct{}, error) {
+		return struct{}{}, writeFileAtomic(name, data, perm)
+	}, opts)
+
+	return
+}
+
+// writeFileAtomic writes data to a temporary file next to the named file and renames it over the
+// named file, removing the temporary file on failure.
+//
+// Parameters:
+//   - name: The name of the file to write.
+//   - data: The contents to write.
+//   - perm: The permissions of the file.
+//
+// Returns:
+//   - err: The error of the first failed step.
+func writeFileAtomic(name string, data []byte, perm fs.FileMode) (err error) {
+	temp, err := os.CreateTemp(filepath.Dir(name), "."+filepath.Base(name)+".tmp-*")
+	if err != nil {
+		return
+	}
+
+	defer func() {
+		if err != nil {
+			_ = temp.Close()
+			_ = os.Remove(temp.Name())
+		}
+	}()
+
+	if _, err = temp.Write(data); err != nil {
+		return
+	}
+
+	if err = temp.Sync(); err != nil {
+		return
+	}
+
+	if err = temp.Chmod(perm); err != nil {
+		return
+	}
+
+	if err = temp.Close(); err != nil {
+		return
+	}
+
+	err = os.Rename(temp.Name(), name)
+
+	return
+}
+
+// retryTransient retries an operation on transient filesystem errors, and stops at the first
+// non-transient one.
+//
+// Parameters:
+//   - ctx:       A context to control the lifetime of the retries.
+//   - operation: The filesystem operation.
+//   - opts:      The retry options.
+//
+// Returns:
+//   - result: The result of the operation.
+//   - err:    The first non-transient error, the error of the last attempt, or the context's error.
+func retryTransient[T any](ctx context.Context, operation retrier.OperationWithData[T], opts []retrier.Option) (result T, err error) {
+	// A non-transient error stops the retries by cancelling their context, and is returned in place of
+	// the cancellation.
+	ctx, cancel := context.WithCancel(ctx)
+
+	defer cancel()
+
+	var permanent error
+
+	result, err = retrier.RetryWithData(ctx, func() (result T, err error) {
+		result, err = operation()
+		if err != nil && !IsTransient(err) {
+			permanent = err
+
+			cancel()
+		}
+
+		return
+	}, opts...)
+
+	if permanent != nil {
+		err = permanent
+	}
+
+	return
+}
@@ -0,0 +1,70 @@
+package ioretrier_test
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.source.hueristiq.com/retrier"
+	"go.source.hueristiq.com/retrier/ioretrier"
+)
+
+func TestIsTransient(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		err       error
+		transient bool
+	}{
+		{&fs.PathError{Op: "read", Path: "state.json", Err: syscall.EINTR}, true},
+		{&fs.PathError{Op: "read", Path: "state.json", Err: syscall.EAGAIN}, true},
+		{fmt.Errorf("sync: %w", syscall.ESTALE), true},
+		{&fs.PathError{Op: "open", Path: "state.json", Err: syscall.ENOENT}, false},
+		{fs.ErrPermission, false},
+	}
+
+	for _, tt := range tests {
+		assert.Equal(t, tt.transient, ioretrier.IsTransient(tt.err), "Unexpected classification of %v", tt.err)
+	}
+}
+
+func TestWriteFileAtomic_ReadFile(t *testing.T) {
+	t.Parallel()
+
+	name := filepath.Join(t.TempDir(), "state.json")
+
+	require.NoError(t, ioretrier.WriteFileAtomic(context.Background(), name, []byte(`{"v":1}`), 0o600), "Expected the file to be written")
+	require.NoError(t, ioretrier.WriteFileAtomic(context.Background(), name, []byte(`{"v":2}`), 0o600), "Expected the file to be replaced")
+
+	data, err := ioretrier.ReadFile(context.Background(), name)
+
+	require.NoError(t, err, "Expected the file to be read")
+	assert.Equal(t, `{"v":2}`, string(data), "Expected the contents of the last write")
+
+	entries, err := os.ReadDir(filepath.Dir(name))
+
+	require.NoError(t, err, "Expected the directory to be listed")
+	assert.Len(t, entries, 1, "Expected no temporary file to be left behind")
+}
+
+func TestReadFile_NonTransient(t *testing.T) {
+	t.Parallel()
+
+	var stats retrier.Stats
+
+	_, err := ioretrier.ReadFile(context.Background(), filepath.Join(t.TempDir(), "missing.json"),
+		retrier.WithMaxRetries(5),
+		retrier.WithMinDelay(time.Second),
+		retrier.WithMaxDelay(time.Second),
+		retrier.WithStats(&stats))
+
+	require.ErrorIs(t, err, fs.ErrNotExist, "Expected the non-transient error to be returned")
+	assert.Equal(t, 1, stats.Attempts, "Expected non-transient errors not to be retried")
+}
@@ -0,0 +1,123 @@
+package retrier
+
+import (
+	"context"
+	"iter"
+
+	"go.source.hueristiq.com/retrier/backoff"
+)
+
+// Attempts returns an iterator over the attempts of a retry sequence, for callers that need full
+// control of the loop body, e.g., to select over several channels or resume a streaming read, while
+// reusing the backoff, jitter, and limits of the options. Every iteration is an attempt: breaking out
+// of the loop ends the retry sequence, e.g., once the attempt succeeds, and continuing waits for the
+// backoff delay before the next attempt. The sequence ends once the attempts are exhausted, the
+// maximum elapsed time would be exceeded, or ctx is done, which the caller tells apart through
+// ctx.Err().
+//
+// Only the options governing the schedule of the attempts apply, as the iterator does not see their
+// errors: WithMaxRetries, WithMinDelay, WithMaxDelay, WithBackoff, WithStrategy,
+// WithBackoffAttemptOffset, WithMaxElapsedTime, and WithClock. As it cannot report errors either, an
+// invalid configuration falls back to a single attempt.
+//
+// Parameters:
+//   - ctx:  A context to control the lifetime of the retry sequence.
+//   - opts: Optional configuration options.
+//
+// Returns:
+//   - attempts: The iterator over the attempts. The same Attempt is yielded for every attempt, with its
+//     Number updated, and must not be retained beyond the iteration.
+//
+// Example:
+//
+//	for attempt := range retrier.Attempts(ctx, retrier.WithMaxRetries(5)) {
+//	    if err = stream.Resume(ctx, offset); err == nil {
+//	        break
+//	    }
+//
+//	    log.Printf("attempt %d failed: %v", attempt.Number, err)
+//	}
+func Attempts(ctx context.Context, opts ...Option) (attempts iter.Seq[*Attempt]) {
+	attempts = New(opts...).Attempts(ctx)
+
+	return
+}
+
+// Attempts returns an iterator over the attempts of a retry sequence, as the Attempts function does,
+// with the policy of the Retrier.
+//
+// Parameters:
+//   - ctx: A context to control the lifetime of the retry sequence.
+//
+// Returns:
+//   - attempts: The iterator over the attempts.
+func (r *Retrier) Attempts(ctx context.Context) (attempts iter.Seq[*Attempt]) {
+	attempts = func(yield func(*Attempt) bool) {
+		current := &Attempt{SequenceID: newSequenceID()}
+
+		if r.err != nil {
+			yield(current)
+
+			return
+		}
+
+		cfg := r.cfg
+
+		strategy := cfg.backoff
+
+		if cfg.newStrategy != nil {
+			strategy = backoff.FromStrategy(cfg.newStrategy(cfg.minDelay, cfg.maxDelay))
+		}
+
+		maxRetries := cfg.maxRetries
+
+		// A request marked as not to be retried gets a single attempt.
+		if NoRetry(ctx) && (maxRetries < 0 || maxRetries > 1) {
+			maxRetries = 1
+		}
+
+		start := cfg.clock.Now()
+
+		for attempt := 0; maxRetries < 0 || attempt < maxRetries; attempt++ {
+			if ctx.Err() != nil {
+				return
+			}
+
+			current.end()
+
+			current.Number = attempt
+
+			if !yield(current) {
+				return
+			}
+
+			// The retry sequence ends without waiting after its last attempt.
+			if maxRetries >= 0 && attempt+1 >= maxRetries {
+				return
+			}
+
+			b, unresolved := cfg.resolveDelay(strategy(cfg.minDelay, cfg.maxDelay, attempt+cfg.backoffAttemptOffset), nil)
+			if unresolved != nil {
+				return
+			}
+
+			// Give up if the next attempt would start after the maximum elapsed time.
+			if cfg.maxElapsedTime > 0 && cfg.clock.Now().Sub(start)+b > cfg.maxElapsedTime {
+				return
+			}
+
+			timer := cfg.clock.NewTimer(b)
+
+			select {
+			case <-timer.C():
+				timer.Stop()
+			case <-ctx.Done():
+				timer.Stop()
+
+				return
+			}
+		}
+	}
+
+	return
+}
@@ -0,0 +1,170 @@
+package jitter
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// StrategyInfo describes a backoff or jitter strategy by name and parameters, so that logs, traces,
+// and configuration tooling can report exactly which strategy governed a retry sequence.
+//
+// Fields:
+//   - Name: The name of the strategy, e.g., "exponential".
+//   - Parameters: The parameters of the strategy, keyed by parameter name.
+type StrategyInfo struct {
+	Name       string            `json:"name"`
+	Parameters map[string]string `json:"parameters,omitempty"`
+}
+
+// String returns the strategy name followed by its parameters in key order, e.g.,
+// "exponential(jitter=full, multiplier=2)".
+//
+// Returns:
+//   - description: The textual description of the strategy.
+func (i StrategyInfo) String() (description string) {
+	if len(i.Parameters) == 0 {
+		description = i.Name
+
+		return
+	}
+
+	keys := make([]string, 0, len(i.Parameters))
+
+	for key := range i.Parameters {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+
+	parameters := make([]string, 0, len(keys))
+
+	for _, key := range keys {
+		parameters = append(parameters, key+"="+i.Parameters[key])
+	}
+
+	description = i.Name + "(" + strings.Join(parameters, ", ") + ")"
+
+	return
+}
+
+// Describe returns the StrategyInfo of a jitter strategy: its own description if it implements a
+// Describe() StrategyInfo method, as the built-in strategies do, its name if it implements
+// fmt.Stringer, or "custom" otherwise. A nil strategy is described as "none".
+//
+// Parameters:
+//   - strategy: The jitter strategy to describe.
+//
+// Returns:
+//   - info: The StrategyInfo describing the strategy.
+//
+// Example:
+//
+//	info := jitter.Describe(jitter.NewSymmetric(0.2))
+//	// info.String() will be "symmetric(fraction=0.2)".
+func Describe(strategy Strategy) (info StrategyInfo) {
+	switch described := strategy.(type) {
+	case nil:
+		info = StrategyInfo{Name: "none"}
+	case interface{ Describe() StrategyInfo }:
+		info = described.Describe()
+	case interface{ String() string }:
+		info = StrategyInfo{Name: described.String()}
+	default:
+		info = StrategyInfo{Name: "custom"}
+	}
+
+	return
+}
+
+// describe returns the StrategyInfo of a built-in jitter strategy, with the floor and ceiling of its
+// Configuration among its parameters when they are set.
+//
+// Parameters:
+//   - name:       The name of the strategy.
+//   - cfg:        The Configuration of the strategy.
+//   - parameters: The parameters of the strategy itself, which may be nil.
+//
+// Returns:
+//   - info: The StrategyInfo describing the strategy.
+func describe(name string, cfg *Configuration, parameters map[string]string) (info StrategyInfo) {
+	info = StrategyInfo{Name: name, Parameters: parameters}
+
+	if cfg.floor == 0 && cfg.ceiling <= 0 {
+		return
+	}
+
+	if info.Parameters == nil {
+		info.Parameters = map[string]string{}
+	}
+
+	if cfg.floor != 0 {
+		info.Parameters["floor"] = cfg.floor.String()
+	}
+
+	if cfg.ceiling > 0 {
+		info.Parameters["ceiling"] = cfg.ceiling.String()
+	}
+
+	return
+}
+
+// Describe returns the StrategyInfo of the equal jitter strategy.
+//
+// Returns:
+//   - info: The StrategyInfo describing the strategy.
+func (s *equalStrategy) Describe() (info StrategyInfo) {
+	info = describe("equal", s.cfg, nil)
+
+	return
+}
+
+// String implements fmt.Stringer by returning the textual form of the strategy's StrategyInfo.
+//
+// Returns:
+//   - description: The textual description of the strategy.
+func (s *equalStrategy) String() (description string) {
+	description = s.Describe().String()
+
+	return
+}
+
+// Describe returns the StrategyInfo of the full jitter strategy.
+//
+// Returns:
+//   - info: The StrategyInfo describing the strategy.
+func (s *fullStrategy) Describe() (info StrategyInfo) {
+	info = describe("full", s.cfg, nil)
+
+	return
+}
+
+// String implements fmt.Stringer by returning the textual form of the strategy's StrategyInfo.
+//
+// Returns:
+//   - description: The textual description of the strategy.
+func (s *fullStrategy) String() (description string) {
+	description = s.Describe().String()
+
+	return
+}
+
+// Describe returns the StrategyInfo of the symmetric jitter strategy, including its fraction.
+//
+// Returns:
+//   - info: The StrategyInfo describing the strategy.
+func (s *symmetricStrategy) Describe() (info StrategyInfo) {
+	info = describe("symmetric", s.cfg, map[string]string{"fraction": strconv.FormatFloat(s.fraction, 'g', -1, 64)})
+
+	return
+}
+
+// String implements fmt.Stringer by returning the textual form of the strategy's StrategyInfo.
+//
+// Returns:
+//   - description: The textual description of the strategy.
+func (s *symmetricStrategy) String() (description string) {
+	description = s.Describe().String()
+
+	return
+}
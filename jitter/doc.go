@@ -8,7 +8,7 @@
 // chance of synchronized retries (the "thundering herd" problem) and
 // distributing the system load more evenly over time.
 //
-// This package offers three jitter strategies:
+// This package offers four jitter strategies:
 //  1. **Equal Jitter**: Adds moderate randomness to the retry interval by
 //     selecting a random value within half of the original backoff duration.
 //     This is suitable for scenarios where you want some consistency with
@@ -21,4 +21,20 @@
 //     by the previous backoff value, keeping the retry interval bounded
 //     within a specified range. This is useful for preventing unbounded
 //     exponential growth in retry delays.
+//  4. **Symmetric Jitter**: Spreads the retry interval uniformly around the
+//     original backoff duration, by up to a given fraction in both directions.
+//     This keeps the nominal backoff as the average retry interval.
+//
+// Every strategy accepts options, such as WithFloor and WithCeiling, which bound
+// the jittered duration, and WithSource, which selects the source of randomness:
+// CryptoSource (the default) for unpredictable values, FastSource for cheap
+// values in tight retry loops, or SeededSource for reproducible values in tests
+// and simulations.
+//
+// The Equal, Full, and Symmetric strategies are also available as values implementing the Strategy
+// interface, whose Bounds method declares the range of the durations they produce, so that worst-case
+// delays can be reasoned about. Validate checks that a Strategy, e.g., a user-provided one, keeps
+// its bounds. The built-in Strategy values implement fmt.Stringer and a Describe method returning a
+// StrategyInfo, also reported by the Describe function for any Strategy. Register and Get resolve
+// Strategy values by name, including custom ones, for configuration systems such as the policy package.
 package jitter
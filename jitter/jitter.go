@@ -1,11 +1,127 @@
 package jitter
 
 import (
-	"crypto/rand"
-	"math/big"
+	"math"
 	"time"
 )
 
+// Configuration holds the settings applied to jittered durations.
+//
+// Fields:
+//   - floor: The minimum jittered duration.
+//   - ceiling: The maximum jittered duration, or 0 if the jittered duration is not capped.
+//   - source: The Source random values are drawn from.
+type Configuration struct {
+	floor   time.Duration
+	ceiling time.Duration
+	source  Source
+}
+
+// apply clamps a jittered duration to the configured settings.
+//
+// Parameters:
+//   - jitter: The jittered duration.
+//
+// Returns:
+//   - applied: The jittered duration, lowered to the ceiling if it is above it, and raised to the floor
+//     if it is below it.
+func (c *Configuration) apply(jitter time.Duration) (applied time.Duration) {
+	applied = jitter
+
+	if c.ceiling > 0 {
+		applied = min(applied, c.ceiling)
+	}
+
+	applied = max(applied, c.floor)
+
+	return
+}
+
+// Option is a function type used to modify the Configuration of a jitter strategy.
+//
+// Parameters:
+//   - *Configuration: A pointer to the Configuration struct that allows modification of its fields.
+type Option func(*Configuration)
+
+// WithFloor sets the minimum jittered duration. Jitter strategies, full jitter in particular, can
+// produce near-zero durations; the floor guarantees a minimum recovery gap for downstreams that
+// require one.
+//
+// Parameters:
+//   - floor: The minimum jittered duration.
+//
+// Returns:
+//   - Option: A functional option that modifies the Configuration to set the floor field.
+//
+// Example:
+//
+//	jitteredBackoff := jitter.Full(10*time.Second, jitter.WithFloor(time.Second))
+//	// jitteredBackoff will be somewhere between 1 second and 10 seconds.
+func WithFloor(floor time.Duration) Option {
+	return func(c *Configuration) {
+		c.floor = floor
+	}
+}
+
+// WithCeiling sets the maximum jittered duration. Strategies that can produce durations longer than
+// the original backoff, such as Symmetric, use it to clamp the jittered duration to a maximum delay.
+// The floor takes precedence over the ceiling.
+//
+// Parameters:
+//   - ceiling: The maximum jittered duration.
+//
+// Returns:
+//   - Option: A functional option that modifies the Configuration to set the ceiling field.
+//
+// Example:
+//
+//	jitteredBackoff := jitter.Symmetric(10*time.Second, 0.5, jitter.WithCeiling(12*time.Second))
+//	// jitteredBackoff will be somewhere between 5 seconds and 12 seconds.
+func WithCeiling(ceiling time.Duration) Option {
+	return func(c *Configuration) {
+		c.ceiling = ceiling
+	}
+}
+
+// WithSource sets the Source random values are drawn from. By default, jitter strategies use
+// CryptoSource; FastSource is a much cheaper alternative for tight retry loops.
+//
+// Parameters:
+//   - source: The Source to draw random values from. A nil source keeps the default.
+//
+// Returns:
+//   - Option: A functional option that modifies the Configuration to set the source field.
+//
+// Example:
+//
+//	jitteredBackoff := jitter.Full(10*time.Second, jitter.WithSource(jitter.FastSource()))
+func WithSource(source Source) Option {
+	return func(c *Configuration) {
+		if source != nil {
+			c.source = source
+		}
+	}
+}
+
+// configure applies options to a new Configuration.
+//
+// Parameters:
+//   - opts: The options to apply.
+//
+// Returns:
+//   - cfg: The resulting Configuration.
+func configure(opts []Option) (cfg *Configuration) {
+	cfg = &Configuration{
+		source: CryptoSource(),
+	}
+
+	for _, opt := range opts {
+		opt(cfg)
+	}
+
+	return
+}
+
 // Equal applies an equal jitter strategy to the provided backoff duration.
 // This method ensures moderate randomness by adding a jitter value that is
 // calculated as a random number within half of the original backoff time.
@@ -20,6 +136,7 @@ import (
 //   - backoff: The original backoff duration to which jitter will be applied.
 //     This represents the base amount of time to wait before retrying
 //     an operation.
+//   - opts: Optional configuration options, such as WithFloor.
 //
 // Returns:
 //   - jitter: The resulting backoff duration after applying equal jitter.
@@ -31,10 +148,12 @@ import (
 //	backoff := 10 * time.Second
 //	jitteredBackoff := jitter.Equal(backoff)
 //	// jitteredBackoff will be somewhere between 5 seconds and 10 seconds.
-func Equal(backoff time.Duration) (jitter time.Duration) {
+func Equal(backoff time.Duration, opts ...Option) (jitter time.Duration) {
 	midpoint := backoff / 2
 
-	jitter = midpoint + getRandomDuration(midpoint)
+	cfg := configure(opts)
+
+	jitter = cfg.apply(midpoint + getRandomDuration(cfg.source, midpoint))
 
 	return
 }
@@ -50,6 +169,7 @@ func Equal(backoff time.Duration) (jitter time.Duration) {
 //
 // Parameters:
 //   - backoff: The base backoff duration to be randomized.
+//   - opts: Optional configuration options, such as WithFloor.
 //
 // Returns:
 //   - jitter: A completely random backoff duration between 0 and the original
@@ -60,8 +180,10 @@ func Equal(backoff time.Duration) (jitter time.Duration) {
 //	backoff := 10 * time.Second
 //	jitteredBackoff := jitter.Full(backoff)
 //	// jitteredBackoff will be somewhere between 0 and 10 seconds.
-func Full(backoff time.Duration) (jitter time.Duration) {
-	jitter = getRandomDuration(backoff)
+func Full(backoff time.Duration, opts ...Option) (jitter time.Duration) {
+	cfg := configure(opts)
+
+	jitter = cfg.apply(getRandomDuration(cfg.source, backoff))
 
 	return
 }
@@ -81,6 +203,7 @@ func Full(backoff time.Duration) (jitter time.Duration) {
 //   - maxDelay: The maximum allowable delay duration for the backoff.
 //   - previous: The previous backoff duration, used to calculate the new
 //     jittered duration.
+//   - opts: Optional configuration options, such as WithFloor.
 //
 // Returns:
 //   - jitter: A decorrelated jittered duration that is within the range of
@@ -95,32 +218,108 @@ func Full(backoff time.Duration) (jitter time.Duration) {
 //	jitteredBackoff := jitter.Decorrelated(minDelay, maxDelay, previous)
 //	// jitteredBackoff will be somewhere between minDelay and maxDelay,
 //	// bounded by the previous backoff value.
-func Decorrelated(minDelay, maxDelay, previous time.Duration) (jitter time.Duration) {
+func Decorrelated(minDelay, maxDelay, previous time.Duration, opts ...Option) (jitter time.Duration) {
 	if previous == 0 {
 		previous = minDelay
 	}
 
-	jitter = getRandomDuration(previous * 3)
+	cfg := configure(opts)
+
+	jitter = getRandomDuration(cfg.source, saturatingMul(previous, 3))
 
-	jitter += minDelay
+	if jitter <= math.MaxInt64-minDelay {
+		jitter += minDelay
+	} else {
+		jitter = math.MaxInt64
+	}
 
 	if jitter > maxDelay {
 		jitter = maxDelay
 	}
 
+	jitter = cfg.apply(jitter)
+
 	return
 }
 
-// getRandomDuration returns a random time.Duration value between 0 and the
-// provided maximum duration. This function uses a cryptographically secure
-// random number generator (CSPRNG) to ensure that the random values are
-// highly unpredictable.
+// saturatingMul multiplies a duration by a positive factor with saturation semantics, capping the
+// product at the largest representable time.Duration instead of overflowing. It mirrors backoff.SafeMul,
+// which the jitter package cannot import.
 //
-// By using a CSPRNG, this method guarantees stronger randomness compared
-// to traditional pseudo-random number generators, which is particularly
-// useful in security-sensitive applications.
+// Parameters:
+//   - d:      The duration to multiply.
+//   - factor: The positive factor to multiply the duration by.
+//
+// Returns:
+//   - product: The saturated product of d and factor.
+func saturatingMul(d time.Duration, factor int64) (product time.Duration) {
+	if d > math.MaxInt64/time.Duration(factor) {
+		product = math.MaxInt64
+
+		return
+	}
+
+	product = d * time.Duration(factor)
+
+	return
+}
+
+// Symmetric applies a two-sided (plus/minus) jitter strategy to the provided backoff duration.
+// The jittered duration is drawn uniformly from [backoff*(1-fraction), backoff*(1+fraction)],
+// i.e., spread symmetrically around the nominal backoff, as done by Kubernetes-style jitter.
+//
+// Unlike the other strategies, symmetric jitter keeps the nominal backoff as the average duration,
+// which keeps schedules predictable while still de-synchronizing clients. Since the jittered
+// duration can exceed the original backoff, use WithCeiling to clamp it to a maximum delay.
 //
 // Parameters:
+//   - backoff: The nominal backoff duration to be randomized.
+//   - fraction: The maximum relative deviation from the nominal backoff, clamped to [0, 1].
+//   - opts: Optional configuration options, such as WithFloor and WithCeiling.
+//
+// Returns:
+//   - jitter: A random duration between backoff*(1-fraction) and backoff*(1+fraction).
+//
+// Example:
+//
+//	backoff := 10 * time.Second
+//	jitteredBackoff := jitter.Symmetric(backoff, 0.2)
+//	// jitteredBackoff will be somewhere between 8 seconds and 12 seconds.
+func Symmetric(backoff time.Duration, fraction float64, opts ...Option) (jitter time.Duration) {
+	cfg := configure(opts)
+
+	if backoff <= 0 || !(fraction > 0) {
+		jitter = cfg.apply(max(backoff, 0))
+
+		return
+	}
+
+	spread := time.Duration(float64(backoff) * min(fraction, 1))
+
+	width := time.Duration(math.MaxInt64)
+
+	if spread <= math.MaxInt64/2 {
+		width = 2 * spread
+	}
+
+	low := backoff - spread
+
+	jitter = time.Duration(math.MaxInt64)
+
+	if random := getRandomDuration(cfg.source, width); random <= math.MaxInt64-low {
+		jitter = low + random
+	}
+
+	jitter = cfg.apply(jitter)
+
+	return
+}
+
+// getRandomDuration returns a random time.Duration value between 0 and the
+// provided maximum duration, drawn from the provided Source.
+//
+// Parameters:
+//   - source: The Source to draw the random value from.
 //   - maxDuration: The maximum duration from which to select a random value.
 //     This must be a positive value greater than zero.
 //
@@ -131,19 +330,14 @@ func Decorrelated(minDelay, maxDelay, previous time.Duration) (jitter time.Durat
 //
 // Example:
 //
-//	randomDuration := getRandomDuration(10 * time.Second)
+//	randomDuration := getRandomDuration(CryptoSource(), 10 * time.Second)
 //	// randomDuration will be a random time.Duration between 0 and 10 seconds.
-func getRandomDuration(maxDuration time.Duration) (duration time.Duration) {
+func getRandomDuration(source Source, maxDuration time.Duration) (duration time.Duration) {
 	if maxDuration <= 0 {
 		return 0
 	}
 
-	n, err := rand.Int(rand.Reader, big.NewInt(int64(maxDuration)))
-	if err != nil {
-		return maxDuration
-	}
-
-	duration = time.Duration(n.Int64())
+	duration = time.Duration(source.Int64N(int64(maxDuration)))
 
 	return
 }
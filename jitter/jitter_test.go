@@ -1,10 +1,13 @@
 package jitter_test
 
 import (
+	"fmt"
+	"math"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"go.source.hueristiq.com/retrier/jitter"
 )
 
@@ -107,3 +110,231 @@ func TestDecorrelatedJitter_MaxBoundary(t *testing.T) {
 	assert.GreaterOrEqual(t, jittered, minDelay, "Jittered duration should be at least the minimum")
 	assert.LessOrEqual(t, jittered, maxDelay, "Jittered duration should not exceed the maximum")
 }
+
+func TestDecorrelatedJitter_Saturation(t *testing.T) {
+	t.Parallel()
+
+	previous := time.Duration(math.MaxInt64 / 2)
+
+	for range 100 {
+		jittered := jitter.Decorrelated(time.Second, time.Hour, previous)
+
+		assert.Equal(t, time.Hour, jittered, "Three times the previous duration should saturate rather than overflow")
+	}
+}
+
+func TestFullJitter_WithFloor(t *testing.T) {
+	t.Parallel()
+
+	backoff := 10 * time.Second
+	floor := 2 * time.Second
+
+	for range 100 {
+		jittered := jitter.Full(backoff, jitter.WithFloor(floor))
+
+		assert.GreaterOrEqual(t, jittered, floor, "Jittered duration should be at least the floor")
+		assert.Less(t, jittered, backoff, "Jittered duration should be less than the original backoff")
+	}
+}
+
+func TestEqualJitter_WithFloor(t *testing.T) {
+	t.Parallel()
+
+	jittered := jitter.Equal(0, jitter.WithFloor(time.Second))
+
+	assert.Equal(t, time.Second, jittered, "Jittered duration should be raised to the floor")
+}
+
+func TestDecorrelatedJitter_WithFloor(t *testing.T) {
+	t.Parallel()
+
+	minDelay := 1 * time.Millisecond
+	maxDelay := 10 * time.Second
+	previous := 1 * time.Millisecond
+	floor := time.Second
+
+	for range 100 {
+		jittered := jitter.Decorrelated(minDelay, maxDelay, previous, jitter.WithFloor(floor))
+
+		assert.GreaterOrEqual(t, jittered, floor, "Jittered duration should be at least the floor")
+	}
+}
+
+func TestSymmetricJitter(t *testing.T) {
+	t.Parallel()
+
+	backoff := 10 * time.Second
+
+	for range 100 {
+		jittered := jitter.Symmetric(backoff, 0.2)
+
+		assert.GreaterOrEqual(t, jittered, 8*time.Second, "Jittered duration should be at least backoff*(1-fraction)")
+		assert.LessOrEqual(t, jittered, 12*time.Second, "Jittered duration should be at most backoff*(1+fraction)")
+	}
+}
+
+func TestSymmetricJitter_WithCeiling(t *testing.T) {
+	t.Parallel()
+
+	backoff := 10 * time.Second
+
+	for range 100 {
+		jittered := jitter.Symmetric(backoff, 1, jitter.WithCeiling(backoff))
+
+		assert.GreaterOrEqual(t, jittered, 0*time.Second, "Jittered duration should be at least 0")
+		assert.LessOrEqual(t, jittered, backoff, "Jittered duration should be clamped to the ceiling")
+	}
+}
+
+func TestSymmetricJitter_EdgeCases(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, 0*time.Second, jitter.Symmetric(0, 0.5), "Jittered duration should be 0 when the backoff is 0")
+	assert.Equal(t, time.Second, jitter.Symmetric(time.Second, 0), "Jittered duration should be the backoff when the fraction is 0")
+	assert.Equal(t, time.Second, jitter.Symmetric(time.Second, math.NaN()), "Jittered duration should be the backoff when the fraction is NaN")
+	assert.Positive(t, jitter.Symmetric(math.MaxInt64, 1), "Jittered duration should not overflow")
+}
+
+func TestFullJitter_FastSource(t *testing.T) {
+	t.Parallel()
+
+	backoff := 10 * time.Second
+
+	for range 100 {
+		jittered := jitter.Full(backoff, jitter.WithSource(jitter.FastSource()))
+
+		assert.GreaterOrEqual(t, jittered, 0*time.Second, "Jittered duration should be at least 0")
+		assert.Less(t, jittered, backoff, "Jittered duration should be less than the original backoff")
+	}
+}
+
+func TestSeededSource(t *testing.T) {
+	t.Parallel()
+
+	draw := func(seed uint64) (delays []time.Duration) {
+		source := jitter.WithSource(jitter.SeededSource(seed))
+
+		for range 20 {
+			delays = append(delays, jitter.Full(10*time.Second, source))
+		}
+
+		return
+	}
+
+	assert.Equal(t, draw(42), draw(42), "Sources with the same seed should draw the same jitter")
+	assert.NotEqual(t, draw(42), draw(43), "Sources with different seeds should draw different jitter")
+
+	for _, jittered := range draw(7) {
+		assert.GreaterOrEqual(t, jittered, 0*time.Second, "Jittered duration should be at least 0")
+		assert.Less(t, jittered, 10*time.Second, "Jittered duration should be less than the original backoff")
+	}
+}
+
+func TestRegistry(t *testing.T) {
+	t.Parallel()
+
+	builtin, ok := jitter.Get("full")
+
+	require.True(t, ok, "Expected the built-in strategies to be registered")
+
+	lower, upper := builtin.Bounds(time.Second)
+
+	assert.Equal(t, time.Duration(0), lower)
+	assert.Equal(t, time.Second, upper)
+
+	require.NoError(t, jitter.Register("test-gentle", func() jitter.Strategy {
+		return jitter.NewSymmetric(0.1)
+	}))
+
+	gentle, ok := jitter.Get("test-gentle")
+
+	require.True(t, ok, "Expected a registered strategy to be resolved")
+	assert.InDelta(t, float64(time.Second), float64(gentle.Apply(time.Second)), float64(100*time.Millisecond))
+	assert.Contains(t, jitter.Names(), "test-gentle")
+
+	full := func() jitter.Strategy {
+		return jitter.NewFull()
+	}
+
+	require.ErrorIs(t, jitter.Register("test-gentle", full), jitter.ErrAlreadyRegistered)
+	require.ErrorIs(t, jitter.Register("", full), jitter.ErrInvalidRegistration)
+}
+
+func BenchmarkFullJitter_CryptoSource(b *testing.B) {
+	source := jitter.WithSource(jitter.CryptoSource())
+
+	b.ReportAllocs()
+
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			jitter.Full(time.Second, source)
+		}
+	})
+}
+
+func BenchmarkFullJitter_FastSource(b *testing.B) {
+	source := jitter.WithSource(jitter.FastSource())
+
+	b.ReportAllocs()
+
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			jitter.Full(time.Second, source)
+		}
+	})
+}
+
+func TestStrategy_Bounds(t *testing.T) {
+	t.Parallel()
+
+	backoff := 10 * time.Second
+
+	strategies := map[string]jitter.Strategy{
+		"equal":     jitter.NewEqual(),
+		"full":      jitter.NewFull(jitter.WithFloor(time.Second)),
+		"symmetric": jitter.NewSymmetric(0.2, jitter.WithCeiling(11*time.Second)),
+	}
+
+	for name, strategy := range strategies {
+		assert.NoError(t, jitter.Validate(strategy, backoff, 1000), "Expected %s jitter to keep its bounds", name)
+		assert.NoError(t, jitter.Validate(strategy, 0, 10), "Expected %s jitter to keep its bounds for a zero backoff", name)
+	}
+
+	lower, upper := jitter.NewSymmetric(0.2, jitter.WithCeiling(11*time.Second)).Bounds(backoff)
+
+	assert.Equal(t, 8*time.Second, lower, "Unexpected lower bound of symmetric jitter")
+	assert.Equal(t, 11*time.Second, upper, "Expected the ceiling to bound symmetric jitter")
+
+	assert.ErrorIs(t, jitter.Validate(liar{}, backoff, 10), jitter.ErrOutOfBounds, "Expected a strategy breaking its bounds to be reported")
+}
+
+type liar struct{}
+
+func (liar) Apply(backoff time.Duration) time.Duration {
+	return 2 * backoff
+}
+
+func (liar) Bounds(backoff time.Duration) (time.Duration, time.Duration) {
+	return 0, backoff
+}
+
+func TestDescribe(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		strategy jitter.Strategy
+		expected string
+	}{
+		{jitter.NewFull(), "full"},
+		{jitter.NewEqual(jitter.WithFloor(time.Second)), "equal(floor=1s)"},
+		{jitter.NewSymmetric(0.25, jitter.WithCeiling(time.Minute)), "symmetric(ceiling=1m0s, fraction=0.25)"},
+		{nil, "none"},
+	}
+
+	for _, tt := range tests {
+		assert.Equal(t, tt.expected, jitter.Describe(tt.strategy).String(), "Unexpected strategy description")
+	}
+
+	assert.Equal(t, "full", fmt.Sprint(jitter.NewFull()), "Expected the strategies to implement fmt.Stringer")
+	assert.Equal(t, jitter.StrategyInfo{Name: "symmetric", Parameters: map[string]string{"fraction": "0.5"}}, jitter.Describe(jitter.NewSymmetric(0.5)))
+}
@@ -0,0 +1,102 @@
+package jitter
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+)
+
+var (
+	// ErrAlreadyRegistered is returned by Register for a name already in use.
+	ErrAlreadyRegistered = errors.New("jitter strategy already registered")
+	// ErrInvalidRegistration is returned by Register for an empty name or a nil factory.
+	ErrInvalidRegistration = errors.New("invalid jitter strategy registration")
+)
+
+// registry holds the jitter strategies resolvable by name, starting with the built-in strategies that
+// need no parameters.
+var registry = struct {
+	mutex     sync.RWMutex
+	factories map[string]func() Strategy
+}{
+	factories: map[string]func() Strategy{
+		"full":  func() Strategy { return NewFull() },
+		"equal": func() Strategy { return NewEqual() },
+	},
+}
+
+// Register makes a jitter strategy resolvable by name through Get, so that configuration systems,
+// such as the policy package, can refer to custom strategies by name. The built-in strategies are
+// registered as "full" and "equal".
+//
+// Parameters:
+//   - name:    The name of the strategy.
+//   - factory: The function creating the strategy, called by every Get.
+//
+// Returns:
+//   - err: An error wrapping ErrInvalidRegistration if the name is empty or the factory nil, or
+//     ErrAlreadyRegistered if the name is already in use.
+//
+// Example:
+//
+//	err := jitter.Register("gentle", func() jitter.Strategy { return jitter.NewSymmetric(0.1) })
+func Register(name string, factory func() Strategy) (err error) {
+	if name == "" || factory == nil {
+		err = fmt.Errorf("%w: empty name or nil factory", ErrInvalidRegistration)
+
+		return
+	}
+
+	registry.mutex.Lock()
+	defer registry.mutex.Unlock()
+
+	if _, ok := registry.factories[name]; ok {
+		err = fmt.Errorf("%w: %q", ErrAlreadyRegistered, name)
+
+		return
+	}
+
+	registry.factories[name] = factory
+
+	return
+}
+
+// Get creates the jitter strategy registered under a name.
+//
+// Parameters:
+//   - name: The name of the strategy.
+//
+// Returns:
+//   - strategy: The strategy created by the factory registered under name.
+//   - ok:       Whether a strategy is registered under name.
+func Get(name string) (strategy Strategy, ok bool) {
+	registry.mutex.RLock()
+	factory, ok := registry.factories[name]
+	registry.mutex.RUnlock()
+
+	if ok {
+		strategy = factory()
+	}
+
+	return
+}
+
+// Names returns the names of the registered jitter strategies, sorted.
+//
+// Returns:
+//   - names: The names of the registered strategies.
+func Names() (names []string) {
+	registry.mutex.RLock()
+	defer registry.mutex.RUnlock()
+
+	names = make([]string, 0, len(registry.factories))
+
+	for name := range registry.factories {
+		names = append(names, name)
+	}
+
+	slices.Sort(names)
+
+	return
+}
@@ -0,0 +1,115 @@
+package jitter
+
+import (
+	"crypto/rand"
+	"math/big"
+	mathrand "math/rand/v2"
+	"sync"
+)
+
+// Source is a source of uniformly distributed random numbers used by jitter strategies.
+// Implementations must be safe for concurrent use.
+type Source interface {
+	// Int64N returns a uniformly distributed random number in the half-open interval [0, n).
+	// It is only called with n > 0.
+	Int64N(n int64) (random int64)
+}
+
+// cryptoSource is a Source backed by crypto/rand.
+type cryptoSource struct{}
+
+// Int64N implements Source using crypto/rand. If the system's random number generator fails,
+// it returns n-1, the longest possible duration, erring on the side of backing off more.
+func (cryptoSource) Int64N(n int64) (random int64) {
+	r, err := rand.Int(rand.Reader, big.NewInt(n))
+	if err != nil {
+		random = n - 1
+
+		return
+	}
+
+	random = r.Int64()
+
+	return
+}
+
+// CryptoSource returns a Source backed by crypto/rand, a cryptographically secure random number
+// generator (CSPRNG). It is the default source of every jitter strategy: its values are highly
+// unpredictable, which matters when retry timing must not be guessable, but each value costs a
+// system call-backed read and a big.Int allocation.
+//
+// Returns:
+//   - source: The crypto/rand-backed Source.
+func CryptoSource() (source Source) {
+	source = cryptoSource{}
+
+	return
+}
+
+// fastSource is a Source backed by math/rand/v2.
+type fastSource struct{}
+
+// Int64N implements Source using the top-level functions of math/rand/v2.
+func (fastSource) Int64N(n int64) (random int64) {
+	random = mathrand.Int64N(n) //nolint:gosec // FastSource trades unpredictability for speed by design.
+
+	return
+}
+
+// FastSource returns a Source backed by math/rand/v2. Its top-level functions draw from per-thread
+// ChaCha8 generators maintained by the Go runtime, so they are lock-free and effectively sharded per
+// CPU, without any allocation.
+//
+// The tradeoff: FastSource is not suitable when retry timing must be unpredictable to an adversary,
+// but in tight retry loops around sub-millisecond operations, where crypto/rand and big.Int dominate
+// the CPU profile, it is an order of magnitude cheaper than CryptoSource. Jitter only needs to
+// de-synchronize clients, which a fast generator does just as well.
+//
+// Returns:
+//   - source: The math/rand/v2-backed Source.
+//
+// Example:
+//
+//	jitteredBackoff := jitter.Full(10*time.Second, jitter.WithSource(jitter.FastSource()))
+func FastSource() (source Source) {
+	source = fastSource{}
+
+	return
+}
+
+// seededSource is a Source backed by a seeded math/rand/v2 PCG generator.
+type seededSource struct {
+	mutex     sync.Mutex
+	generator *mathrand.Rand
+}
+
+// Int64N implements Source using the seeded generator, serializing concurrent draws.
+func (s *seededSource) Int64N(n int64) (random int64) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	random = s.generator.Int64N(n)
+
+	return
+}
+
+// SeededSource returns a Source backed by a math/rand/v2 PCG generator seeded with the given seed.
+// Sources with the same seed draw the same sequence of values, so that tests and simulations get
+// reproducible jitter, while production keeps CryptoSource. The sequence is only reproducible as long
+// as the draws happen in the same order: a SeededSource shared by concurrent retry sequences
+// interleaves them.
+//
+// Parameters:
+//   - seed: The seed of the generator.
+//
+// Returns:
+//   - source: The seeded Source.
+//
+// Example:
+//
+//	jitteredBackoff := backoff.ExponentialWithFullJitter(jitter.WithSource(jitter.SeededSource(42)))
+func SeededSource(seed uint64) (source Source) {
+	source = &seededSource{generator: mathrand.New(mathrand.NewPCG(seed, seed))}
+
+	return
+}
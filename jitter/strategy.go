@@ -0,0 +1,213 @@
+package jitter
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"time"
+)
+
+// ErrOutOfBounds is returned by Validate when a Strategy breaks its bounds contract.
+var ErrOutOfBounds = errors.New("jitter out of bounds")
+
+// Strategy is a jitter strategy. Besides jittering durations, it declares the bounds of the durations
+// it produces, so that the retrier, simulators, and tests can reason about the worst-case delays of
+// arbitrary strategies, including user-provided ones.
+type Strategy interface {
+	// Apply returns the jittered duration of a backoff duration.
+	Apply(backoff time.Duration) (jitter time.Duration)
+	// Bounds returns the range, inclusive, every duration returned by Apply for a backoff duration lies in.
+	Bounds(backoff time.Duration) (lower, upper time.Duration)
+}
+
+// equalStrategy is the Strategy of Equal.
+type equalStrategy struct {
+	opts []Option
+	cfg  *Configuration
+}
+
+// NewEqual returns the Strategy of Equal.
+//
+// Parameters:
+//   - opts: Optional configuration options, such as WithFloor.
+//
+// Returns:
+//   - strategy: The equal jitter Strategy.
+func NewEqual(opts ...Option) (strategy Strategy) {
+	strategy = &equalStrategy{opts: opts, cfg: configure(opts)}
+
+	return
+}
+
+// Apply implements Strategy by applying Equal.
+//
+// Parameters:
+//   - backoff: The backoff duration to jitter.
+//
+// Returns:
+//   - jitter: The jittered duration.
+func (s *equalStrategy) Apply(backoff time.Duration) (jitter time.Duration) {
+	jitter = Equal(backoff, s.opts...)
+
+	return
+}
+
+// Bounds implements Strategy: equal jitter stays within the upper half of the backoff duration.
+//
+// Parameters:
+//   - backoff: The backoff duration to bound the jittered durations of.
+//
+// Returns:
+//   - lower: The shortest jittered duration.
+//   - upper: The longest jittered duration.
+func (s *equalStrategy) Bounds(backoff time.Duration) (lower, upper time.Duration) {
+	midpoint := backoff / 2
+
+	lower, upper = s.cfg.apply(midpoint), s.cfg.apply(midpoint+max(midpoint, 0))
+
+	return
+}
+
+// fullStrategy is the Strategy of Full.
+type fullStrategy struct {
+	opts []Option
+	cfg  *Configuration
+}
+
+// NewFull returns the Strategy of Full.
+//
+// Parameters:
+//   - opts: Optional configuration options, such as WithFloor.
+//
+// Returns:
+//   - strategy: The full jitter Strategy.
+func NewFull(opts ...Option) (strategy Strategy) {
+	strategy = &fullStrategy{opts: opts, cfg: configure(opts)}
+
+	return
+}
+
+// Apply implements Strategy by applying Full.
+//
+// Parameters:
+//   - backoff: The backoff duration to jitter.
+//
+// Returns:
+//   - jitter: The jittered duration.
+func (s *fullStrategy) Apply(backoff time.Duration) (jitter time.Duration) {
+	jitter = Full(backoff, s.opts...)
+
+	return
+}
+
+// Bounds implements Strategy: full jitter spans from zero to the backoff duration.
+//
+// Parameters:
+//   - backoff: The backoff duration to bound the jittered durations of.
+//
+// Returns:
+//   - lower: The shortest jittered duration.
+//   - upper: The longest jittered duration.
+func (s *fullStrategy) Bounds(backoff time.Duration) (lower, upper time.Duration) {
+	lower, upper = s.cfg.apply(0), s.cfg.apply(max(backoff, 0))
+
+	return
+}
+
+// symmetricStrategy is the Strategy of Symmetric.
+type symmetricStrategy struct {
+	fraction float64
+	opts     []Option
+	cfg      *Configuration
+}
+
+// NewSymmetric returns the Strategy of Symmetric.
+//
+// Parameters:
+//   - fraction: The maximum relative deviation from the nominal backoff, clamped to [0, 1].
+//   - opts:     Optional configuration options, such as WithFloor and WithCeiling.
+//
+// Returns:
+//   - strategy: The symmetric jitter Strategy.
+func NewSymmetric(fraction float64, opts ...Option) (strategy Strategy) {
+	strategy = &symmetricStrategy{fraction: fraction, opts: opts, cfg: configure(opts)}
+
+	return
+}
+
+// Apply implements Strategy by applying Symmetric.
+//
+// Parameters:
+//   - backoff: The backoff duration to jitter.
+//
+// Returns:
+//   - jitter: The jittered duration.
+func (s *symmetricStrategy) Apply(backoff time.Duration) (jitter time.Duration) {
+	jitter = Symmetric(backoff, s.fraction, s.opts...)
+
+	return
+}
+
+// Bounds implements Strategy: symmetric jitter spreads around the backoff duration by the fraction.
+//
+// Parameters:
+//   - backoff: The backoff duration to bound the jittered durations of.
+//
+// Returns:
+//   - lower: The shortest jittered duration.
+//   - upper: The longest jittered duration.
+func (s *symmetricStrategy) Bounds(backoff time.Duration) (lower, upper time.Duration) {
+	if backoff <= 0 || !(s.fraction > 0) {
+		lower = s.cfg.apply(max(backoff, 0))
+		upper = lower
+
+		return
+	}
+
+	spread := time.Duration(float64(backoff) * min(s.fraction, 1))
+
+	upper = time.Duration(math.MaxInt64)
+
+	if spread <= math.MaxInt64-backoff {
+		upper = backoff + spread
+	}
+
+	lower, upper = s.cfg.apply(backoff-spread), s.cfg.apply(upper)
+
+	return
+}
+
+// Validate checks that a Strategy keeps its bounds contract for a backoff duration: its bounds must
+// be ordered and non-negative, and every sampled jittered duration must lie within them. It lets tests
+// vet user-provided strategies before trusting their bounds.
+//
+// Parameters:
+//   - strategy: The Strategy to validate.
+//   - backoff:  The backoff duration to validate the Strategy for.
+//   - samples:  The number of jittered durations to sample.
+//
+// Returns:
+//   - err: nil if the Strategy keeps its contract, or an error wrapping ErrOutOfBounds otherwise.
+//
+// Example:
+//
+//	err := jitter.Validate(myStrategy, time.Second, 1000)
+func Validate(strategy Strategy, backoff time.Duration, samples int) (err error) {
+	lower, upper := strategy.Bounds(backoff)
+
+	if lower < 0 || lower > upper {
+		err = fmt.Errorf("%w: invalid bounds [%s, %s] for %s", ErrOutOfBounds, lower, upper, backoff)
+
+		return
+	}
+
+	for range samples {
+		if jitter := strategy.Apply(backoff); jitter < lower || jitter > upper {
+			err = fmt.Errorf("%w: %s outside [%s, %s] for %s", ErrOutOfBounds, jitter, lower, upper, backoff)
+
+			return
+		}
+	}
+
+	return
+}
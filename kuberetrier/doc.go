@@ -0,0 +1,15 @@
+// Package kuberetrier adapts the retrier's backoff and jitter strategies to Kubernetes controllers.
+//
+// RateLimiter implements the rate limiter interface of the client-go workqueue package, i.e.,
+// workqueue.TypedRateLimiter, and workqueue.RateLimiter when instantiated with any, structurally,
+// without depending on client-go. Controller authors can thus requeue failing items with the same
+// backoff implementation they use for their API calls:
+//
+//	limiter := kuberetrier.NewRateLimiter[any](policy.Policy{
+//	    MinDelay: 5 * time.Millisecond,
+//	    MaxDelay: 1000 * time.Second,
+//	    Backoff:  backoff.ExponentialWithFullJitter(),
+//	})
+//
+//	queue := workqueue.NewRateLimitingQueue(limiter)
+package kuberetrier
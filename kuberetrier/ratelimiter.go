@@ -0,0 +1,83 @@
+package kuberetrier
+
+import (
+	"sync"
+	"time"
+
+	"go.source.hueristiq.com/retrier/policy"
+)
+
+// RateLimiter computes the delay before an item of a workqueue is requeued from the number of times
+// it failed, using the backoff strategy and delay bounds of a policy. It is safe for concurrent use.
+type RateLimiter[T comparable] struct {
+	policy policy.Policy
+
+	mutex    sync.Mutex
+	failures map[T]int
+}
+
+// NewRateLimiter returns a RateLimiter using the backoff strategy and delay bounds of a policy. The
+// maximum number of attempts of the policy is not enforced: the workqueue decides when to drop items.
+//
+// Parameters:
+//   - p: The policy whose backoff strategy and delay bounds are used.
+//
+// Returns:
+//   - limiter: The RateLimiter.
+func NewRateLimiter[T comparable](p policy.Policy) (limiter *RateLimiter[T]) {
+	limiter = &RateLimiter[T]{
+		policy:   p,
+		failures: make(map[T]int),
+	}
+
+	return
+}
+
+// When records a failure of the item and returns the delay before it is requeued.
+//
+// Parameters:
+//   - item: The item that failed.
+//
+// Returns:
+//   - delay: The delay computed by the backoff strategy for the number of failures of the item.
+func (l *RateLimiter[T]) When(item T) (delay time.Duration) {
+	l.mutex.Lock()
+
+	failures := l.failures[item]
+
+	l.failures[item] = failures + 1
+
+	l.mutex.Unlock()
+
+	delay = l.policy.Delay(failures)
+
+	return
+}
+
+// Forget stops tracking the item, e.g., once it was processed successfully, so that its next failure
+// starts from the initial delay again.
+//
+// Parameters:
+//   - item: The item to forget.
+func (l *RateLimiter[T]) Forget(item T) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	delete(l.failures, item)
+}
+
+// NumRequeues returns the number of times the item failed since it was last forgotten.
+//
+// Parameters:
+//   - item: The item.
+//
+// Returns:
+//   - requeues: The number of failures of the item.
+func (l *RateLimiter[T]) NumRequeues(item T) (requeues int) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	requeues = l.failures[item]
+
+	return
+}
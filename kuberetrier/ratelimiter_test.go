@@ -0,0 +1,42 @@
+package kuberetrier_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.source.hueristiq.com/retrier/kuberetrier"
+	"go.source.hueristiq.com/retrier/policy"
+)
+
+// typedRateLimiter mirrors the workqueue.TypedRateLimiter interface of client-go.
+type typedRateLimiter[T comparable] interface {
+	When(item T) time.Duration
+	Forget(item T)
+	NumRequeues(item T) int
+}
+
+var (
+	_ typedRateLimiter[string] = (*kuberetrier.RateLimiter[string])(nil)
+	_ typedRateLimiter[any]    = (*kuberetrier.RateLimiter[any])(nil)
+)
+
+func TestRateLimiter(t *testing.T) {
+	t.Parallel()
+
+	limiter := kuberetrier.NewRateLimiter[string](policy.Policy{MinDelay: 10 * time.Millisecond, MaxDelay: 50 * time.Millisecond})
+
+	expected := []time.Duration{10 * time.Millisecond, 20 * time.Millisecond, 40 * time.Millisecond, 50 * time.Millisecond}
+
+	for i, delay := range expected {
+		assert.Equal(t, delay, limiter.When("default/pod"), "Unexpected delay after %d failures", i)
+	}
+
+	assert.Equal(t, 4, limiter.NumRequeues("default/pod"), "Expected the failures of the item to be counted")
+	assert.Equal(t, 10*time.Millisecond, limiter.When("default/other"), "Expected items to be tracked independently")
+
+	limiter.Forget("default/pod")
+
+	assert.Equal(t, 0, limiter.NumRequeues("default/pod"), "Expected a forgotten item to have no failures")
+	assert.Equal(t, 10*time.Millisecond, limiter.When("default/pod"), "Expected a forgotten item to start from the initial delay")
+}
@@ -0,0 +1,93 @@
+package retrier
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+)
+
+// ErrCompensationFailed is wrapped, with the errors of the compensations, by the error of a retry
+// sequence whose Ledger failed to compensate.
+var ErrCompensationFailed = errors.New("compensation failed")
+
+// Ledger records the compensation actions undoing the side effects of the attempts of a retry
+// sequence, saga-style: when the sequence gives up, the compensations are run in reverse order, so
+// that write paths can undo the partial effects created by failed attempts. A Ledger serves a single
+// retry sequence, through WithLedger, and is safe for concurrent use.
+type Ledger struct {
+	mutex         sync.Mutex
+	compensations []func() error
+}
+
+// Add records the compensation of a side effect the current attempt created.
+//
+// Parameters:
+//   - compensation: The function undoing the side effect. Nil functions are ignored.
+//
+// Example:
+//
+//	id, err := orders.Reserve(ctx, item)
+//	if err == nil {
+//	    ledger.Add(func() error { return orders.Release(ctx, id) })
+//	}
+func (l *Ledger) Add(compensation func() error) {
+	if compensation == nil {
+		return
+	}
+
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	l.compensations = append(l.compensations, compensation)
+}
+
+// Len returns the number of compensations recorded and not yet run.
+//
+// Returns:
+//   - n: The number of pending compensations.
+func (l *Ledger) Len() (n int) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	n = len(l.compensations)
+
+	return
+}
+
+// compensate runs the recorded compensations in reverse order, all of them even if some fail, and
+// clears them.
+//
+// Returns:
+//   - err: An error wrapping ErrCompensationFailed and the errors of the failed compensations, or nil.
+func (l *Ledger) compensate() (err error) {
+	l.mutex.Lock()
+
+	compensations := l.compensations
+
+	l.compensations = nil
+
+	l.mutex.Unlock()
+
+	var failures []error
+
+	for _, compensation := range slices.Backward(compensations) {
+		if failure := compensation(); failure != nil {
+			failures = append(failures, failure)
+		}
+	}
+
+	if len(failures) > 0 {
+		err = fmt.Errorf("%w: %w", ErrCompensationFailed, errors.Join(failures...))
+	}
+
+	return
+}
+
+// discard clears the recorded compensations without running them.
+func (l *Ledger) discard() {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	l.compensations = nil
+}
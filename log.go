@@ -0,0 +1,83 @@
+package retrier
+
+import (
+	"context"
+	"log/slog"
+	"time"
+)
+
+// LogLevels are the levels at which the logger set through WithLogger logs a retry sequence.
+//
+// Fields:
+//   - Attempt: The level of the failed attempts followed by a backoff. Defaults to slog.LevelWarn.
+//   - Success: The level of the outcome of a retry sequence that succeeds. Defaults to slog.LevelDebug.
+//   - Failure: The level of the outcome of a retry sequence that gives up. Defaults to slog.LevelError.
+type LogLevels struct {
+	Attempt slog.Level
+	Success slog.Level
+	Failure slog.Level
+}
+
+// defaultLogLevels are the LogLevels of a Configuration that does not set them.
+var defaultLogLevels = LogLevels{
+	Attempt: slog.LevelWarn,
+	Success: slog.LevelDebug,
+	Failure: slog.LevelError,
+}
+
+// logAttempt logs a failed attempt followed by a backoff.
+//
+// Parameters:
+//   - ctx:        The context of the retry sequence, passed to the handler.
+//   - logger:     The logger.
+//   - level:      The level of the record.
+//   - sequenceID: The identifier of the retry sequence.
+//   - info:       The failed attempt.
+func logAttempt(ctx context.Context, logger *slog.Logger, level slog.Level, sequenceID string, info AttemptInfo) {
+	if !logger.Enabled(ctx, level) {
+		return
+	}
+
+	logger.LogAttrs(ctx, level, "retrying after failed attempt",
+		slog.String("sequence_id", sequenceID),
+		slog.Int("attempt", info.Attempt),
+		slog.Any("error", info.Err),
+		slog.Duration("backoff", info.Backoff),
+		slog.Duration("elapsed", info.Elapsed),
+		slog.Int("remaining", info.Remaining),
+	)
+}
+
+// logOutcome logs the outcome of a retry sequence.
+//
+// Parameters:
+//   - ctx:        The context of the retry sequence, passed to the handler.
+//   - logger:     The logger.
+//   - levels:     The levels of the records.
+//   - sequenceID: The identifier of the retry sequence.
+//   - attempts:   The number of attempts executed.
+//   - elapsed:    The time the retry sequence took.
+//   - err:        The error the retry sequence returns, or nil if it succeeded.
+func logOutcome(ctx context.Context, logger *slog.Logger, levels LogLevels, sequenceID string, attempts int, elapsed time.Duration, err error) {
+	level, message := levels.Success, "retry sequence succeeded"
+
+	if err != nil {
+		level, message = levels.Failure, "retry sequence failed"
+	}
+
+	if !logger.Enabled(ctx, level) {
+		return
+	}
+
+	attrs := []slog.Attr{
+		slog.String("sequence_id", sequenceID),
+		slog.Int("attempts", attempts),
+		slog.Duration("elapsed", elapsed),
+	}
+
+	if err != nil {
+		attrs = append(attrs, slog.Any("error", err))
+	}
+
+	logger.LogAttrs(ctx, level, message, attrs...)
+}
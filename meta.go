@@ -0,0 +1,79 @@
+package retrier
+
+import "time"
+
+// ResultSource identifies what produced the result of a retry sequence.
+type ResultSource int
+
+const (
+	// ResultSourceOperation means the result was returned by the operation itself.
+	ResultSourceOperation ResultSource = iota
+	// ResultSourceMiddleware means a middleware answered without calling the operation, e.g., serving
+	// the result from a cache or a fallback.
+	ResultSourceMiddleware
+)
+
+// String returns the name of the result source.
+//
+// Returns:
+//   - name: The name of the result source.
+func (s ResultSource) String() (name string) {
+	switch s {
+	case ResultSourceOperation:
+		name = "operation"
+	case ResultSourceMiddleware:
+		name = "middleware"
+	default:
+		name = "unknown"
+	}
+
+	return
+}
+
+// ResultMeta describes the provenance of the result returned by a successful retry sequence, so that
+// callers and downstream caches can reason about the freshness of the data. It is populated through
+// WithResultMeta, and reset to its zero value when the retry sequence fails.
+//
+// Fields:
+//   - Attempt: The zero-based number of the attempt that produced the result.
+//   - ProducedAt: The time at which the attempt that produced the result completed.
+//   - Source: What produced the result.
+type ResultMeta struct {
+	Attempt    int
+	ProducedAt time.Time
+	Source     ResultSource
+}
+
+// AttemptMeta describes the attempt a context-aware operation is called for, so that it can adjust
+// its behavior on later attempts, e.g., switch endpoints, without plumbing state manually. It is
+// returned by AttemptFromContext.
+//
+// Fields:
+//   - Number: The zero-based number of the attempt within the retry sequence.
+//   - PreviousErr: The error of the previous attempt, or nil for the first attempt.
+//   - Elapsed: The time elapsed since the retry sequence started.
+type AttemptMeta struct {
+	Number      int
+	PreviousErr error
+	Elapsed     time.Duration
+}
+
+// AttemptInfo describes a failed attempt to the notifiers registered through WithNotifierV2.
+//
+// Fields:
+//   - Attempt: The zero-based number of the failed attempt within the retry sequence.
+//   - Err: The error of the failed attempt, or a *RepeatedError grouping the repeats of a failure with
+//     WithFingerprint.
+//   - Backoff: The delay before the next attempt.
+//   - Elapsed: The time elapsed since the retry sequence started.
+//   - Remaining: The number of attempts left to the retry sequence, or -1 if they are unlimited.
+//   - SequenceID: The unique identifier of the retry sequence, shared with its Attempts and Stats, so
+//     that the notifications of one retry sequence can be correlated.
+type AttemptInfo struct {
+	Attempt    int
+	Err        error
+	Backoff    time.Duration
+	Elapsed    time.Duration
+	Remaining  int
+	SequenceID string
+}
@@ -0,0 +1,22 @@
+// Package otelretrier instruments retry sequences with OpenTelemetry.
+//
+// An Instrumentation records a span per attempt, carrying the number of the attempt, its error, and
+// the backoff delay that follows it, as a child of the span of the context passed to the retry
+// sequence, and records metrics per retry sequence: the number of retries, the number of sequences
+// that gave up without a permanent failure, and a histogram of the total delay between attempts.
+//
+//	instrumentation, err := otelretrier.New(
+//	    otelretrier.WithTracerProvider(tracerProvider),
+//	    otelretrier.WithMeterProvider(meterProvider),
+//	)
+//
+//	err = instrumentation.RetryCtx(ctx, func(ctx context.Context) error {
+//	    return client.Ping(ctx)
+//	}, retrier.WithMaxRetries(5))
+//
+// The backoff delays are reported through a notifier, so that they are subject to WithSampling and
+// WithHookTiming like the other notifiers of the retry sequence.
+//
+// The package lives in its own module, so that the retrier module itself does not depend on the
+// OpenTelemetry API.
+package otelretrier
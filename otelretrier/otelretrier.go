@@ -0,0 +1,325 @@
+package otelretrier
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/trace"
+	"go.source.hueristiq.com/retrier"
+)
+
+// instrumentationName is the name of the tracer and meter of the package.
+const instrumentationName = "go.source.hueristiq.com/retrier/otelretrier"
+
+// The attributes of the spans of the attempts.
+const (
+	// AttributeAttempt is the zero-based number of the attempt within the retry sequence.
+	AttributeAttempt = attribute.Key("retrier.attempt")
+	// AttributeBackoff is the delay, in milliseconds, before the attempt following a failed one.
+	AttributeBackoff = attribute.Key("retrier.backoff_ms")
+)
+
+// config is the configuration of an Instrumentation, set through the Options of New.
+//
+// Fields:
+//   - tracerProvider: The TracerProvider of the tracer recording the spans of the attempts.
+//   - meterProvider:  The MeterProvider of the meter recording the metrics of the retry sequences.
+type config struct {
+	tracerProvider trace.TracerProvider
+	meterProvider  metric.MeterProvider
+}
+
+// Option is a functional option configuring an Instrumentation.
+type Option func(c *config)
+
+// WithTracerProvider sets the TracerProvider of the tracer recording the spans of the attempts. A nil
+// provider stands for the global one, returned by otel.GetTracerProvider.
+//
+// Parameters:
+//   - provider: The TracerProvider.
+//
+// Returns:
+//   - Option: A functional option that modifies the config to set the tracerProvider field.
+func WithTracerProvider(provider trace.TracerProvider) Option {
+	return func(c *config) {
+		if provider != nil {
+			c.tracerProvider = provider
+		}
+	}
+}
+
+// WithMeterProvider sets the MeterProvider of the meter recording the metrics of the retry sequences.
+// A nil provider stands for the global one, returned by otel.GetMeterProvider.
+//
+// Parameters:
+//   - provider: The MeterProvider.
+//
+// Returns:
+//   - Option: A functional option that modifies the config to set the meterProvider field.
+func WithMeterProvider(provider metric.MeterProvider) Option {
+	return func(c *config) {
+		if provider != nil {
+			c.meterProvider = provider
+		}
+	}
+}
+
+// Instrumentation runs retry sequences recording a span per attempt and metrics per sequence. It is
+// safe for concurrent use by multiple goroutines.
+//
+// Fields:
+//   - tracer:      The tracer recording the spans of the attempts.
+//   - retries:     The counter of the retries, i.e., the attempts following a failed one.
+//   - exhaustions: The counter of the retry sequences giving up without a permanent failure.
+//   - delay:       The histogram of the total delay between the attempts of a retry sequence.
+type Instrumentation struct {
+	tracer      trace.Tracer
+	retries     metric.Int64Counter
+	exhaustions metric.Int64Counter
+	delay       metric.Float64Histogram
+}
+
+// New returns an Instrumentation recording through the providers set through opts, or the global
+// ones.
+//
+// Parameters:
+//   - opts: The options of the Instrumentation.
+//
+// Returns:
+//   - instrumentation: The Instrumentation.
+//   - err:             The error of creating the instruments of the metrics.
+func New(opts ...Option) (instrumentation *Instrumentation, err error) {
+	c := &config{
+		tracerProvider: otel.GetTracerProvider(),
+		meterProvider:  otel.GetMeterProvider(),
+	}
+
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	meter := c.meterProvider.Meter(instrumentationName)
+
+	i := &Instrumentation{tracer: c.tracerProvider.Tracer(instrumentationName)}
+
+	if i.retries, err = meter.Int64Counter("retrier.retries",
+		metric.WithDescription("The number of attempts following a failed one."),
+		metric.WithUnit("{retry}")); err != nil {
+		return
+	}
+
+	if i.exhaustions, err = meter.Int64Counter("retrier.exhaustions",
+		metric.WithDescription("The number of retry sequences giving up without a permanent failure."),
+		metric.WithUnit("{sequence}")); err != nil {
+		return
+	}
+
+	if i.delay, err = meter.Float64Histogram("retrier.delay",
+		metric.WithDescription("The total delay between the attempts of a retry sequence."),
+		metric.WithUnit("s")); err != nil {
+		return
+	}
+
+	instrumentation = i
+
+	return
+}
+
+// RetryCtx is retrier.RetryCtx, instrumented.
+//
+// Parameters:
+//   - ctx:       A context to control the lifetime of the retry sequence, parent of the spans of the
+//     attempts.
+//   - operation: The operation to be retried.
+//   - opts:      The retry options.
+//
+// Returns:
+//   - err: The error of the retry sequence, as returned by retrier.RetryCtx.
+func (i *Instrumentation) RetryCtx(ctx context.Context, operation retrier.OperationWithContext, opts ...retrier.Option) (err error) {
+	_, err = RetryCtxWithData(ctx, i, func(ctx context.Context) (data struct{}, err error) {
+		err = operation(ctx)
+
+		return
+	}, opts...)
+
+	return
+}
+
+// RetryCtxWithData is retrier.RetryCtxWithData, instrumented.
+//
+// Parameters:
+//   - ctx:             A context to control the lifetime of the retry sequence, parent of the spans
+//     of the attempts.
+//   - instrumentation: The Instrumentation recording the retry sequence.
+//   - operation:       The operation to be retried, which returns a value of type T and an error.
+//   - opts:            The retry options.
+//
+// Returns:
+//   - result: The result of the operation if it succeeds within the allowed retry attempts.
+//   - err:    The error of the retry sequence, as returned by retrier.RetryCtxWithData.
+//
+// Example:
+//
+//	user, err := otelretrier.RetryCtxWithData(ctx, instrumentation, func(ctx context.Context) (User, error) {
+//	    return client.GetUser(ctx, id)
+//	}, retrier.WithMaxRetries(5))
+func RetryCtxWithData[T any](ctx context.Context, instrumentation *Instrumentation, operation retrier.OperationWithContextAndData[T], opts ...retrier.Option) (result T, err error) {
+	s := &sequence{tracer: instrumentation.tracer}
+
+	result, err = retrier.RetryCtxWithData(ctx, func(ctx context.Context) (result T, err error) {
+		meta, _ := retrier.AttemptFromContext(ctx)
+
+		ctx, span := s.start(ctx, meta.Number)
+
+		result, err = operation(ctx)
+
+		s.finish(span, meta.Number, err)
+
+		return
+	}, append(slices.Clip(opts), retrier.WithNotifierV2(s.notify))...)
+
+	s.end()
+
+	instrumentation.record(ctx, s, err)
+
+	return
+}
+
+// record records the metrics of a retry sequence once it ended.
+//
+// Parameters:
+//   - ctx: The context of the retry sequence.
+//   - s:   The state of the retry sequence.
+//   - err: The error of the retry sequence.
+func (i *Instrumentation) record(ctx context.Context, s *sequence, err error) {
+	s.mutex.Lock()
+
+	defer s.mutex.Unlock()
+
+	if s.attempts > 1 {
+		i.retries.Add(ctx, int64(s.attempts-1))
+	}
+
+	var exhausted *retrier.RetryError
+
+	if errors.As(err, &exhausted) {
+		i.exhaustions.Add(ctx, 1)
+	}
+
+	i.delay.Record(ctx, s.delay.Seconds())
+}
+
+// sequence is the state of an instrumented retry sequence.
+//
+// Fields:
+//   - tracer:   The tracer recording the spans of the attempts.
+//   - attempts: The number of attempts of the retry sequence.
+//   - delay:    The total delay between the attempts, as notified.
+//   - failed:   The span of the last failed attempt, left open until its backoff delay is notified.
+//   - number:   The number of the last failed attempt.
+type sequence struct {
+	mutex sync.Mutex
+
+	tracer   trace.Tracer
+	attempts int
+	delay    time.Duration
+	failed   trace.Span
+	number   int
+}
+
+// start starts the span of an attempt, ending the span of the previous failed attempt if its backoff
+// delay was not notified.
+//
+// Parameters:
+//   - ctx:    The context of the attempt.
+//   - number: The zero-based number of the attempt.
+//
+// Returns:
+//   - spanCtx: The context of the attempt, carrying its span.
+//   - span:    The span of the attempt.
+func (s *sequence) start(ctx context.Context, number int) (spanCtx context.Context, span trace.Span) {
+	s.mutex.Lock()
+
+	defer s.mutex.Unlock()
+
+	s.endFailed()
+
+	s.attempts++
+
+	spanCtx, span = s.tracer.Start(ctx, "retrier.attempt", trace.WithAttributes(AttributeAttempt.Int(number)))
+
+	return
+}
+
+// finish ends the span of a successful attempt, or records the error of a failed one, whose span is
+// ended once its backoff delay is notified.
+//
+// Parameters:
+//   - span:   The span of the attempt.
+//   - number: The zero-based number of the attempt.
+//   - err:    The error of the attempt.
+func (s *sequence) finish(span trace.Span, number int, err error) {
+	if err == nil {
+		span.SetStatus(codes.Ok, "")
+		span.End()
+
+		return
+	}
+
+	span.RecordError(err)
+	span.SetStatus(codes.Error, err.Error())
+
+	s.mutex.Lock()
+
+	defer s.mutex.Unlock()
+
+	s.endFailed()
+
+	s.failed, s.number = span, number
+}
+
+// notify is the notifier of the retry sequence, attaching the backoff delay following a failed
+// attempt to its span and ending it.
+//
+// Parameters:
+//   - info: The failed attempt.
+func (s *sequence) notify(info retrier.AttemptInfo) {
+	s.mutex.Lock()
+
+	defer s.mutex.Unlock()
+
+	s.delay += info.Backoff
+
+	if s.failed != nil && s.number == info.Attempt {
+		s.failed.SetAttributes(AttributeBackoff.Int64(info.Backoff.Milliseconds()))
+	}
+
+	s.endFailed()
+}
+
+// end ends the span of the last failed attempt, if still open, once the retry sequence ended.
+func (s *sequence) end() {
+	s.mutex.Lock()
+
+	defer s.mutex.Unlock()
+
+	s.endFailed()
+}
+
+// endFailed ends the span of the last failed attempt, if still open. The mutex must be held.
+func (s *sequence) endFailed() {
+	if s.failed == nil {
+		return
+	}
+
+	s.failed.End()
+
+	s.failed = nil
+}
@@ -0,0 +1,134 @@
+package otelretrier_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.source.hueristiq.com/retrier"
+	"go.source.hueristiq.com/retrier/otelretrier"
+)
+
+var errUnavailable = errors.New("unavailable")
+
+func setup(t *testing.T) (instrumentation *otelretrier.Instrumentation, spans *tracetest.SpanRecorder, reader *sdkmetric.ManualReader) {
+	t.Helper()
+
+	spans = tracetest.NewSpanRecorder()
+	reader = sdkmetric.NewManualReader()
+
+	instrumentation, err := otelretrier.New(
+		otelretrier.WithTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(spans))),
+		otelretrier.WithMeterProvider(sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))))
+
+	require.NoError(t, err, "Expected the instruments to be created")
+
+	return
+}
+
+func collect(t *testing.T, reader *sdkmetric.ManualReader) (metrics map[string]metricdata.Aggregation) {
+	t.Helper()
+
+	var rm metricdata.ResourceMetrics
+
+	require.NoError(t, reader.Collect(context.Background(), &rm), "Expected the metrics to be collected")
+
+	metrics = make(map[string]metricdata.Aggregation)
+
+	for _, scope := range rm.ScopeMetrics {
+		for _, m := range scope.Metrics {
+			metrics[m.Name] = m.Data
+		}
+	}
+
+	return
+}
+
+func TestInstrumentation_RetryCtx(t *testing.T) {
+	t.Parallel()
+
+	instrumentation, spans, reader := setup(t)
+
+	calls := 0
+
+	err := instrumentation.RetryCtx(context.Background(), func(context.Context) error {
+		calls++
+
+		if calls < 3 {
+			return errUnavailable
+		}
+
+		return nil
+	}, retrier.WithMinDelay(time.Millisecond), retrier.WithMaxDelay(time.Millisecond))
+
+	require.NoError(t, err, "Expected the operation to succeed after retrying")
+
+	ended := spans.Ended()
+
+	require.Len(t, ended, 3, "Expected a span per attempt")
+
+	for number, span := range ended {
+		assert.Equal(t, "retrier.attempt", span.Name())
+		assert.Contains(t, span.Attributes(), otelretrier.AttributeAttempt.Int(number), "Expected the number of the attempt")
+	}
+
+	assert.Equal(t, codes.Error, ended[0].Status().Code, "Expected a failed attempt to be an error")
+	assert.Contains(t, ended[0].Attributes(), otelretrier.AttributeBackoff.Int64(1), "Expected the backoff delay following a failed attempt")
+	assert.Len(t, ended[0].Events(), 1, "Expected the error to be recorded")
+	assert.Equal(t, codes.Ok, ended[2].Status().Code, "Expected the last attempt to succeed")
+
+	metrics := collect(t, reader)
+
+	retries, ok := metrics["retrier.retries"].(metricdata.Sum[int64])
+
+	require.True(t, ok, "Expected the retries to be counted")
+	assert.Equal(t, int64(2), retries.DataPoints[0].Value, "Expected two retries")
+
+	delay, ok := metrics["retrier.delay"].(metricdata.Histogram[float64])
+
+	require.True(t, ok, "Expected the delays to be recorded")
+	assert.Equal(t, uint64(1), delay.DataPoints[0].Count, "Expected a total delay per retry sequence")
+	assert.InDelta(t, 0.002, delay.DataPoints[0].Sum, 1e-9, "Expected the total delay between the attempts")
+
+	_, ok = metrics["retrier.exhaustions"]
+
+	assert.False(t, ok, "Expected no exhaustion")
+}
+
+func TestRetryCtxWithData_Exhausted(t *testing.T) {
+	t.Parallel()
+
+	instrumentation, spans, reader := setup(t)
+
+	_, err := otelretrier.RetryCtxWithData(context.Background(), instrumentation, func(context.Context) (int, error) {
+		return 0, errUnavailable
+	}, retrier.WithMaxRetries(2), retrier.WithMinDelay(time.Millisecond), retrier.WithMaxDelay(time.Millisecond))
+
+	require.ErrorIs(t, err, errUnavailable, "Expected the retry sequence to give up")
+	assert.Len(t, spans.Ended(), 2, "Expected every span to be ended")
+
+	exhaustions, ok := collect(t, reader)["retrier.exhaustions"].(metricdata.Sum[int64])
+
+	require.True(t, ok, "Expected the exhaustions to be counted")
+	assert.Equal(t, int64(1), exhaustions.DataPoints[0].Value, "Expected one exhaustion")
+
+	_, err = otelretrier.RetryCtxWithData(context.Background(), instrumentation, func(context.Context) (int, error) {
+		return 0, retrier.Permanent(errUnavailable)
+	})
+
+	require.ErrorIs(t, err, errUnavailable, "Expected the retry sequence to give up")
+
+	ended := spans.Ended()
+
+	require.Len(t, ended, 3, "Expected the span of a permanent failure to be ended")
+	assert.Equal(t, []attribute.KeyValue{otelretrier.AttributeAttempt.Int(0)}, ended[2].Attributes(), "Expected no backoff after a permanent failure")
+}
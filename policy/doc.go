@@ -0,0 +1,16 @@
+// Package policy provides a declarative description of retry policies and tooling around them.
+//
+// A Policy captures the settings that govern a retry sequence: the maximum number of attempts,
+// the delay bounds, and the backoff strategy. ExportSchedule turns a Policy into the effective
+// schedule of delays between attempts, including the band the jitter spreads each delay over,
+// which can be exported as CSV or JSON, or rendered as a small ASCII chart, so that teams can
+// attach the schedule a service actually follows to design docs and runbooks. Validate checks the
+// cross-field consistency of a Policy, e.g., one loaded from configuration, and lists every offending
+// field in a ValidationError. FromJSON and FromYAML load a Policy from configuration, naming its
+// backoff strategy, jitter, and multiplier, so that services can change their retry behavior without
+// code changes, and retrier.WithPolicy applies it, in place of a ToOptions method, which would make
+// this package import the retrier that imports it. Analyze turns the timelines of retry sequences
+// recorded in production, through retrier.WithTimeline, into an Efficacy report of the sleep wasted
+// after the dependency recovered and of premature retries, with delays suggested from the observed
+// recovery times.
+package policy
@@ -0,0 +1,126 @@
+package policy
+
+import (
+	"slices"
+	"time"
+)
+
+// AttemptRecord records one attempt of a retry sequence.
+//
+// Fields:
+//   - Start:    When the attempt started.
+//   - Duration: How long the attempt took.
+//   - Failed:   Whether the attempt failed.
+type AttemptRecord struct {
+	Start    time.Time     `json:"start"`
+	Duration time.Duration `json:"duration"`
+	Failed   bool          `json:"failed"`
+}
+
+// end returns when the attempt ended.
+//
+// Returns:
+//   - end: When the attempt ended.
+func (r AttemptRecord) end() (end time.Time) {
+	end = r.Start.Add(r.Duration)
+
+	return
+}
+
+// Timeline is the record of the attempts of one retry sequence, in order, as recorded through
+// retrier.WithTimeline.
+type Timeline []AttemptRecord
+
+// Efficacy reports how well the backoff of a policy fits the recoveries of its dependency, as observed
+// over recorded timelines.
+//
+// Fields:
+//   - Sequences:         The number of timelines analyzed.
+//   - Recovered:         The number of timelines that failed, then succeeded.
+//   - WastedSleep:       The time the recovered sequences spent backing off after another sequence had
+//     already observed the dependency recovered.
+//   - PrematureRetries:  The number of retries that failed, i.e., that came before the dependency recovered.
+//   - SuggestedMinDelay: A minimum delay fitted to the observed recovery times, or 0 if none was observed.
+//   - SuggestedMaxDelay: A maximum delay fitted to the observed recovery times, or 0 if none was observed.
+type Efficacy struct {
+	Sequences         int           `json:"sequences"`
+	Recovered         int           `json:"recovered"`
+	WastedSleep       time.Duration `json:"wasted_sleep"`
+	PrematureRetries  int           `json:"premature_retries"`
+	SuggestedMinDelay time.Duration `json:"suggested_min_delay"`
+	SuggestedMaxDelay time.Duration `json:"suggested_max_delay"`
+}
+
+// Analyze turns recorded timelines, e.g., collected from production over an incident, into an
+// Efficacy report. The recovery time of a sequence is the time from the end of its first failed
+// attempt to the start of its successful one. The suggested delays are heuristics fitted to these
+// recovery times: the maximum delay is their 90th percentile, so that sequences do not sleep far past
+// a typical recovery, and the minimum delay is half their 10th percentile, so that first retries
+// rarely come before even the quickest recoveries.
+//
+// Parameters:
+//   - timelines: The recorded timelines, of retry sequences of the same policy and dependency.
+//
+// Returns:
+//   - efficacy: The Efficacy report.
+//
+// Example:
+//
+//	efficacy := policy.Analyze(timelines)
+//	fmt.Printf("wasted %s, try MinDelay=%s MaxDelay=%s\n", efficacy.WastedSleep, efficacy.SuggestedMinDelay, efficacy.SuggestedMaxDelay)
+func Analyze(timelines []Timeline) (efficacy Efficacy) {
+	efficacy.Sequences = len(timelines)
+
+	// Every successful attempt tells the dependency was up when it started.
+	var successes []time.Time
+
+	for _, timeline := range timelines {
+		for _, attempt := range timeline {
+			if !attempt.Failed {
+				successes = append(successes, attempt.Start)
+			}
+		}
+	}
+
+	slices.SortFunc(successes, time.Time.Compare)
+
+	var recoveries []time.Duration
+
+	for _, timeline := range timelines {
+		for i, attempt := range timeline {
+			if i > 0 && attempt.Failed {
+				efficacy.PrematureRetries++
+			}
+		}
+
+		last := len(timeline) - 1
+		if last < 1 || timeline[last].Failed {
+			continue
+		}
+
+		efficacy.Recovered++
+
+		success := timeline[last]
+
+		recoveries = append(recoveries, success.Start.Sub(timeline[0].end()))
+
+		// The sequence slept past the first success observed after its last failure.
+		lastFailure := timeline[last-1].end()
+
+		index, _ := slices.BinarySearchFunc(successes, lastFailure, time.Time.Compare)
+		if index < len(successes) && successes[index].Before(success.Start) {
+			efficacy.WastedSleep += success.Start.Sub(successes[index])
+		}
+	}
+
+	if len(recoveries) == 0 {
+		return
+	}
+
+	slices.Sort(recoveries)
+
+	efficacy.SuggestedMinDelay = recoveries[len(recoveries)/10] / 2
+	efficacy.SuggestedMaxDelay = recoveries[len(recoveries)*9/10]
+
+	return
+}
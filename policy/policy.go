@@ -0,0 +1,291 @@
+package policy
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.source.hueristiq.com/retrier/backoff"
+)
+
+// Policy describes the settings that govern a retry sequence.
+//
+// Fields:
+//   - MaxRetries: The maximum number of attempts.
+//   - MinDelay: The minimum delay between attempts.
+//   - MaxDelay: The maximum delay between attempts.
+//   - Backoff: The backoff strategy. A nil Backoff stands for the strategy named by Strategy, Jitter, and
+//     Multiplier, backoff.Exponential() by default.
+//   - Strategy: The name of the backoff strategy, used when Backoff is nil: StrategyExponential (the
+//     default), StrategyLinear, StrategyConstant, or a name registered with backoff.Register.
+//   - Jitter: The name of the jitter applied to the backoff strategy, used when Backoff is nil:
+//     JitterNone (the default), JitterFull, JitterEqual, JitterDecorrelated, or a name registered with
+//     jitter.Register.
+//   - Multiplier: The factor the delays of StrategyExponential grow by, 2 by default.
+//   - MaxElapsedTime: The wall-clock time after which the retry sequence stops retrying, or 0 for no limit.
+type Policy struct {
+	MaxRetries     int
+	MinDelay       time.Duration
+	MaxDelay       time.Duration
+	Backoff        backoff.Backoff
+	Strategy       string
+	Jitter         string
+	Multiplier     float64
+	MaxElapsedTime time.Duration
+}
+
+// Delay returns the delay the policy waits for after the given failed attempt.
+//
+// Parameters:
+//   - attempt: The zero-based number of the failed attempt.
+//
+// Returns:
+//   - delay: The delay computed by the backoff strategy, jittered if the strategy is.
+func (p Policy) Delay(attempt int) (delay time.Duration) {
+	delay = p.strategy()(p.MinDelay, p.MaxDelay, attempt)
+
+	return
+}
+
+// strategy returns the backoff strategy of the policy, defaulting to backoff.Exponential() if the named
+// strategy is invalid.
+func (p Policy) strategy() (strategy backoff.Backoff) {
+	strategy, err := p.BuildBackoff()
+	if err != nil {
+		strategy = backoff.Exponential()
+	}
+
+	return
+}
+
+// ScheduleEntry describes the delay that follows a failed attempt.
+//
+// Fields:
+//   - Attempt: The zero-based number of the failed attempt the delay follows.
+//   - Nominal: The median delay, i.e., the delay in the middle of the jitter band.
+//   - Low: The shortest delay, i.e., the lower end of the jitter band.
+//   - High: The longest delay, i.e., the upper end of the jitter band.
+type ScheduleEntry struct {
+	Attempt int           `json:"attempt"`
+	Nominal time.Duration `json:"nominal"`
+	Low     time.Duration `json:"low"`
+	High    time.Duration `json:"high"`
+}
+
+// Schedule is the effective schedule of delays of a Policy, one entry per failed attempt.
+type Schedule []ScheduleEntry
+
+// scheduleSamples is the number of times the backoff strategy is sampled per attempt to determine the
+// jitter band of a policy whose jitter.Strategy is unknown. Deterministic strategies yield a band of
+// width zero.
+const scheduleSamples = 1000
+
+// ExportSchedule computes the effective schedule of delays of a Policy. For a jittered strategy whose
+// jitter.Strategy is known through backoff.JitterOf, the jitter band of each delay is given by the
+// Bounds of that strategy. As a Backoff is otherwise an opaque function, the jitter band is determined
+// empirically, by sampling the backoff strategy.
+//
+// Parameters:
+//   - p:        The Policy whose schedule is computed.
+//   - attempts: The number of failed attempts to compute the delay for. If it is not positive, the
+//     schedule covers the delays between the MaxRetries attempts of the policy.
+//
+// Returns:
+//   - schedule: The effective schedule of delays.
+//
+// Example:
+//
+//	schedule := policy.ExportSchedule(p, 5)
+//	_ = schedule.WriteCSV(os.Stdout)
+func ExportSchedule(p Policy, attempts int) (schedule Schedule) {
+	strategy := p.strategy()
+
+	if attempts <= 0 {
+		attempts = p.MaxRetries - 1
+	}
+
+	schedule = make(Schedule, 0, max(attempts, 0))
+
+	if _, _, _, ok := backoff.JitterOf(strategy); ok {
+		for attempt := range attempts {
+			low, high := p.band(strategy, attempt)
+
+			schedule = append(schedule, ScheduleEntry{Attempt: attempt, Nominal: low + (high-low)/2, Low: low, High: high})
+		}
+
+		return
+	}
+
+	samples := make([]time.Duration, scheduleSamples)
+
+	for attempt := range attempts {
+		for i := range samples {
+			samples[i] = strategy(p.MinDelay, p.MaxDelay, attempt)
+		}
+
+		slices.Sort(samples)
+
+		schedule = append(schedule, ScheduleEntry{
+			Attempt: attempt,
+			Nominal: samples[len(samples)/2],
+			Low:     samples[0],
+			High:    samples[len(samples)-1],
+		})
+	}
+
+	return
+}
+
+// band returns the jitter band of the delay of a jittered strategy before an attempt, from the Bounds
+// of the jitter strategy it applies to the band of its base, sampled if the base is not jittered.
+//
+// Parameters:
+//   - b:       The backoff strategy.
+//   - attempt: The zero-based number of the failed attempt the delay follows.
+//
+// Returns:
+//   - low:  The shortest delay.
+//   - high: The longest delay.
+func (p Policy) band(b backoff.Backoff, attempt int) (low, high time.Duration) {
+	base, j, additive, ok := backoff.JitterOf(b)
+	if !ok {
+		low, high = b(p.MinDelay, p.MaxDelay, attempt), time.Duration(0)
+
+		for range scheduleSamples {
+			delay := b(p.MinDelay, p.MaxDelay, attempt)
+
+			low, high = min(low, delay), max(high, delay)
+		}
+
+		return
+	}
+
+	baseLow, baseHigh := p.band(base, attempt)
+
+	low, _ = j.Bounds(baseLow)
+	_, high = j.Bounds(baseHigh)
+
+	if additive {
+		low, high = backoff.SafeAdd(baseLow, low), backoff.SafeAdd(baseHigh, high)
+	}
+
+	low, high = min(low, p.MaxDelay), min(high, p.MaxDelay)
+
+	return
+}
+
+// WriteCSV writes the schedule as CSV, with a header row and delays formatted as durations, e.g., "1.5s".
+//
+// Parameters:
+//   - w: The writer to write the CSV to.
+//
+// Returns:
+//   - err: The error encountered while writing, if any.
+func (s Schedule) WriteCSV(w io.Writer) (err error) {
+	writer := csv.NewWriter(w)
+
+	records := make([][]string, 0, len(s)+1)
+
+	records = append(records, []string{"attempt", "nominal", "low", "high"})
+
+	for _, entry := range s {
+		records = append(records, []string{
+			strconv.Itoa(entry.Attempt),
+			entry.Nominal.String(),
+			entry.Low.String(),
+			entry.High.String(),
+		})
+	}
+
+	err = writer.WriteAll(records)
+
+	return
+}
+
+// WriteJSON writes the schedule as a JSON array, with delays expressed in nanoseconds.
+//
+// Parameters:
+//   - w: The writer to write the JSON to.
+//
+// Returns:
+//   - err: The error encountered while writing, if any.
+func (s Schedule) WriteJSON(w io.Writer) (err error) {
+	err = json.NewEncoder(w).Encode(s)
+
+	return
+}
+
+// Chart renders the schedule as an ASCII chart, one row per attempt. Each row shows a bar scaled to the
+// longest delay of the schedule, where '#' spans up to the lower end of the jitter band and '=' spans
+// the jitter band itself, followed by the band's bounds.
+//
+// Parameters:
+//   - width: The width of the longest bar, in characters.
+//
+// Returns:
+//   - chart: The rendered chart.
+//
+// Example:
+//
+//	fmt.Print(policy.ExportSchedule(p, 3).Chart(20))
+//	// 0 |#####               | 100ms
+//	// 1 |##########          | 200ms
+//	// 2 |####################| 400ms
+func (s Schedule) Chart(width int) (chart string) {
+	var longest time.Duration
+
+	for _, entry := range s {
+		longest = max(longest, entry.High)
+	}
+
+	label := len(strconv.Itoa(len(s) - 1))
+
+	var builder strings.Builder
+
+	for _, entry := range s {
+		low, high := scale(entry.Low, longest, width), scale(entry.High, longest, width)
+
+		builder.WriteString(strings.Repeat(" ", label-len(strconv.Itoa(entry.Attempt))))
+		builder.WriteString(strconv.Itoa(entry.Attempt))
+		builder.WriteString(" |")
+		builder.WriteString(strings.Repeat("#", low))
+		builder.WriteString(strings.Repeat("=", high-low))
+		builder.WriteString(strings.Repeat(" ", width-high))
+		builder.WriteString("| ")
+		builder.WriteString(entry.Low.String())
+
+		if entry.High != entry.Low {
+			builder.WriteString(" - ")
+			builder.WriteString(entry.High.String())
+		}
+
+		builder.WriteString("\n")
+	}
+
+	chart = builder.String()
+
+	return
+}
+
+// scale converts a delay into a bar length relative to the longest delay.
+//
+// Parameters:
+//   - delay:   The delay to scale.
+//   - longest: The delay that maps to the full width.
+//   - width:   The full width, in characters.
+//
+// Returns:
+//   - length: The bar length, between 0 and width.
+func scale(delay, longest time.Duration, width int) (length int) {
+	if longest <= 0 || delay <= 0 {
+		return
+	}
+
+	length = min(int(float64(delay)/float64(longest)*float64(width)), width)
+
+	return
+}
@@ -0,0 +1,272 @@
+package policy_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.source.hueristiq.com/retrier/backoff"
+	"go.source.hueristiq.com/retrier/jitter"
+	"go.source.hueristiq.com/retrier/policy"
+)
+
+func TestExportSchedule(t *testing.T) {
+	t.Parallel()
+
+	p := policy.Policy{
+		MaxRetries: 5,
+		MinDelay:   100 * time.Millisecond,
+		MaxDelay:   time.Second,
+	}
+
+	schedule := policy.ExportSchedule(p, 5)
+
+	expected := []time.Duration{
+		100 * time.Millisecond,
+		200 * time.Millisecond,
+		400 * time.Millisecond,
+		800 * time.Millisecond,
+		time.Second,
+	}
+
+	require.Len(t, schedule, len(expected), "Expected one entry per attempt")
+
+	for i, entry := range schedule {
+		assert.Equal(t, i, entry.Attempt, "Unexpected attempt number")
+		assert.Equal(t, expected[i], entry.Nominal, "Unexpected nominal delay for attempt %d", i)
+		assert.Equal(t, entry.Nominal, entry.Low, "Expected no jitter band for attempt %d", i)
+		assert.Equal(t, entry.Nominal, entry.High, "Expected no jitter band for attempt %d", i)
+	}
+}
+
+func TestExportSchedule_JitterBand(t *testing.T) {
+	t.Parallel()
+
+	p := policy.Policy{
+		MinDelay: 100 * time.Millisecond,
+		MaxDelay: 10 * time.Second,
+		Backoff:  backoff.ExponentialWithSymmetricJitter(0.5),
+	}
+
+	for _, entry := range policy.ExportSchedule(p, 3) {
+		nominal := 100 * time.Millisecond << entry.Attempt
+
+		assert.Less(t, entry.Low, entry.High, "Expected a jitter band for attempt %d", entry.Attempt)
+		assert.GreaterOrEqual(t, entry.Low, nominal/2, "Unexpected lower end of the jitter band")
+		assert.LessOrEqual(t, entry.High, nominal*3/2, "Unexpected upper end of the jitter band")
+	}
+}
+
+func TestExportSchedule_JitterBounds(t *testing.T) {
+	t.Parallel()
+
+	schedule := policy.ExportSchedule(policy.Policy{
+		MinDelay: time.Second,
+		MaxDelay: 12 * time.Second,
+		Strategy: policy.StrategyExponential,
+		Jitter:   policy.JitterFull,
+	}, 4)
+
+	expected := []policy.ScheduleEntry{
+		{Attempt: 0, Nominal: 1500 * time.Millisecond, Low: time.Second, High: 2 * time.Second},
+		{Attempt: 1, Nominal: 3 * time.Second, Low: 2 * time.Second, High: 4 * time.Second},
+		{Attempt: 2, Nominal: 6 * time.Second, Low: 4 * time.Second, High: 8 * time.Second},
+		{Attempt: 3, Nominal: 10 * time.Second, Low: 8 * time.Second, High: 12 * time.Second},
+	}
+
+	assert.Equal(t, policy.Schedule(expected), schedule, "Expected the jitter band of the full jitter added to the exponential delays, capped at MaxDelay")
+
+	schedule = policy.ExportSchedule(policy.Policy{
+		MinDelay: time.Second,
+		MaxDelay: time.Minute,
+		Strategy: policy.StrategyConstant,
+		Jitter:   policy.JitterEqual,
+	}, 1)
+
+	assert.Equal(t, policy.Schedule{{Attempt: 0, Nominal: 750 * time.Millisecond, Low: 500 * time.Millisecond, High: time.Second}}, schedule, "Expected the jitter band of the equal jitter replacing the constant delay")
+
+	schedule = policy.ExportSchedule(policy.Policy{
+		MinDelay: time.Second,
+		MaxDelay: time.Minute,
+		Backoff:  backoff.WithJitter(backoff.Linear(0), jitter.NewFull(jitter.WithFloor(400*time.Millisecond))),
+	}, 1)
+
+	assert.Equal(t, policy.Schedule{{Attempt: 0, Nominal: 700 * time.Millisecond, Low: 400 * time.Millisecond, High: time.Second}}, schedule, "Expected the jitter band of the jitter strategy actually applied, with its floor")
+}
+
+func TestSchedule_Export(t *testing.T) {
+	t.Parallel()
+
+	schedule := policy.ExportSchedule(policy.Policy{MinDelay: 100 * time.Millisecond, MaxDelay: time.Second}, 3)
+
+	var csv bytes.Buffer
+
+	require.NoError(t, schedule.WriteCSV(&csv), "Expected the schedule to be written as CSV")
+	assert.Equal(t, "attempt,nominal,low,high\n0,100ms,100ms,100ms\n1,200ms,200ms,200ms\n2,400ms,400ms,400ms\n", csv.String(), "Unexpected CSV")
+
+	var buffer bytes.Buffer
+
+	require.NoError(t, schedule.WriteJSON(&buffer), "Expected the schedule to be written as JSON")
+
+	var decoded policy.Schedule
+
+	require.NoError(t, json.Unmarshal(buffer.Bytes(), &decoded), "Expected the JSON to be decodable")
+	assert.Equal(t, schedule, decoded, "Expected the JSON to round-trip")
+
+	assert.Equal(t, "0 |#####               | 100ms\n1 |##########          | 200ms\n2 |####################| 400ms\n", schedule.Chart(20), "Unexpected chart")
+}
+
+func TestExportSchedule_MaxRetries(t *testing.T) {
+	t.Parallel()
+
+	schedule := policy.ExportSchedule(policy.Policy{MaxRetries: 4, MinDelay: time.Millisecond, MaxDelay: time.Second}, 0)
+
+	assert.Len(t, schedule, 3, "Expected one entry per delay between the attempts of the policy")
+}
+
+func TestPolicy_Validate(t *testing.T) {
+	t.Parallel()
+
+	require.NoError(t, policy.Policy{MaxRetries: 3, MinDelay: time.Millisecond, MaxDelay: time.Second}.Validate(), "Expected a consistent policy to be valid")
+	require.NoError(t, policy.Policy{MaxRetries: -1}.Validate(), "Expected an unbounded policy to be valid")
+
+	err := policy.Policy{MaxRetries: 0, MinDelay: 2 * time.Second, MaxDelay: time.Second}.Validate()
+
+	require.ErrorIs(t, err, policy.ErrInvalidPolicy, "Expected an inconsistent policy to be invalid")
+
+	var target *policy.ValidationError
+
+	require.ErrorAs(t, err, &target, "Expected a ValidationError")
+
+	fields := make([][]string, 0, len(target.Problems))
+
+	for _, problem := range target.Problems {
+		fields = append(fields, problem.Fields)
+	}
+
+	assert.Equal(t, [][]string{{"MaxRetries"}, {"MinDelay", "MaxDelay"}}, fields, "Expected every offending field to be listed")
+	assert.Contains(t, err.Error(), "MinDelay 2s is greater than MaxDelay 1s", "Expected an actionable message")
+}
+
+func TestFromJSON(t *testing.T) {
+	t.Parallel()
+
+	p, err := policy.FromJSON([]byte(`{
+		"max_retries": 5,
+		"min_delay": "100ms",
+		"max_delay": "10s",
+		"strategy": "exponential",
+		"multiplier": 3,
+		"max_elapsed_time": "1m"
+	}`))
+
+	require.NoError(t, err)
+	assert.Equal(t, 5, p.MaxRetries)
+	assert.Equal(t, 100*time.Millisecond, p.MinDelay)
+	assert.Equal(t, 10*time.Second, p.MaxDelay)
+	assert.Equal(t, time.Minute, p.MaxElapsedTime)
+	require.NotNil(t, p.Backoff, "Expected the backoff strategy to be built")
+	assert.Equal(t, 900*time.Millisecond, p.Delay(2), "Expected the delays to grow by the multiplier")
+	assert.Equal(t, 10*time.Second, p.Delay(10), "Expected the delays to be capped")
+
+	data, err := json.Marshal(p)
+
+	require.NoError(t, err)
+
+	roundTripped, err := policy.FromJSON(data)
+
+	require.NoError(t, err)
+	assert.Equal(t, p.Delay(3), roundTripped.Delay(3), "Expected the policy to round-trip")
+
+	_, err = policy.FromJSON([]byte(`{"max_retries": 3, "min_delay": "1s", "max_delay": "2s", "retries": 3}`))
+
+	require.Error(t, err, "Expected unknown fields to be rejected")
+
+	_, err = policy.FromJSON([]byte(`{"max_retries": 3, "min_delay": "soon", "max_delay": "2s"}`))
+
+	require.ErrorContains(t, err, "min_delay", "Expected malformed durations to be rejected")
+
+	_, err = policy.FromJSON([]byte(`{"max_retries": 3, "min_delay": "1s", "max_delay": "2s", "strategy": "fibonacci", "jitter": "decorrelated", "multiplier": 0.5}`))
+
+	require.ErrorIs(t, err, policy.ErrInvalidPolicy, "Expected unknown strategies to be rejected")
+
+	var target *policy.ValidationError
+
+	require.ErrorAs(t, err, &target)
+	assert.Len(t, target.Problems, 3, "Expected the strategy, the jitter, and the multiplier to be reported")
+}
+
+func TestFromYAML(t *testing.T) {
+	t.Parallel()
+
+	p, err := policy.FromYAML([]byte("max_retries: 4\nmin_delay: 1s\nmax_delay: 5s\nstrategy: linear\njitter: full\n"))
+
+	require.NoError(t, err)
+	assert.Equal(t, 4, p.MaxRetries)
+	assert.Equal(t, policy.JitterFull, p.Jitter)
+
+	for range 20 {
+		delay := p.Delay(2)
+
+		assert.GreaterOrEqual(t, delay, 3*time.Second, "Expected linear delays of 1s + 1s * 2, jittered upwards")
+		assert.LessOrEqual(t, delay, 5*time.Second, "Expected linear delays capped at the maximum delay")
+	}
+
+	_, err = policy.FromYAML([]byte("max_retries: 4\nmin_delay: 1s\nmax_delay: 5s\nbackoff: linear\n"))
+
+	require.Error(t, err, "Expected unknown fields to be rejected")
+
+	constant, err := policy.FromYAML([]byte("max_retries: 4\nmin_delay: 2s\nmax_delay: 5s\nstrategy: constant\n"))
+
+	require.NoError(t, err)
+	assert.Equal(t, 2*time.Second, constant.Delay(3), "Expected constant delays")
+}
+
+func TestFromJSON_RegisteredStrategies(t *testing.T) {
+	t.Parallel()
+
+	require.NoError(t, backoff.Register("policy-test-steps", func() backoff.Backoff {
+		return backoff.Schedule(time.Second, 3*time.Second)
+	}))
+	require.NoError(t, jitter.Register("policy-test-none", func() jitter.Strategy {
+		return jitter.NewSymmetric(0)
+	}))
+
+	p, err := policy.FromJSON([]byte(`{"max_retries": 3, "min_delay": "1s", "max_delay": "5s", "strategy": "policy-test-steps", "jitter": "policy-test-none"}`))
+
+	require.NoError(t, err, "Expected registered strategies to be resolved by name")
+	assert.Equal(t, 3*time.Second, p.Delay(1))
+}
+
+func TestAnalyze(t *testing.T) {
+	t.Parallel()
+
+	origin := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	at := func(offset time.Duration, failed bool) policy.AttemptRecord {
+		return policy.AttemptRecord{Start: origin.Add(offset), Duration: 0, Failed: failed}
+	}
+
+	timelines := []policy.Timeline{
+		// Recovers at 3s, as first observed by this sequence.
+		{at(0, true), at(time.Second, true), at(3*time.Second, false)},
+		// Sleeps from its last failure at 2s until 10s, while the dependency was up from 3s on.
+		{at(0, true), at(2*time.Second, true), at(10*time.Second, false)},
+		// Never fails.
+		{at(5*time.Second, false)},
+	}
+
+	efficacy := policy.Analyze(timelines)
+
+	assert.Equal(t, 3, efficacy.Sequences, "Unexpected number of sequences")
+	assert.Equal(t, 2, efficacy.Recovered, "Unexpected number of recovered sequences")
+	assert.Equal(t, 2, efficacy.PrematureRetries, "Unexpected number of premature retries")
+	assert.Equal(t, 7*time.Second, efficacy.WastedSleep, "Unexpected wasted sleep")
+	assert.Equal(t, 1500*time.Millisecond, efficacy.SuggestedMinDelay, "Unexpected suggested minimum delay")
+	assert.Equal(t, 10*time.Second, efficacy.SuggestedMaxDelay, "Unexpected suggested maximum delay")
+
+	assert.Zero(t, policy.Analyze(nil).SuggestedMaxDelay, "Expected no suggestion without recoveries")
+}
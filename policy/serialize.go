@@ -0,0 +1,311 @@
+package policy
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math"
+	"strconv"
+	"time"
+
+	"go.source.hueristiq.com/retrier/backoff"
+	"go.source.hueristiq.com/retrier/jitter"
+	"gopkg.in/yaml.v3"
+)
+
+// The names of the backoff strategies of a Policy.
+const (
+	// StrategyExponential multiplies the delay by Multiplier with each attempt.
+	StrategyExponential = "exponential"
+	// StrategyLinear increases the delay by MinDelay with each attempt.
+	StrategyLinear = "linear"
+	// StrategyConstant waits for MinDelay between attempts.
+	StrategyConstant = "constant"
+)
+
+// The names of the jitter applied to the backoff strategy of a Policy.
+const (
+	// JitterNone applies no jitter.
+	JitterNone = "none"
+	// JitterFull draws the delay between 0 and the backoff delay.
+	JitterFull = "full"
+	// JitterEqual draws the delay between half the backoff delay and the backoff delay.
+	JitterEqual = "equal"
+	// JitterDecorrelated draws the delay from the previous one, for StrategyExponential only.
+	JitterDecorrelated = "decorrelated"
+)
+
+// document is the serialized form of a Policy, with durations written as strings, e.g., "1.5s".
+type document struct {
+	MaxRetries     int     `json:"max_retries"                yaml:"max_retries"`
+	MinDelay       string  `json:"min_delay"                  yaml:"min_delay"`
+	MaxDelay       string  `json:"max_delay"                  yaml:"max_delay"`
+	Strategy       string  `json:"strategy,omitempty"         yaml:"strategy,omitempty"`
+	Jitter         string  `json:"jitter,omitempty"           yaml:"jitter,omitempty"`
+	Multiplier     float64 `json:"multiplier,omitempty"       yaml:"multiplier,omitempty"`
+	MaxElapsedTime string  `json:"max_elapsed_time,omitempty" yaml:"max_elapsed_time,omitempty"`
+}
+
+// FromJSON loads a Policy from a JSON document, e.g., a section of a configuration file, so that
+// services can change their retry behavior without code changes. Durations are written as strings,
+// e.g., "1.5s", and unknown fields are rejected. The backoff strategy is built from the named strategy,
+// jitter, and multiplier, and the policy is validated.
+//
+// Parameters:
+//   - data: The JSON document.
+//
+// Returns:
+//   - p:   The loaded Policy.
+//   - err: An error if the document is malformed, or a *ValidationError if the policy is invalid.
+//
+// Example:
+//
+//	p, err := policy.FromJSON([]byte(`{"max_retries": 5, "min_delay": "100ms", "max_delay": "10s", "jitter": "full"}`))
+//	err = retrier.Retry(ctx, operation, retrier.WithPolicy(p))
+func FromJSON(data []byte) (p Policy, err error) {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+
+	decoder.DisallowUnknownFields()
+
+	var doc document
+
+	if err = decoder.Decode(&doc); err != nil {
+		err = fmt.Errorf("decoding policy: %w", err)
+
+		return
+	}
+
+	p, err = doc.policy()
+
+	return
+}
+
+// FromYAML loads a Policy from a YAML document, like FromJSON, with the same field names.
+//
+// Parameters:
+//   - data: The YAML document.
+//
+// Returns:
+//   - p:   The loaded Policy.
+//   - err: An error if the document is malformed, or a *ValidationError if the policy is invalid.
+//
+// Example:
+//
+//	p, err := policy.FromYAML([]byte("max_retries: 5\nmin_delay: 100ms\nmax_delay: 10s\njitter: full\n"))
+func FromYAML(data []byte) (p Policy, err error) {
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+
+	decoder.KnownFields(true)
+
+	var doc document
+
+	if err = decoder.Decode(&doc); err != nil {
+		err = fmt.Errorf("decoding policy: %w", err)
+
+		return
+	}
+
+	p, err = doc.policy()
+
+	return
+}
+
+// MarshalJSON implements json.Marshaler, writing the policy in the format read by FromJSON. A custom
+// Backoff cannot be serialized and is left out: only the named strategy is written.
+//
+// Returns:
+//   - data: The JSON document.
+//   - err:  The error encountered while encoding, if any.
+func (p Policy) MarshalJSON() (data []byte, err error) {
+	data, err = json.Marshal(p.document())
+
+	return
+}
+
+// MarshalYAML implements yaml.Marshaler, writing the policy in the format read by FromYAML, like
+// MarshalJSON.
+//
+// Returns:
+//   - value: The document to encode.
+//   - err:   Always nil.
+func (p Policy) MarshalYAML() (value any, err error) {
+	value = p.document()
+
+	return
+}
+
+// document returns the serialized form of the policy.
+func (p Policy) document() (doc document) {
+	doc = document{
+		MaxRetries: p.MaxRetries,
+		MinDelay:   p.MinDelay.String(),
+		MaxDelay:   p.MaxDelay.String(),
+		Strategy:   p.Strategy,
+		Jitter:     p.Jitter,
+		Multiplier: p.Multiplier,
+	}
+
+	if p.MaxElapsedTime != 0 {
+		doc.MaxElapsedTime = p.MaxElapsedTime.String()
+	}
+
+	return
+}
+
+// policy returns the validated Policy a document describes, with its backoff strategy built.
+func (doc document) policy() (p Policy, err error) {
+	p = Policy{
+		MaxRetries: doc.MaxRetries,
+		Strategy:   doc.Strategy,
+		Jitter:     doc.Jitter,
+		Multiplier: doc.Multiplier,
+	}
+
+	durations := []struct {
+		name  string
+		value string
+		into  *time.Duration
+	}{
+		{"min_delay", doc.MinDelay, &p.MinDelay},
+		{"max_delay", doc.MaxDelay, &p.MaxDelay},
+		{"max_elapsed_time", doc.MaxElapsedTime, &p.MaxElapsedTime},
+	}
+
+	for _, duration := range durations {
+		if duration.value == "" {
+			continue
+		}
+
+		if *duration.into, err = time.ParseDuration(duration.value); err != nil {
+			err = fmt.Errorf("decoding policy: %s: %w", duration.name, err)
+
+			return
+		}
+	}
+
+	if err = p.Validate(); err != nil {
+		return
+	}
+
+	p.Backoff, err = p.BuildBackoff()
+
+	return
+}
+
+// BuildBackoff returns the backoff strategy of the policy: Backoff if set, or the strategy named by
+// Strategy, Jitter, and Multiplier otherwise.
+//
+// Returns:
+//   - strategy: The backoff strategy.
+//   - err:      A *ValidationError if the named strategy is invalid.
+func (p Policy) BuildBackoff() (strategy backoff.Backoff, err error) {
+	if p.Backoff != nil {
+		strategy = p.Backoff
+
+		return
+	}
+
+	if problems := p.validateStrategy(); len(problems) > 0 {
+		err = &ValidationError{Problems: problems}
+
+		return
+	}
+
+	// The built-in jitter and the custom one registered with jitter.Register, if any.
+	j, _ := jitter.Get(p.Jitter)
+
+	switch {
+	case p.Strategy == StrategyLinear && p.Jitter == JitterFull:
+		strategy = backoff.LinearWithFullJitter(p.MinDelay)
+	case p.Strategy == StrategyLinear && p.Jitter == JitterEqual:
+		strategy = backoff.LinearWithEqualJitter(p.MinDelay)
+	case p.Strategy == StrategyLinear:
+		strategy = jittered(backoff.Linear(p.MinDelay), j)
+	case p.Strategy == StrategyConstant:
+		strategy = jittered(backoff.Linear(0), j)
+	case p.Strategy == "" || p.Strategy == StrategyExponential:
+		strategy = exponential(p.Multiplier, p.Jitter, j)
+	default:
+		// A custom strategy registered with backoff.Register.
+		base, _ := backoff.Get(p.Strategy)
+
+		strategy = jittered(base, j)
+	}
+
+	return
+}
+
+// jittered applies a jitter strategy to a backoff strategy, if any.
+func jittered(b backoff.Backoff, j jitter.Strategy) (strategy backoff.Backoff) {
+	strategy = b
+
+	if j != nil {
+		strategy = backoff.WithJitter(b, j)
+	}
+
+	return
+}
+
+// exponential returns the exponential backoff strategy growing by a multiplier, with the built-in
+// jittered variants for the default multiplier.
+func exponential(multiplier float64, name string, j jitter.Strategy) (strategy backoff.Backoff) {
+	if multiplier == 0 || multiplier == 2 {
+		switch name {
+		case JitterFull:
+			strategy = backoff.ExponentialWithFullJitter()
+		case JitterEqual:
+			strategy = backoff.ExponentialWithEqualJitter()
+		case JitterDecorrelated:
+			strategy = backoff.ExponentialWithDecorrelatedJitter()
+		default:
+			strategy = jittered(backoff.Exponential(), j)
+		}
+
+		return
+	}
+
+	strategy = jittered(func(minDelay, maxDelay time.Duration, attempt int) (delay time.Duration) {
+		delay = min(backoff.SafeMul(minDelay, math.Pow(multiplier, float64(attempt))), maxDelay)
+
+		return
+	}, j)
+
+	return
+}
+
+// validateStrategy checks the named strategy, jitter, and multiplier of the policy.
+func (p Policy) validateStrategy() (problems []*FieldError) {
+	switch _, registered := backoff.Get(p.Strategy); {
+	case p.Strategy == "", p.Strategy == StrategyExponential, p.Strategy == StrategyLinear, p.Strategy == StrategyConstant, registered:
+	default:
+		problems = append(problems, &FieldError{
+			Fields: []string{"Strategy"},
+			Reason: "unknown strategy " + strconv.Quote(p.Strategy),
+		})
+	}
+
+	switch _, registered := jitter.Get(p.Jitter); {
+	case p.Jitter == "", p.Jitter == JitterNone, registered:
+	case p.Jitter == JitterDecorrelated:
+		if p.Strategy != "" && p.Strategy != StrategyExponential || p.Multiplier != 0 && p.Multiplier != 2 {
+			problems = append(problems, &FieldError{
+				Fields: []string{"Strategy", "Jitter"},
+				Reason: "decorrelated jitter requires the exponential strategy with the default multiplier",
+			})
+		}
+	default:
+		problems = append(problems, &FieldError{
+			Fields: []string{"Jitter"},
+			Reason: "unknown jitter " + strconv.Quote(p.Jitter),
+		})
+	}
+
+	if p.Multiplier != 0 && p.Multiplier < 1 {
+		problems = append(problems, &FieldError{
+			Fields: []string{"Multiplier"},
+			Reason: "multiplier " + strconv.FormatFloat(p.Multiplier, 'g', -1, 64) + " is less than 1",
+		})
+	}
+
+	return
+}
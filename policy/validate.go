@@ -0,0 +1,123 @@
+package policy
+
+import (
+	"errors"
+	"strings"
+)
+
+// ErrInvalidPolicy is wrapped by every ValidationError.
+var ErrInvalidPolicy = errors.New("invalid policy")
+
+// FieldError describes an inconsistency of a Policy.
+//
+// Fields:
+//   - Fields: The names of the offending fields, e.g., "MinDelay" and "MaxDelay".
+//   - Reason: The description of the inconsistency and how to resolve it.
+type FieldError struct {
+	Fields []string
+	Reason string
+}
+
+// Error implements the error interface.
+//
+// Returns:
+//   - message: The offending fields and the reason.
+func (e *FieldError) Error() (message string) {
+	message = strings.Join(e.Fields, ", ") + ": " + e.Reason
+
+	return
+}
+
+// ValidationError lists every inconsistency found by Validate, so that a policy loaded from
+// configuration can be fixed in one go.
+//
+// Fields:
+//   - Problems: The inconsistencies of the policy.
+type ValidationError struct {
+	Problems []*FieldError
+}
+
+// Error implements the error interface.
+//
+// Returns:
+//   - message: The inconsistencies of the policy, separated by semicolons.
+func (e *ValidationError) Error() (message string) {
+	problems := make([]string, 0, len(e.Problems))
+
+	for _, problem := range e.Problems {
+		problems = append(problems, problem.Error())
+	}
+
+	message = ErrInvalidPolicy.Error() + ": " + strings.Join(problems, "; ")
+
+	return
+}
+
+// Unwrap returns ErrInvalidPolicy, so that errors.Is(err, ErrInvalidPolicy) reports invalid policies.
+//
+// Returns:
+//   - err: ErrInvalidPolicy.
+func (e *ValidationError) Unwrap() (err error) {
+	err = ErrInvalidPolicy
+
+	return
+}
+
+// Validate checks the consistency of the fields of a policy, e.g., one loaded from configuration,
+// which the retrier would otherwise silently resolve or ignore.
+//
+// Returns:
+//   - err: A *ValidationError listing every inconsistency, or nil if the policy is consistent.
+//
+// Example:
+//
+//	err := policy.Policy{MaxRetries: 3, MinDelay: 2 * time.Second, MaxDelay: time.Second}.Validate()
+//	// err: invalid policy: MinDelay, MaxDelay: MinDelay 2s is greater than MaxDelay 1s
+func (p Policy) Validate() (err error) {
+	var problems []*FieldError
+
+	if p.MaxRetries == 0 {
+		problems = append(problems, &FieldError{
+			Fields: []string{"MaxRetries"},
+			Reason: "0 attempts never execute the operation; use a negative value for unbounded retries",
+		})
+	}
+
+	if p.MinDelay < 0 {
+		problems = append(problems, &FieldError{
+			Fields: []string{"MinDelay"},
+			Reason: "negative delay " + p.MinDelay.String(),
+		})
+	}
+
+	if p.MaxDelay < 0 {
+		problems = append(problems, &FieldError{
+			Fields: []string{"MaxDelay"},
+			Reason: "negative delay " + p.MaxDelay.String(),
+		})
+	}
+
+	if p.MinDelay > p.MaxDelay {
+		problems = append(problems, &FieldError{
+			Fields: []string{"MinDelay", "MaxDelay"},
+			Reason: "MinDelay " + p.MinDelay.String() + " is greater than MaxDelay " + p.MaxDelay.String(),
+		})
+	}
+
+	if p.MaxElapsedTime < 0 {
+		problems = append(problems, &FieldError{
+			Fields: []string{"MaxElapsedTime"},
+			Reason: "negative duration " + p.MaxElapsedTime.String(),
+		})
+	}
+
+	if p.Backoff == nil {
+		problems = append(problems, p.validateStrategy()...)
+	}
+
+	if len(problems) > 0 {
+		err = &ValidationError{Problems: problems}
+	}
+
+	return
+}
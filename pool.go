@@ -0,0 +1,273 @@
+package retrier
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"sync"
+)
+
+// ErrPoolClosed is returned by Pool.Submit once the pool is closed.
+var ErrPoolClosed = errors.New("pool closed")
+
+// Pool executes tasks with retries at bounded concurrency. It bounds the attempts in flight rather
+// than the tasks: a task holds a slot only while one of its attempts executes, and releases it during
+// its backoff delays, so that tasks waiting to retry do not starve the others of workers.
+//
+// Attempts waiting for a slot are scheduled fairly across keys, see SubmitKeyed, in round-robin, so that
+// one hot failing key with many due retries cannot starve the other keys.
+//
+// A Pool is safe for concurrent use.
+type Pool struct {
+	slots *slots
+	opts  []Option
+
+	mutex  sync.Mutex
+	closed bool
+	tasks  sync.WaitGroup
+}
+
+// NewPool returns a Pool executing at most workers attempts at a time.
+//
+// Parameters:
+//   - workers: The maximum number of attempts executing at a time. It is raised to 1 if it is lower.
+//   - opts:    The configuration options applied to the retry sequence of every task.
+//
+// Returns:
+//   - pool: The new Pool.
+//
+// Example:
+//
+//	pool := retrier.NewPool(8, retrier.ProfileBatch())
+//	defer pool.Close()
+func NewPool(workers int, opts ...Option) (pool *Pool) {
+	pool = &Pool{
+		slots: newSlots(max(workers, 1)),
+		opts:  opts,
+	}
+
+	return
+}
+
+// Submit schedules a task, retried with the options of the pool followed by the provided ones, which
+// override the policy of the pool for this task alone. It is SubmitKeyed with an empty key.
+//
+// Parameters:
+//   - ctx:  A context to control the lifetime of the retry sequence of the task, including the wait for a slot.
+//   - task: The operation to execute.
+//   - opts: Optional configuration options overriding the options of the pool.
+//
+// Returns:
+//   - done: A channel receiving the outcome of the task, as returned by Retry, once it completes.
+//   - err:  ErrPoolClosed if the pool is closed.
+//
+// Example:
+//
+//	done, err := pool.Submit(ctx, sendEmail, retrier.WithMaxRetries(10))
+//	if err != nil {
+//	    return err
+//	}
+//
+//	err = <-done
+func (p *Pool) Submit(ctx context.Context, task Operation, opts ...Option) (done <-chan error, err error) {
+	done, err = p.SubmitKeyed(ctx, "", task, opts...)
+
+	return
+}
+
+// SubmitKeyed schedules a task under a key, e.g., the tenant or the destination it serves. When slots
+// free up, the attempts waiting for one are served in round-robin across keys, and in order within a
+// key, so that the attempts of a key cannot starve the ones of another, however many are due.
+//
+// Parameters:
+//   - ctx:  A context to control the lifetime of the retry sequence of the task, including the wait for a slot.
+//   - key:  The key of the task.
+//   - task: The operation to execute.
+//   - opts: Optional configuration options overriding the options of the pool.
+//
+// Returns:
+//   - done: A channel receiving the outcome of the task, as returned by Retry, once it completes.
+//   - err:  ErrPoolClosed if the pool is closed.
+//
+// Example:
+//
+//	done, err := pool.SubmitKeyed(ctx, webhook.Host, deliver)
+func (p *Pool) SubmitKeyed(ctx context.Context, key string, task Operation, opts ...Option) (done <-chan error, err error) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	if p.closed {
+		err = ErrPoolClosed
+
+		return
+	}
+
+	outcome := make(chan error, 1)
+
+	p.tasks.Add(1)
+
+	go func() {
+		defer p.tasks.Done()
+
+		outcome <- Retry(ctx, func() (err error) {
+			if err = p.slots.acquire(ctx, key); err != nil {
+				return
+			}
+
+			defer p.slots.release()
+
+			err = task()
+
+			return
+		}, append(p.opts[:len(p.opts):len(p.opts)], opts...)...)
+	}()
+
+	done = outcome
+
+	return
+}
+
+// Close stops the pool from accepting tasks and waits for the submitted ones to complete.
+func (p *Pool) Close() {
+	p.mutex.Lock()
+
+	p.closed = true
+
+	p.mutex.Unlock()
+
+	p.tasks.Wait()
+}
+
+// slots hands out a fixed number of slots, serving the waiters in round-robin across keys.
+type slots struct {
+	mutex   sync.Mutex
+	free    int
+	keys    []string
+	waiters map[string][]chan struct{}
+}
+
+// newSlots returns slots handing out n slots.
+//
+// Parameters:
+//   - n: The number of slots.
+//
+// Returns:
+//   - s: The new slots.
+func newSlots(n int) (s *slots) {
+	s = &slots{
+		free:    n,
+		waiters: make(map[string][]chan struct{}),
+	}
+
+	return
+}
+
+// acquire waits for a slot, queued under key.
+//
+// Parameters:
+//   - ctx: A context to control the wait.
+//   - key: The key under which to queue.
+//
+// Returns:
+//   - err: The context's error if it is done before a slot is granted.
+func (s *slots) acquire(ctx context.Context, key string) (err error) {
+	s.mutex.Lock()
+
+	if s.free > 0 && len(s.keys) == 0 {
+		s.free--
+
+		s.mutex.Unlock()
+
+		return
+	}
+
+	granted := make(chan struct{}, 1)
+
+	if len(s.waiters[key]) == 0 {
+		s.keys = append(s.keys, key)
+	}
+
+	s.waiters[key] = append(s.waiters[key], granted)
+
+	s.mutex.Unlock()
+
+	select {
+	case <-granted:
+		return
+	case <-ctx.Done():
+		err = ctx.Err()
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	// The slot may have been granted meanwhile, in which case it is handed over to the next waiter.
+	if !s.dequeue(key, granted) {
+		s.grant()
+	}
+
+	return
+}
+
+// release returns a slot, granting it to the next waiter if any.
+func (s *slots) release() {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.grant()
+}
+
+// grant hands a slot over to the first waiter of the next key in round-robin, or frees it if no
+// attempt waits. It must be called with the mutex held.
+func (s *slots) grant() {
+	if len(s.keys) == 0 {
+		s.free++
+
+		return
+	}
+
+	key := s.keys[0]
+	queue := s.waiters[key]
+
+	queue[0] <- struct{}{}
+
+	s.keys = s.keys[1:]
+
+	if queue = queue[1:]; len(queue) > 0 {
+		s.waiters[key] = queue
+		s.keys = append(s.keys, key)
+	} else {
+		delete(s.waiters, key)
+	}
+}
+
+// dequeue removes a waiter from the queue of its key. It must be called with the mutex held.
+//
+// Parameters:
+//   - key:     The key the waiter is queued under.
+//   - granted: The channel of the waiter.
+//
+// Returns:
+//   - found: Whether the waiter was still queued.
+func (s *slots) dequeue(key string, granted chan struct{}) (found bool) {
+	queue := s.waiters[key]
+
+	index := slices.Index(queue, granted)
+	if index < 0 {
+		return
+	}
+
+	found = true
+
+	if queue = slices.Delete(queue, index, index+1); len(queue) > 0 {
+		s.waiters[key] = queue
+
+		return
+	}
+
+	delete(s.waiters, key)
+
+	s.keys = slices.DeleteFunc(s.keys, func(k string) bool { return k == key })
+
+	return
+}
@@ -0,0 +1,110 @@
+package retrier
+
+import (
+	"sync/atomic"
+	"time"
+)
+
+const (
+	// pressureBuckets is the number of one-second buckets of the sliding window of RetryPressure.
+	pressureBuckets = 10
+	// pressureScale is the fixed-point scale of the weights accumulated by the buckets.
+	pressureScale = 1000
+)
+
+// pressureBucket accumulates the attempts started during one second.
+//
+// Fields:
+//   - second:   The Unix time of the second the bucket accumulates.
+//   - attempts: The number of attempts started during the second.
+//   - weight:   The sum of the weights of the retries started during the second, scaled by pressureScale.
+type pressureBucket struct {
+	second   atomic.Int64
+	attempts atomic.Int64
+	weight   atomic.Int64
+}
+
+// pressure is the sliding window of the attempts of every retry sequence of the process.
+var pressure [pressureBuckets]pressureBucket
+
+// recordPressure records the start of an attempt in the sliding window of RetryPressure.
+//
+// Parameters:
+//   - retry:      Whether the attempt is a retry, i.e., not the first attempt of its sequence.
+//   - saturation: The ratio, between 0 and 1, of the delay that preceded the retry to maxDelay.
+func recordPressure(retry bool, saturation float64) {
+	now := time.Now().Unix()
+
+	bucket := &pressure[now%pressureBuckets]
+
+	// Recycle the bucket of a past second. Concurrent attempts may be lost while it is recycled, which
+	// the gauge, being approximate, tolerates.
+	if second := bucket.second.Load(); second != now && bucket.second.CompareAndSwap(second, now) {
+		bucket.attempts.Store(0)
+		bucket.weight.Store(0)
+	}
+
+	bucket.attempts.Add(1)
+
+	if retry {
+		bucket.weight.Add(int64((1 + saturation) / 2 * pressureScale))
+	}
+}
+
+// RetryPressure returns the retry pressure of the process: the ratio of the attempts started during
+// the last 10 seconds, across every retry sequence, that are retries, each weighted by how close the
+// delay that preceded it was to maxDelay. A retry after a delay of minDelay weighs about half, one
+// after a saturated delay weighs one, so that the gauge rises both when many calls need retries and
+// when retry sequences run deep into their schedule. It is a single early-warning signal of dependency
+// trouble, meant to be exported as a gauge by the metrics of the process.
+//
+// Returns:
+//   - ratio: The retry pressure, between 0 (no retries) and 1 (only saturated retries).
+//
+// Example:
+//
+//	gauge.Set(retrier.RetryPressure())
+func RetryPressure() (ratio float64) {
+	now := time.Now().Unix()
+
+	var attempts, weight int64
+
+	for i := range pressure {
+		bucket := &pressure[i]
+
+		if now-bucket.second.Load() >= pressureBuckets {
+			continue
+		}
+
+		attempts += bucket.attempts.Load()
+		weight += bucket.weight.Load()
+	}
+
+	if attempts == 0 {
+		return
+	}
+
+	ratio = min(float64(weight)/pressureScale/float64(attempts), 1)
+
+	return
+}
+
+// saturationOf returns the ratio of a delay to maxDelay, between 0 and 1.
+//
+// Parameters:
+//   - delay:    The delay.
+//   - maxDelay: The maximum delay.
+//
+// Returns:
+//   - saturation: The ratio of delay to maxDelay, or 1 if maxDelay is not positive.
+func saturationOf(delay, maxDelay time.Duration) (saturation float64) {
+	if maxDelay <= 0 {
+		saturation = 1
+
+		return
+	}
+
+	saturation = min(max(float64(delay)/float64(maxDelay), 0), 1)
+
+	return
+}
@@ -0,0 +1,83 @@
+package retrier
+
+import (
+	"time"
+
+	"go.source.hueristiq.com/retrier/backoff"
+)
+
+// ProfileAggressive returns an option bundle retrying quickly and often, suited to cheap, idempotent
+// operations against dependencies that recover fast: 10 attempts, delays from 50ms up to 2s, with
+// exponential backoff and full jitter.
+//
+// Returns:
+//   - Option: A functional option applying the profile's settings.
+//
+// Example:
+//
+//	err := retrier.Retry(ctx, operation, retrier.ProfileAggressive())
+func ProfileAggressive() Option {
+	return Options(
+		WithMaxRetries(10),
+		WithMinDelay(50*time.Millisecond),
+		WithMaxDelay(2*time.Second),
+		WithBackoff(backoff.ExponentialWithFullJitter()),
+	)
+}
+
+// ProfileConservative returns an option bundle retrying few times with long delays, suited to
+// expensive operations or fragile dependencies that should not be pressured: 3 attempts, delays
+// from 1s up to 30s, with exponential backoff and equal jitter.
+//
+// Returns:
+//   - Option: A functional option applying the profile's settings.
+//
+// Example:
+//
+//	err := retrier.Retry(ctx, operation, retrier.ProfileConservative())
+func ProfileConservative() Option {
+	return Options(
+		WithMaxRetries(3),
+		WithMinDelay(time.Second),
+		WithMaxDelay(30*time.Second),
+		WithBackoff(backoff.ExponentialWithEqualJitter()),
+	)
+}
+
+// ProfileInteractive returns an option bundle with a tight budget, suited to user-facing paths where
+// latency matters more than eventual success: 3 attempts, delays from 50ms up to 250ms, with
+// exponential backoff and full jitter, and the whole retry sequence fitted within a 1s SLO.
+//
+// Returns:
+//   - Option: A functional option applying the profile's settings.
+//
+// Example:
+//
+//	err := retrier.Retry(ctx, operation, retrier.ProfileInteractive())
+func ProfileInteractive() Option {
+	return Options(
+		WithMaxRetries(3),
+		WithMinDelay(50*time.Millisecond),
+		WithMaxDelay(250*time.Millisecond),
+		WithBackoff(backoff.ExponentialWithFullJitter()),
+		WithSLO(time.Second),
+	)
+}
+
+// ProfileBatch returns an option bundle favoring eventual success over latency, suited to background
+// and batch jobs: 8 attempts, delays from 1s up to 1m, with exponential backoff and decorrelated jitter.
+//
+// Returns:
+//   - Option: A functional option applying the profile's settings.
+//
+// Example:
+//
+//	err := retrier.Retry(ctx, operation, retrier.ProfileBatch())
+func ProfileBatch() Option {
+	return Options(
+		WithMaxRetries(8),
+		WithMinDelay(time.Second),
+		WithMaxDelay(time.Minute),
+		WithBackoff(backoff.ExponentialWithDecorrelatedJitter()),
+	)
+}
@@ -0,0 +1,83 @@
+package retrier
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// ErrQuorumNotReached is returned by Race when too many operations failed for the quorum to be reached.
+var ErrQuorumNotReached = errors.New("quorum not reached")
+
+// Race runs alternative operations concurrently, e.g., redundant reads of the same data across
+// regions, each retried independently with the provided options, and returns as soon as quorum of
+// them succeeded. The retry sequences of the remaining operations are then cancelled: they stop
+// before their next attempt, while attempts in flight run to completion in the background.
+//
+// As the options are shared by every operation, options keyed on the context, such as WithSupersede
+// or WithNegativeCache, make the operations supersede or short-circuit one another and should not be used.
+//
+// Parameters:
+//   - ctx:        A context to control the lifetime of the race. Cancelling it cancels every retry sequence.
+//   - operations: The alternative operations to run.
+//   - quorum:     The number of operations that must succeed. It is raised to 1 if it is lower.
+//   - opts:       Optional configuration options applied to the retry sequence of every operation.
+//
+// Returns:
+//   - err: nil once quorum operations succeeded, or an error wrapping ErrQuorumNotReached and the
+//     errors of the failed operations if the quorum cannot be reached.
+//
+// Example:
+//
+//	err := retrier.Race(ctx, []retrier.Operation{readEU, readUS, readAP}, 2, retrier.WithMaxRetries(3))
+//	// Returns once two of the three regions answered.
+func Race(ctx context.Context, operations []Operation, quorum int, opts ...Option) (err error) {
+	quorum = max(quorum, 1)
+
+	if quorum > len(operations) {
+		err = fmt.Errorf("%w: quorum %d exceeds the %d operations", ErrQuorumNotReached, quorum, len(operations))
+
+		return
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+
+	defer cancel()
+
+	// The channel is buffered so that the retry sequences still running once Race returns do not block.
+	outcomes := make(chan error, len(operations))
+
+	for _, operation := range operations {
+		go func() {
+			outcomes <- Retry(ctx, operation, opts...)
+		}()
+	}
+
+	var (
+		successes int
+		failures  []error
+	)
+
+	for range operations {
+		outcome := <-outcomes
+		if outcome == nil {
+			successes++
+
+			if successes == quorum {
+				return
+			}
+
+			continue
+		}
+
+		failures = append(failures, outcome)
+
+		if len(operations)-len(failures) < quorum {
+			break
+		}
+	}
+
+	err = fmt.Errorf("%w: %d of %d operations failed: %w", ErrQuorumNotReached, len(failures), len(operations), errors.Join(failures...))
+
+	return
+}
@@ -1,9 +1,19 @@
 package retrier
 
 import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"math"
+	"slices"
+	"sync/atomic"
 	"time"
 
 	"go.source.hueristiq.com/retrier/backoff"
+	"go.source.hueristiq.com/retrier/budget"
+	"go.source.hueristiq.com/retrier/policy"
 )
 
 // Configuration holds the settings for retry operations. These settings determine the behavior
@@ -15,13 +25,297 @@ import (
 //   - minDelay: The minimum delay between retries.
 //   - maxDelay: The maximum allowable delay between retries.
 //   - backoff: A function that calculates the backoff duration based on retry attempt number and delay limits.
-//   - notifier: A callback function that gets triggered on each retry attempt, providing feedback on errors and backoff duration.
+//   - newStrategy: A function creating the stateful backoff strategy of each retry sequence, overriding backoff.
+//   - noJitter: Whether the backoff strategy is stripped of its jitter, for reproducible delays.
+//   - backoffAttemptOffset: The offset added to the zero-based attempt number before it is passed to the backoff strategy.
+//   - delayBoundsResolution: The mode used to resolve a minDelay that is greater than maxDelay.
+//   - negativeDelayResolution: The mode used to resolve a negative delay returned by the backoff strategy.
+//   - middlewares: The middlewares wrapping the execution of every attempt, outermost first.
+//   - notifiers: The callback functions triggered, in registration order, on each retry attempt, providing feedback on errors and backoff duration.
+//   - hooks: The callbacks run at the stages of the lifecycle of every retry sequence, in registration order.
+//   - hookTiming: When the notifiers run relative to the backoff delay.
+//   - fingerprint: The function grouping consecutive identical failures for the notifiers.
+//   - samplingRate: The fraction of retry sequences for which notifications are emitted.
+//   - supersedeKey: A function deriving the key under which a newer retry sequence cancels an older one.
+//   - negativeCacheTTL: The duration for which the permanent failure of a retry sequence is cached.
+//   - negativeCacheKey: A function deriving the key under which the failure of a retry sequence is cached.
+//   - ledger: The Ledger of the compensations of the side effects of the attempts.
+//   - alignTo: The interval the wake times of the retries are aligned to on the wall clock.
+//   - reresolve: The hook re-resolving the endpoint of the operation before a retry following a long delay.
+//   - reresolveAfter: The minimum delay after which reresolve is called.
+//   - budget: The retry budget shared with other retry sequences.
+//   - maxElapsedTime: The wall-clock time after which the retry sequence stops retrying, or 0 for no limit.
+//   - slo: The target duration the whole retry sequence should fit in.
+//   - stats: The Stats populated when the retry sequence ends.
+//   - resultMeta: The ResultMeta populated when the retry sequence ends.
+//   - idempotent: Whether the operation can safely be replayed after an ambiguous failure.
+//   - retryAmbiguous: Whether ambiguous failures are retried, overriding idempotent when set.
+//   - errorRetention: The ErrorRetention mode of the errors of the attempts.
+//   - aggregateErrors: Whether the error of a retry sequence that gives up joins the errors of its attempts.
+//   - recoveryObserver: The callback receiving the time a retry sequence took to recover from its first failure.
+//   - classifier: The Classifier of the outcomes of the attempts.
+//   - retryIf: The predicate deciding whether a failure is retried.
+//   - classifierProvider: The provider of the Classifier of each retry sequence, overriding classifier.
+//   - contextErrors: The treatment of context errors returned by the operation from its own sub-calls.
+//   - softGiveUp: The number of failed attempts after which the caller receives the current error, or 0 to disable soft give-up.
+//   - continuation: The callback receiving the outcome of the retry sequence continued in the background after a soft give-up.
+//   - abandonAfter: The duration after which an attempt that has not returned is abandoned, or 0 to wait for every attempt.
+//   - retryAfterFrom: The function extracting the delay hinted by the error of a failed attempt, tried before RetryAfter.
+//   - serverHints: Whether the delays hinted by the errors of failed attempts replace the backoff delays.
+//   - audit: The audit log the record of every attempt is appended to.
+//   - clock: The source of time of the retry sequence.
+//   - logger: The structured logger of the attempts and outcome of every retry sequence.
+//   - logLevels: The levels at which the logger logs.
+//   - scheduleWarned: Whether the logger was warned that the schedule exceeds its time budget, shared with the copies made through WithConfiguration.
+//   - timeline: The callback receiving the timeline of the attempts of every retry sequence.
+//   - runtimeSnapshot: Whether a snapshot of the runtime is attached to the error of a retry sequence that gives up.
+//   - runtimeTrace: Whether retry sequences and attempts are annotated with runtime/trace tasks and regions.
+//   - strict: Whether configuration problems reported by options are returned as errors.
+//   - problems: The configuration problems reported by options, which ignore the offending values.
 type Configuration struct {
-	maxRetries int
-	minDelay   time.Duration
-	maxDelay   time.Duration
-	backoff    backoff.Backoff
-	notifier   Notifer
+	maxRetries              int
+	minDelay                time.Duration
+	maxDelay                time.Duration
+	backoff                 backoff.Backoff
+	newStrategy             func(minDelay, maxDelay time.Duration) backoff.Strategy
+	noJitter                bool
+	backoffAttemptOffset    int
+	delayBoundsResolution   DelayBoundsResolution
+	negativeDelayResolution NegativeDelayResolution
+	middlewares             []Middleware
+	notifiers               []NotifierV2
+	hooks                   []Hooks
+	hookTiming              HookTiming
+	fingerprint             func(err error) string
+	samplingRate            float64
+	supersedeKey            func(ctx context.Context) string
+	negativeCacheTTL        time.Duration
+	negativeCacheKey        func(ctx context.Context) string
+	ledger                  *Ledger
+	alignTo                 time.Duration
+	reresolve               func(ctx context.Context)
+	reresolveAfter          time.Duration
+	budget                  *budget.Budget
+	maxElapsedTime          time.Duration
+	slo                     time.Duration
+	stats                   *Stats
+	resultMeta              *ResultMeta
+	idempotent              bool
+	retryAmbiguous          *bool
+	errorRetention          ErrorRetention
+	aggregateErrors         bool
+	recoveryObserver        func(recovery time.Duration)
+	classifier              Classifier
+	classifierProvider      func(ctx context.Context) Classifier
+	retryIf                 func(err error) bool
+	contextErrors           ContextErrorTreatment
+	softGiveUp              int
+	continuation            func(result any, err error)
+	abandonAfter            time.Duration
+	retryAfterFrom          func(err error) (delay time.Duration, ok bool)
+	serverHints             bool
+	audit                   *auditLog
+	clock                   Clock
+	logger                  *slog.Logger
+	logLevels               LogLevels
+	scheduleWarned          *atomic.Bool
+	timeline                func(timeline policy.Timeline)
+	runtimeSnapshot         bool
+	runtimeTrace            bool
+	strict                  bool
+	problems                []error
+}
+
+// reject records a configuration problem reported by an option, which ignores the offending value.
+//
+// Parameters:
+//   - option: The name of the option reporting the problem.
+//   - reason: The description of the problem.
+func (c *Configuration) reject(option, reason string) {
+	c.problems = append(c.problems, &OptionError{Option: option, Reason: reason})
+}
+
+// resolve applies the configured resolution mode once, after all options have been applied, to a
+// Configuration whose minDelay is greater than its maxDelay.
+//
+// Returns:
+//   - err: An error wrapping ErrInvalidDelayBounds if the resolution mode is DelayBoundsError and the
+//     delay bounds are inverted, or nil otherwise.
+func (c *Configuration) resolve() (err error) {
+	if c.minDelay <= c.maxDelay {
+		return
+	}
+
+	switch c.delayBoundsResolution {
+	case DelayBoundsClamp:
+		c.minDelay = c.maxDelay
+	case DelayBoundsSwap:
+		c.minDelay, c.maxDelay = c.maxDelay, c.minDelay
+	case DelayBoundsError:
+		err = fmt.Errorf("%w: minDelay %s is greater than maxDelay %s", ErrInvalidDelayBounds, c.minDelay, c.maxDelay)
+	}
+
+	return
+}
+
+// DelayBoundsResolution determines how a Configuration whose minDelay is greater than its maxDelay is
+// resolved when options are materialized.
+type DelayBoundsResolution int
+
+const (
+	// DelayBoundsClamp lowers minDelay to maxDelay, so that every delay is capped at maxDelay. This is the default.
+	DelayBoundsClamp DelayBoundsResolution = iota
+	// DelayBoundsSwap swaps minDelay and maxDelay, treating the inverted bounds as a typo.
+	DelayBoundsSwap
+	// DelayBoundsError rejects the Configuration with an error wrapping ErrInvalidDelayBounds.
+	DelayBoundsError
+)
+
+// NegativeDelayResolution determines how a negative delay returned by the backoff strategy, e.g., a
+// custom one subtracting from its bounds, is resolved before waiting.
+type NegativeDelayResolution int
+
+const (
+	// NegativeDelayZero waits for no delay before the next attempt. This is the default.
+	NegativeDelayZero NegativeDelayResolution = iota
+	// NegativeDelayMinDelay substitutes minDelay for the negative delay.
+	NegativeDelayMinDelay
+	// NegativeDelayError gives up the retry sequence with an error wrapping ErrNegativeDelay and the
+	// error of the last attempt.
+	NegativeDelayError
+)
+
+// ErrNegativeDelay is returned when the backoff strategy returns a negative delay and the
+// NegativeDelayError resolution mode is configured.
+var ErrNegativeDelay = errors.New("negative backoff delay")
+
+// resolveDelay applies the configured resolution mode to a negative delay returned by the backoff strategy.
+//
+// Parameters:
+//   - delay: The delay returned by the backoff strategy.
+//   - cause: The error of the attempt the delay follows.
+//
+// Returns:
+//   - resolved: The delay to wait for, never negative.
+//   - err:      An error wrapping ErrNegativeDelay and cause if the resolution mode is
+//     NegativeDelayError and the delay is negative, or nil otherwise.
+func (c *Configuration) resolveDelay(delay time.Duration, cause error) (resolved time.Duration, err error) {
+	resolved = delay
+
+	if delay >= 0 {
+		return
+	}
+
+	switch c.negativeDelayResolution {
+	case NegativeDelayZero:
+		resolved = 0
+	case NegativeDelayMinDelay:
+		resolved = c.minDelay
+	case NegativeDelayError:
+		err = fmt.Errorf("%w %s: %w", ErrNegativeDelay, delay, cause)
+	}
+
+	return
+}
+
+// ErrInvalidDelayBounds is returned when minDelay is greater than maxDelay and the DelayBoundsError
+// resolution mode is configured.
+var ErrInvalidDelayBounds = errors.New("invalid delay bounds")
+
+// ErrInvalidOption is wrapped by every OptionError.
+var ErrInvalidOption = errors.New("invalid option")
+
+// OptionError describes a configuration problem reported by an option, such as a nil backoff strategy
+// or a negative duration. The option ignores the offending value; the problem is only returned as an
+// error in strict mode, enabled through WithStrict, except for a backoff strategy WithNoJitter cannot
+// strip of its jitter, which is always returned.
+//
+// Fields:
+//   - Option: The name of the option reporting the problem, e.g., "WithBackoff".
+//   - Reason: The description of the problem.
+type OptionError struct {
+	Option string
+	Reason string
+}
+
+// Error implements the error interface.
+//
+// Returns:
+//   - message: The description of the configuration problem.
+func (e *OptionError) Error() (message string) {
+	message = ErrInvalidOption.Error() + ": " + e.Option + ": " + e.Reason
+
+	return
+}
+
+// Unwrap returns ErrInvalidOption, so that errors.Is(err, ErrInvalidOption) reports configuration problems.
+//
+// Returns:
+//   - err: ErrInvalidOption.
+func (e *OptionError) Unwrap() (err error) {
+	err = ErrInvalidOption
+
+	return
+}
+
+// NewValidated materializes the provided options into a Configuration, applying the defaults first and
+// then resolving the delay bounds according to the configured DelayBoundsResolution. The resulting
+// Configuration can be reused across retry operations through WithConfiguration, so validation happens once.
+//
+// Parameters:
+//   - opts: Optional configuration options that can adjust max retries, backoff strategy, or delay intervals.
+//
+// Returns:
+//   - cfg: The materialized Configuration.
+//   - err: An error wrapping ErrInvalidDelayBounds if the options cannot be resolved, the joined
+//     OptionErrors reported by the options in strict mode, the OptionError of WithNoJitter if the
+//     backoff strategy cannot be stripped of its jitter, or nil otherwise.
+//
+// Example:
+//
+//	cfg, err := retrier.NewValidated(retrier.WithMinDelay(time.Second), retrier.WithMaxDelay(time.Millisecond),
+//	    retrier.WithDelayBoundsResolution(retrier.DelayBoundsError))
+//	// err wraps retrier.ErrInvalidDelayBounds.
+func NewValidated(opts ...Option) (cfg *Configuration, err error) {
+	cfg = &Configuration{
+		maxRetries:     3,
+		maxDelay:       1000 * time.Millisecond,
+		minDelay:       100 * time.Millisecond,
+		backoff:        backoff.Exponential(),
+		samplingRate:   1,
+		idempotent:     true,
+		classifier:     Classify,
+		serverHints:    true,
+		logLevels:      defaultLogLevels,
+		scheduleWarned: &atomic.Bool{},
+		clock:          systemClock{},
+	}
+
+	for _, opt := range opts {
+		opt(cfg)
+	}
+
+	if err = cfg.stripJitter(); err != nil {
+		cfg = nil
+
+		return
+	}
+
+	if cfg.strict && len(cfg.problems) > 0 {
+		cfg, err = nil, errors.Join(cfg.problems...)
+
+		return
+	}
+
+	if err = cfg.resolve(); err != nil {
+		cfg = nil
+
+		return
+	}
+
+	cfg.warnSchedule(context.Background())
+
+	return
 }
 
 // Notifer is a callback function type used to handle notifications during retry attempts.
@@ -39,6 +333,247 @@ type Configuration struct {
 //	}
 type Notifer func(err error, backoff time.Duration)
 
+// NotifierV2 is a callback function type used to handle notifications during retry attempts, like
+// Notifer, but receiving the full context of the failed attempt, for structured logging.
+//
+// Parameters:
+//   - info: The failed attempt, its error, the backoff before the next attempt, the time elapsed since
+//     the retry sequence started, and the number of remaining attempts.
+//
+// Example:
+//
+//	func logNotifier(info retrier.AttemptInfo) {
+//	    slog.Warn("retrying", "attempt", info.Attempt, "err", info.Err, "backoff", info.Backoff, "remaining", info.Remaining)
+//	}
+type NotifierV2 func(info AttemptInfo)
+
+// ErrScheduleExceedsBudget is reported by Validate when the nominal schedule of delays of a
+// Configuration cannot fit within its time budget.
+var ErrScheduleExceedsBudget = errors.New("retry schedule exceeds time budget")
+
+// determinismAttempts is the number of attempts of the schedule whose delays stripJitter checks when
+// the retries are unlimited or numerous.
+const determinismAttempts = 64
+
+// stripJitter replaces the backoff strategy with its deterministic counterpart if WithNoJitter is set,
+// through backoff.Unjittered, or backoff.UnjitteredStrategy for a stateful strategy set with
+// WithStrategy, and checks that the resulting strategy no longer draws random delays, sampling the
+// delay of every attempt of the schedule, up to determinismAttempts, scheduleSamples times. As the
+// delays of a strategy that cannot be stripped are not reproducible, it is an error even outside
+// strict mode.
+//
+// Returns:
+//   - err: An *OptionError if the strategy still draws random delays, or nil otherwise.
+func (c *Configuration) stripJitter() (err error) {
+	if !c.noJitter {
+		return
+	}
+
+	attempts := determinismAttempts
+	if c.maxRetries >= 0 {
+		attempts = min(c.maxRetries-1, attempts)
+	}
+
+	// A stateful strategy is stripped per instance, compare the schedules of several of its instances.
+	if newStrategy := c.newStrategy; newStrategy != nil {
+		c.newStrategy = func(minDelay, maxDelay time.Duration) (strategy backoff.Strategy) {
+			strategy, _ = backoff.UnjitteredStrategy(newStrategy(minDelay, maxDelay))
+
+			return
+		}
+
+		delays := make([]time.Duration, max(attempts, 0))
+
+		reference := c.newStrategy(c.minDelay, c.maxDelay)
+
+		for i := range delays {
+			delays[i] = reference.Next()
+		}
+
+		for range scheduleSamples {
+			sample := c.newStrategy(c.minDelay, c.maxDelay)
+
+			for _, delay := range delays {
+				if sample.Next() != delay {
+					err = &OptionError{Option: "WithNoJitter", Reason: "stateful strategy draws random delays"}
+
+					return
+				}
+			}
+		}
+
+		return
+	}
+
+	c.backoff, _ = backoff.Unjittered(c.backoff)
+
+	for attempt := range max(attempts, 0) {
+		delay := c.backoff(c.minDelay, c.maxDelay, attempt+c.backoffAttemptOffset)
+
+		for range scheduleSamples {
+			if c.backoff(c.minDelay, c.maxDelay, attempt+c.backoffAttemptOffset) != delay {
+				err = &OptionError{Option: "WithNoJitter", Reason: fmt.Sprintf("strategy %s draws random delays, use its unjittered base", c.backoff)}
+
+				return
+			}
+		}
+	}
+
+	return
+}
+
+// scheduleSamples is the number of times the backoff strategy is sampled per attempt by Validate, so
+// that jittered strategies whose jitter.Strategy is unknown are judged on their shortest delay rather
+// than a single draw.
+const scheduleSamples = 16
+
+// shortestDelay returns the shortest delay the backoff strategy waits before an attempt.
+//
+// Parameters:
+//   - attempt: The zero-based number of the attempt.
+//
+// Returns:
+//   - shortest: The shortest delay before the attempt.
+func (c *Configuration) shortestDelay(attempt int) (shortest time.Duration) {
+	shortest = c.shortestDelayOf(c.backoff, attempt+c.backoffAttemptOffset)
+
+	return
+}
+
+// shortestDelayOf returns the shortest delay a backoff strategy waits before an attempt. For a
+// jittered strategy whose jitter.Strategy is known through backoff.JitterOf, it is the lower bound of
+// the jitter applied to the shortest delay of its base, as declared by the Bounds of the very jitter
+// strategy it applies, options included. Otherwise the strategy is sampled scheduleSamples times.
+//
+// Parameters:
+//   - b:       The backoff strategy.
+//   - attempt: The attempt number passed to the strategy.
+//
+// Returns:
+//   - shortest: The shortest delay before the attempt.
+func (c *Configuration) shortestDelayOf(b backoff.Backoff, attempt int) (shortest time.Duration) {
+	if base, j, additive, ok := backoff.JitterOf(b); ok {
+		delay := c.shortestDelayOf(base, attempt)
+
+		shortest, _ = j.Bounds(delay)
+
+		if additive {
+			shortest = backoff.SafeAdd(delay, shortest)
+		}
+
+		shortest = min(shortest, c.maxDelay)
+
+		return
+	}
+
+	shortest = time.Duration(math.MaxInt64)
+
+	for range scheduleSamples {
+		shortest = min(shortest, b(c.minDelay, c.maxDelay, attempt))
+	}
+
+	return
+}
+
+// Validate reports configuration choices that are valid but unlikely to behave as intended, such as a
+// schedule of delays that cannot fit within the SLO set through WithSLO, or the maximum elapsed time
+// set through WithMaxElapsedTime, e.g., 5 attempts 30s apart in a 10s budget. The retry sequence then
+// gives up, or has its delays shrunk, well before the configured number of attempts. Unlike
+// NewValidated, Validate does not reject the Configuration. NewValidated logs the same report once, as
+// a warning, to the logger set through WithLogger.
+//
+// Returns:
+//   - err: An error wrapping ErrScheduleExceedsBudget if the shortest schedule of delays between the
+//     attempts exceeds the budget, or nil otherwise.
+//
+// Example:
+//
+//	cfg, _ := retrier.NewValidated(retrier.WithMaxRetries(5), retrier.WithMinDelay(30*time.Second), retrier.WithSLO(10*time.Second))
+//	err := cfg.Validate()
+//	// errors.Is(err, retrier.ErrScheduleExceedsBudget) is true.
+func (c *Configuration) Validate() (err error) {
+	err = c.ValidateContext(context.Background())
+
+	return
+}
+
+// ValidateContext is Validate for a retry sequence run with ctx: the time left until the deadline of
+// ctx, if any, also bounds the budget the schedule of delays must fit in. Retry logs the same report
+// once, as a warning, to the logger set through WithLogger, unless it was already logged for the
+// Configuration.
+//
+// Parameters:
+//   - ctx: The context the retry sequence is run with.
+//
+// Returns:
+//   - err: An error wrapping ErrScheduleExceedsBudget if the shortest schedule of delays between the
+//     attempts exceeds the budget, or nil otherwise.
+//
+// Example:
+//
+//	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+//	defer cancel()
+//
+//	cfg, _ := retrier.NewValidated(retrier.WithMaxRetries(5), retrier.WithMinDelay(30*time.Second))
+//	err := cfg.ValidateContext(ctx)
+//	// errors.Is(err, retrier.ErrScheduleExceedsBudget) is true.
+func (c *Configuration) ValidateContext(ctx context.Context) (err error) {
+	if c.maxRetries < 0 {
+		return
+	}
+
+	name, budget := "", time.Duration(math.MaxInt64)
+
+	if c.slo > 0 {
+		name, budget = "the SLO", c.slo
+	}
+
+	if c.maxElapsedTime > 0 && c.maxElapsedTime < budget {
+		name, budget = "the maximum elapsed time", c.maxElapsedTime
+	}
+
+	if deadline, ok := ctx.Deadline(); ok {
+		if left := max(time.Until(deadline), 0); left < budget {
+			name, budget = "the time left until the context deadline", left
+		}
+	}
+
+	if name == "" {
+		return
+	}
+
+	var schedule time.Duration
+
+	for attempt := range max(c.maxRetries-1, 0) {
+		schedule = backoff.SafeAdd(schedule, max(c.shortestDelay(attempt), 0))
+	}
+
+	if schedule > budget {
+		err = fmt.Errorf("%w: %d attempts wait at least %s, over %s of %s", ErrScheduleExceedsBudget, c.maxRetries, schedule, name, budget)
+	}
+
+	return
+}
+
+// warnSchedule logs, as a warning to the logger set through WithLogger, the report of ValidateContext
+// the first time the schedule of delays of the Configuration, or of any of its copies made through
+// WithConfiguration, exceeds its budget.
+//
+// Parameters:
+//   - ctx: The context the retry sequence is run with, passed to the handler.
+func (c *Configuration) warnSchedule(ctx context.Context) {
+	if c.logger == nil || c.scheduleWarned == nil || c.scheduleWarned.Load() {
+		return
+	}
+
+	err := c.ValidateContext(ctx)
+	if err == nil || !c.scheduleWarned.CompareAndSwap(false, true) {
+		return
+	}
+
+	c.logger.LogAttrs(ctx, slog.LevelWarn, "retry schedule exceeds its time budget", slog.Any("error", err))
+}
+
 // Option is a function type used to modify the Configuration of the retrier. Options allow
 // for the flexible configuration of retry policies by applying user-defined settings.
 //
@@ -49,11 +584,40 @@ type Notifer func(err error, backoff time.Duration)
 //   - Option: A functional option that modifies the Configuration struct, allowing customization of retry behavior.
 type Option func(*Configuration)
 
+// Options composes several options into one, applied in order, so that teams can publish bundles, such
+// as company-wide defaults combining notifiers, middlewares, and policy settings, as a single option
+// value. As options are applied in order, options passed after a bundle override its settings.
+//
+// Parameters:
+//   - opts: The options to compose. Nil options are ignored.
+//
+// Returns:
+//   - Option: A functional option applying every option of opts in order.
+//
+// Example:
+//
+//	func CompanyDefaults() retrier.Option {
+//	    return retrier.Options(retrier.WithNotifier(logNotifier), retrier.WithMaxRetries(5))
+//	}
+//
+//	err := retrier.Retry(ctx, operation, CompanyDefaults(), retrier.WithMaxRetries(2))
+func Options(opts ...Option) Option {
+	return func(c *Configuration) {
+		for _, opt := range opts {
+			if opt != nil {
+				opt(c)
+			}
+		}
+	}
+}
+
 // WithMaxRetries sets the maximum number of retries for the retry mechanism. When the specified
 // number of retries is reached, the operation will stop, and the last error will be returned.
+// A negative number makes the retry sequence unbounded: it retries until the operation succeeds,
+// gives up for another reason, or the context is done.
 //
 // Parameters:
-//   - retries: The maximum number of retry attempts.
+//   - retries: The maximum number of retry attempts, or a negative number for no maximum.
 //
 // Returns:
 //   - Option: A functional option that modifies the Configuration to set the maxRetries field.
@@ -81,6 +645,12 @@ func WithMaxRetries(retries int) Option {
 //	retrier.WithMaxDelay(2 * time.Second) ensures that delays between retries do not exceed 2 seconds.
 func WithMaxDelay(delay time.Duration) Option {
 	return func(c *Configuration) {
+		if delay < 0 {
+			c.reject("WithMaxDelay", "negative delay "+delay.String())
+
+			return
+		}
+
 		c.maxDelay = delay
 	}
 }
@@ -99,6 +669,12 @@ func WithMaxDelay(delay time.Duration) Option {
 //	retrier.WithMinDelay(100 * time.Millisecond) ensures that retries wait at least 100ms before retrying.
 func WithMinDelay(delay time.Duration) Option {
 	return func(c *Configuration) {
+		if delay < 0 {
+			c.reject("WithMinDelay", "negative delay "+delay.String())
+
+			return
+		}
+
 		c.minDelay = delay
 	}
 }
@@ -118,25 +694,1300 @@ func WithMinDelay(delay time.Duration) Option {
 //	retrier.WithBackoff(backoff.ExponentialWithFullJitter()) configures the retrier to use exponential backoff with full jitter.
 func WithBackoff(strategy backoff.Backoff) Option {
 	return func(c *Configuration) {
+		if strategy == nil {
+			c.reject("WithBackoff", "nil backoff strategy")
+
+			return
+		}
+
 		c.backoff = strategy
 	}
 }
 
-// WithNotifier sets a notifier callback function that gets called on each retry attempt. This function
-// allows users to log, monitor, or perform any action upon each retry attempt by providing error details
-// and the duration of the backoff period.
+// WithBackoffAttemptOffset sets the offset added to the zero-based attempt number before it is passed
+// to the backoff strategy. By default the first retry calls the backoff strategy with attempt=0; an offset
+// of 1 makes it start at attempt=1, which preserves the effective schedule of callers migrating from
+// retriers that count attempts from one.
+//
+// Parameters:
+//   - offset: The value added to the attempt number passed to the backoff strategy.
+//
+// Returns:
+//   - Option: A functional option that modifies the Configuration to set the backoffAttemptOffset field.
+//
+// Example:
+//
+//	retrier.WithBackoffAttemptOffset(1) makes the first retry use the backoff computed for attempt 1.
+func WithBackoffAttemptOffset(offset int) Option {
+	return func(c *Configuration) {
+		c.backoffAttemptOffset = offset
+	}
+}
+
+// WithStrategy sets a function creating a stateful backoff strategy for every retry sequence,
+// overriding WithBackoff, so that strategies depending on the delays they actually returned, such as
+// decorrelated jitter, track each retry sequence separately. The strategy returns the delay before
+// every retry in turn, regardless of WithBackoffAttemptOffset.
+//
+// Parameters:
+//   - newStrategy: The function creating the strategy of a retry sequence, given the delay bounds.
+//
+// Returns:
+//   - Option: A functional option that modifies the Configuration to set the newStrategy field.
+//
+// Example:
+//
+//	retrier.WithStrategy(func(minDelay, maxDelay time.Duration) backoff.Strategy {
+//	    return backoff.NewStrategy(backoff.Exponential(), minDelay, maxDelay)
+//	})
+func WithStrategy(newStrategy func(minDelay, maxDelay time.Duration) backoff.Strategy) Option {
+	return func(c *Configuration) {
+		if newStrategy == nil {
+			c.reject("WithStrategy", "nil strategy constructor")
+
+			return
+		}
+
+		c.newStrategy = newStrategy
+	}
+}
+
+// WithNoJitter strips the jitter from the backoff strategy, for regulated environments where retry
+// timing must be reproducible and auditable. The strategy is replaced by its deterministic
+// counterpart, as returned by backoff.Unjittered, which strips the jittered exponential and linear
+// strategies and, recursively, the composed ones, or by backoff.UnjitteredStrategy for a stateful
+// strategy set with WithStrategy. The result is then checked for determinism by sampling, and a
+// strategy still drawing random delays, e.g., a custom one, makes NewValidated, Retry, and
+// RetryWithData return an *OptionError, even without WithStrict.
+//
+// The resulting schedule is exact: the delay before the retry following attempt n, counted from 0, is
+// the strategy's delay for attempt n plus the WithBackoffAttemptOffset, bounded by WithMinDelay and
+// WithMaxDelay as the strategy defines. Only the options deriving delays from the environment, i.e.,
+// WithServerHints, WithAlignTo, and WithSLO, still alter it. policy.ExportSchedule documents it, with
+// Low and High equal to Nominal for every attempt.
+//
+// Returns:
+//   - Option: A functional option that modifies the Configuration to set the noJitter field.
+//
+// Example:
+//
+//	retrier.WithBackoff(backoff.ExponentialWithFullJitter()), retrier.WithNoJitter(), retrier.WithStrict()
+//	// Delays are exactly 100ms, 200ms, 400ms, and so on.
+func WithNoJitter() Option {
+	return func(c *Configuration) {
+		c.noJitter = true
+	}
+}
+
+// WithDelayBoundsResolution sets how a minDelay greater than maxDelay is resolved when options are
+// materialized, instead of letting the inverted bounds reach the backoff strategy.
+//
+// Parameters:
+//   - resolution: The DelayBoundsResolution mode to apply.
+//
+// Returns:
+//   - Option: A functional option that modifies the Configuration to set the delayBoundsResolution field.
+//
+// Example:
+//
+//	retrier.WithDelayBoundsResolution(retrier.DelayBoundsSwap) swaps inverted min and max delays.
+func WithDelayBoundsResolution(resolution DelayBoundsResolution) Option {
+	return func(c *Configuration) {
+		if resolution < DelayBoundsClamp || resolution > DelayBoundsError {
+			c.reject("WithDelayBoundsResolution", fmt.Sprintf("unknown resolution %d", resolution))
+
+			return
+		}
+
+		c.delayBoundsResolution = resolution
+	}
+}
+
+// WithNegativeDelayResolution sets how a negative delay returned by the backoff strategy is resolved.
+// Negative delays are waited as no delay by default.
+//
+// Parameters:
+//   - resolution: The NegativeDelayResolution mode.
+//
+// Returns:
+//   - Option: A functional option that modifies the Configuration to set the negativeDelayResolution field.
+//
+// Example:
+//
+//	retrier.WithNegativeDelayResolution(retrier.NegativeDelayError) surfaces a buggy custom backoff.
+func WithNegativeDelayResolution(resolution NegativeDelayResolution) Option {
+	return func(c *Configuration) {
+		if resolution < NegativeDelayZero || resolution > NegativeDelayError {
+			c.reject("WithNegativeDelayResolution", fmt.Sprintf("unknown resolution %d", resolution))
+
+			return
+		}
+
+		c.negativeDelayResolution = resolution
+	}
+}
+
+// WithConfiguration replaces the Configuration being built with a copy of a previously materialized one,
+// typically obtained from NewValidated. Options applied after it further modify the copy.
 //
 // Parameters:
-//   - notifier: A function of type Notifer that will be called on each retry with the error and backoff duration.
+//   - cfg: The Configuration to copy.
 //
 // Returns:
-//   - Option: A functional option that modifies the Configuration to set the notifier function.
+//   - Option: A functional option that copies cfg into the Configuration.
 //
 // Example:
 //
-//	retrier.WithNotifier(logNotifier) sets up a notifier that logs each retry attempt.
-func WithNotifier(notifier Notifer) Option {
+//	cfg, _ := retrier.NewValidated(retrier.WithMaxRetries(5))
+//	err := retrier.Retry(ctx, operation, retrier.WithConfiguration(cfg))
+func WithConfiguration(cfg *Configuration) Option {
 	return func(c *Configuration) {
-		c.notifier = notifier
+		if cfg == nil {
+			c.reject("WithConfiguration", "nil configuration")
+
+			return
+		}
+
+		*c = *cfg
+
+		c.middlewares = slices.Clone(cfg.middlewares)
+		c.notifiers = slices.Clone(cfg.notifiers)
+		c.hooks = slices.Clone(cfg.hooks)
+		c.problems = slices.Clone(cfg.problems)
+	}
+}
+
+// WithMiddleware appends middlewares wrapping the execution of every attempt. Middlewares run in the
+// order they are added, the first one being the outermost, and share the Attempt's scoped values.
+//
+// Parameters:
+//   - middlewares: The middlewares to append.
+//
+// Returns:
+//   - Option: A functional option that modifies the Configuration to append to the middlewares field.
+//
+// Example:
+//
+//	retrier.WithMiddleware(requestID, logRequestID) lets logRequestID read the ID set by requestID.
+func WithMiddleware(middlewares ...Middleware) Option {
+	return func(c *Configuration) {
+		c.middlewares = append(c.middlewares, middlewares...)
+	}
+}
+
+// WithPolicy applies the settings of a policy.Policy, e.g., one loaded from configuration with
+// policy.FromJSON or policy.FromYAML: its maximum number of attempts, delay bounds, backoff strategy,
+// and maximum elapsed time, if set. Options applied after it further modify the Configuration. A
+// policy that fails policy.Policy.Validate is rejected as a whole, so that a broken configuration file
+// is not half applied. WithPolicy stands in for a Policy.ToOptions method, which the policy package
+// cannot provide without an import cycle, as the retrier imports it.
+//
+// Parameters:
+//   - p: The Policy to apply.
+//
+// Returns:
+//   - Option: A functional option that modifies the Configuration to apply the policy.
+//
+// Example:
+//
+//	p, err := policy.FromYAML(config)
+//	err = retrier.Retry(ctx, operation, retrier.WithPolicy(p), retrier.WithNotifier(logNotifier))
+func WithPolicy(p policy.Policy) Option {
+	return func(c *Configuration) {
+		if err := p.Validate(); err != nil {
+			c.reject("WithPolicy", err.Error())
+
+			return
+		}
+
+		strategy, err := p.BuildBackoff()
+		if err != nil {
+			c.reject("WithPolicy", err.Error())
+
+			return
+		}
+
+		Options(WithMaxRetries(p.MaxRetries), WithMinDelay(p.MinDelay), WithMaxDelay(p.MaxDelay), WithBackoff(strategy))(c)
+
+		if p.MaxElapsedTime > 0 {
+			WithMaxElapsedTime(p.MaxElapsedTime)(c)
+		}
+	}
+}
+
+// WithNotifier registers notifier callback functions that get called on each retry attempt. These
+// functions allow users to log, monitor, or perform any action upon each retry attempt by providing
+// error details and the duration of the backoff period.
+//
+// Notifiers registered by successive calls accumulate, so that metrics, tracing, and logging hooks can
+// coexist, and are called in registration order. A panicking notifier does not stop the retry sequence,
+// nor the notifiers that follow it: the panic is recovered and recorded as a *HookPanicError in
+// Stats.HookFailures.
+//
+// Parameters:
+//   - notifiers: Functions of type Notifer that will be called on each retry with the error and backoff duration.
+//     Nil functions are ignored.
+//
+// Returns:
+//   - Option: A functional option that modifies the Configuration to append the notifier functions.
+//
+// Example:
+//
+//	retrier.WithNotifier(logNotifier, metricsNotifier) logs each retry attempt, then records it.
+func WithNotifier(notifiers ...Notifer) Option {
+	return func(c *Configuration) {
+		for _, notifier := range notifiers {
+			if notifier != nil {
+				c.notifiers = append(c.notifiers, func(info AttemptInfo) {
+					notifier(info.Err, info.Backoff)
+				})
+			}
+		}
+	}
+}
+
+// WithNotifierV2 registers notifier callback functions that get called on each retry attempt with the
+// full context of the failed attempt, its number, error, backoff, the time elapsed since the retry
+// sequence started, and the number of remaining attempts, which the error and backoff passed to the
+// notifiers of WithNotifier lack for structured logging.
+//
+// They accumulate with the notifiers of WithNotifier, are called in registration order among them,
+// and are subject to the same WithHookTiming, WithFingerprint, WithSampling, and panic isolation.
+//
+// Parameters:
+//   - notifiers: Functions of type NotifierV2 that will be called on each retry with the failed attempt.
+//     Nil functions are ignored.
+//
+// Returns:
+//   - Option: A functional option that modifies the Configuration to append the notifier functions.
+//
+// Example:
+//
+//	retrier.WithNotifierV2(func(info retrier.AttemptInfo) {
+//	    slog.Warn("retrying", "attempt", info.Attempt, "elapsed", info.Elapsed, "remaining", info.Remaining)
+//	})
+func WithNotifierV2(notifiers ...NotifierV2) Option {
+	return func(c *Configuration) {
+		for _, notifier := range notifiers {
+			if notifier != nil {
+				c.notifiers = append(c.notifiers, notifier)
+			}
+		}
+	}
+}
+
+// WithHooks registers callbacks run at the stages of the lifecycle of every retry sequence: before
+// each attempt, after each failure, before each backoff delay, and once it succeeds or gives up, so
+// that metrics and cleanup logic can tell sleeping before a retry from giving up. Hooks registered by
+// successive calls accumulate and run in registration order. A panicking callback does not stop the
+// retry sequence: the panic is recovered and recorded as a *HookPanicError in Stats.HookFailures.
+//
+// Parameters:
+//   - hooks: The callbacks of the stages of the lifecycle, any of which may be nil.
+//
+// Returns:
+//   - Option: A functional option that modifies the Configuration to append the hooks.
+//
+// Example:
+//
+//	retrier.WithHooks(retrier.Hooks{
+//	    BeforeSleep: func(_ int, delay time.Duration) { sleeping.Add(1) },
+//	    OnGiveUp:    func(_ int, _ error) { conn.Close() },
+//	})
+func WithHooks(hooks Hooks) Option {
+	return func(c *Configuration) {
+		c.hooks = append(c.hooks, hooks)
+	}
+}
+
+// WithSampling sets the fraction of retry sequences for which notifications are emitted. The sampling
+// decision is made once per retry sequence, so a sampled sequence reports every one of its attempts,
+// keeping the observed data representative while bounding the observability overhead of services
+// performing a very large number of retried calls. The rate is clamped to the [0, 1] range and
+// defaults to 1, i.e., every sequence is observed.
+//
+// Parameters:
+//   - rate: The fraction of retry sequences to observe, between 0 and 1.
+//
+// Returns:
+//   - Option: A functional option that modifies the Configuration to set the samplingRate field.
+//
+// Example:
+//
+//	retrier.WithSampling(0.01) notifies about roughly one retry sequence in a hundred.
+func WithSampling(rate float64) Option {
+	return func(c *Configuration) {
+		if math.IsNaN(rate) {
+			c.reject("WithSampling", "NaN sampling rate")
+
+			return
+		}
+
+		c.samplingRate = min(max(rate, 0), 1)
+	}
+}
+
+// WithSupersede makes a retry sequence cancel any older, still running retry sequence started with
+// the same key, e.g., when a newer user request for the same resource arrives. The older sequence
+// stops before its next attempt, or during its backoff wait, and returns ErrSuperseded. Sequences
+// whose key is empty are never superseded.
+//
+// Parameters:
+//   - key: A function deriving the supersession key from the context passed to Retry.
+//
+// Returns:
+//   - Option: A functional option that modifies the Configuration to set the supersedeKey field.
+//
+// Example:
+//
+//	retrier.WithSupersede(func(ctx context.Context) string { return resourceIDFrom(ctx) })
+//	// A newer retry sequence for the same resource cancels the stale one.
+func WithSupersede(key func(ctx context.Context) string) Option {
+	return func(c *Configuration) {
+		if key == nil {
+			c.reject("WithSupersede", "nil key")
+
+			return
+		}
+
+		c.supersedeKey = key
+	}
+}
+
+// WithNegativeCache caches the failure of a retry sequence that gave up on a permanent failure, so
+// that other callers using the same key within ttl get the cached error right away instead of
+// re-attempting an operation that is known to fail, e.g., a hot key hitting a known-bad record. Only
+// failures marked with Permanent, classified as ClassPermanentFailure by the Classifier, or rejected
+// by the WithRetryIf predicate are cached: running out of attempts, time, or retry budget says nothing
+// about the next caller's chances. Sequences whose key is empty are not cached, and sequences stopped
+// by their context never populate the cache.
+//
+// Parameters:
+//   - ttl: The duration for which the failure is cached.
+//   - key: A function deriving the cache key from the context passed to Retry.
+//
+// Returns:
+//   - Option: A functional option that modifies the Configuration to set the negativeCacheTTL and
+//     negativeCacheKey fields.
+//
+// Example:
+//
+//	retrier.WithNegativeCache(30*time.Second, func(ctx context.Context) string { return recordIDFrom(ctx) })
+//	// Callers for the same record get the cached error for 30 seconds after a sequence gives up.
+func WithNegativeCache(ttl time.Duration, key func(ctx context.Context) string) Option {
+	return func(c *Configuration) {
+		if ttl < 0 || key == nil {
+			c.reject("WithNegativeCache", "negative ttl or nil key")
+
+			return
+		}
+
+		c.negativeCacheTTL = ttl
+		c.negativeCacheKey = key
+	}
+}
+
+// WithMaxElapsedTime limits the total wall-clock time of the retry sequence, across attempts and
+// delays, independently of the number of attempts: a failed attempt is not retried if the next one
+// would start after the limit, and the retry sequence gives up with the last error. Unlike a context
+// deadline, the limit never interrupts an attempt in progress. Unlike WithSLO, delays are not shrunk
+// to fit.
+//
+// Parameters:
+//   - elapsed: The maximum elapsed time of the retry sequence. Zero disables the limit.
+//
+// Returns:
+//   - Option: A functional option that modifies the Configuration to set the maxElapsedTime field.
+//
+// Example:
+//
+//	retrier.WithMaxRetries(-1), retrier.WithMaxElapsedTime(time.Minute) retries for up to a minute.
+func WithMaxElapsedTime(elapsed time.Duration) Option {
+	return func(c *Configuration) {
+		if elapsed < 0 {
+			c.reject("WithMaxElapsedTime", "negative elapsed time "+elapsed.String())
+
+			return
+		}
+
+		c.maxElapsedTime = elapsed
+	}
+}
+
+// WithSLO sets a target duration the whole retry sequence should fit in. The schedule is tuned to the
+// target: delays are shrunk so that the next attempt, estimated from the average duration of previous
+// attempts, can still finish in time, and attempts that cannot finish in time are dropped, giving up
+// with the last error. Whether the target was met is reported through WithStats.
+//
+// Parameters:
+//   - target: The SLO target of the retry sequence.
+//
+// Returns:
+//   - Option: A functional option that modifies the Configuration to set the slo field.
+//
+// Example:
+//
+//	retrier.WithSLO(500 * time.Millisecond) fits the retry sequence within 500ms.
+func WithSLO(target time.Duration) Option {
+	return func(c *Configuration) {
+		if target < 0 {
+			c.reject("WithSLO", "negative target "+target.String())
+
+			return
+		}
+
+		c.slo = target
+	}
+}
+
+// WithStats sets the Stats populated when the retry sequence ends. As the Stats are overwritten by
+// every retry sequence using the option, it should not be shared by concurrent retry sequences.
+//
+// Parameters:
+//   - stats: The Stats to populate.
+//
+// Returns:
+//   - Option: A functional option that modifies the Configuration to set the stats field.
+//
+// Example:
+//
+//	var stats retrier.Stats
+//
+//	err := retrier.Retry(ctx, operation, retrier.WithSLO(time.Second), retrier.WithStats(&stats))
+//	// stats.SLOMet reports whether the operation succeeded within a second.
+func WithStats(stats *Stats) Option {
+	return func(c *Configuration) {
+		if stats == nil {
+			c.reject("WithStats", "nil stats")
+
+			return
+		}
+
+		c.stats = stats
+	}
+}
+
+// WithStrict enables strict mode, in which the configuration problems reported by options, such as a
+// nil backoff strategy or negative durations, make NewValidated, Retry, and RetryWithData return the
+// joined OptionErrors instead of silently ignoring the offending values.
+//
+// Returns:
+//   - Option: A functional option that modifies the Configuration to set the strict field.
+//
+// Example:
+//
+//	err := retrier.Retry(ctx, operation, retrier.WithStrict(), retrier.WithMinDelay(-time.Second))
+//	// errors.Is(err, retrier.ErrInvalidOption) is true.
+func WithStrict() Option {
+	return func(c *Configuration) {
+		c.strict = true
+	}
+}
+
+// WithIdempotent declares whether the operation is idempotent, i.e., whether it can safely be replayed
+// after an ambiguous failure marked with Ambiguous. Operations are considered idempotent by default;
+// retries of non-idempotent operations stop at the first ambiguous failure, which is returned as is,
+// since replaying a write whose outcome is unknown may apply it twice.
+//
+// Parameters:
+//   - idempotent: Whether the operation is idempotent.
+//
+// Returns:
+//   - Option: A functional option that modifies the Configuration to set the idempotent field.
+//
+// Example:
+//
+//	retrier.WithIdempotent(false) stops retrying a payment request after an ambiguous timeout.
+func WithIdempotent(idempotent bool) Option {
+	return func(c *Configuration) {
+		c.idempotent = idempotent
+	}
+}
+
+// WithRetryAmbiguous sets whether failures marked with Ambiguous are retried, overriding the default
+// derived from WithIdempotent. It lets callers opt in to replaying a non-idempotent operation whose
+// outcome is unknown, e.g., when the server deduplicates requests, or opt out for idempotent ones.
+// Failures marked with NotSent are never ambiguous.
+//
+// Parameters:
+//   - retry: Whether ambiguous failures are retried.
+//
+// Returns:
+//   - Option: A functional option that modifies the Configuration to set the retryAmbiguous field.
+//
+// Example:
+//
+//	retrier.WithIdempotent(false), retrier.WithRetryAmbiguous(true) replays a deduplicated write after a timeout.
+func WithRetryAmbiguous(retry bool) Option {
+	return func(c *Configuration) {
+		c.retryAmbiguous = &retry
+	}
+}
+
+// retriesAmbiguous reports whether ambiguous failures are retried.
+//
+// Returns:
+//   - retry: Whether ambiguous failures are retried.
+func (c *Configuration) retriesAmbiguous() (retry bool) {
+	if c.retryAmbiguous != nil {
+		retry = *c.retryAmbiguous
+
+		return
+	}
+
+	retry = c.idempotent
+
+	return
+}
+
+// WithResultMeta sets the ResultMeta populated with the provenance of the result when the retry
+// sequence ends. As the ResultMeta is overwritten by every retry sequence using the option, it should
+// not be shared by concurrent retry sequences.
+//
+// Parameters:
+//   - meta: The ResultMeta to populate.
+//
+// Returns:
+//   - Option: A functional option that modifies the Configuration to set the resultMeta field.
+//
+// Example:
+//
+//	var meta retrier.ResultMeta
+//
+//	result, err := retrier.RetryWithData(ctx, fetchData, retrier.WithResultMeta(&meta))
+//	// meta.Attempt and meta.ProducedAt describe the attempt that produced result.
+func WithResultMeta(meta *ResultMeta) Option {
+	return func(c *Configuration) {
+		if meta == nil {
+			c.reject("WithResultMeta", "nil meta")
+
+			return
+		}
+
+		c.resultMeta = meta
+	}
+}
+
+// WithErrorRetention sets which errors of the attempts are retained, and reported through WithStats.
+// By default, bounded retry sequences retain every error while unbounded ones, which may run forever,
+// retain the last error only, so that long retry loops do not accumulate errors without bound.
+//
+// Parameters:
+//   - retention: The ErrorRetention mode.
+//
+// Returns:
+//   - Option: A functional option that modifies the Configuration to set the errorRetention field.
+//
+// Example:
+//
+//	retrier.WithErrorRetention(retrier.ErrorRetentionSampled) retains a logarithmic sample of the errors.
+func WithErrorRetention(retention ErrorRetention) Option {
+	return func(c *Configuration) {
+		if retention < ErrorRetentionDefault || retention > ErrorRetentionSampled {
+			c.reject("WithErrorRetention", fmt.Sprintf("unknown retention %d", retention))
+
+			return
+		}
+
+		c.errorRetention = retention
+	}
+}
+
+// WithErrorAggregation makes a retry sequence that gives up return an *Error joining the errors of
+// its attempts with errors.Join, and carrying the number of attempts and the time they took, instead
+// of the last error alone, which hides intermittent failures of different kinds. The errors are
+// retained according to WithErrorRetention, which retains every error of a bounded retry sequence by
+// default. errors.Is and errors.As match any of the joined errors.
+//
+// Returns:
+//   - Option: A functional option that modifies the Configuration to set the aggregateErrors field.
+//
+// Example:
+//
+//	err := retrier.Retry(ctx, operation, retrier.WithErrorAggregation())
+//
+//	var retryErr *retrier.Error
+//	if errors.As(err, &retryErr) {
+//	    log.Printf("gave up after %d attempts in %s", retryErr.Attempts, retryErr.Elapsed)
+//	}
+func WithErrorAggregation() Option {
+	return func(c *Configuration) {
+		c.aggregateErrors = true
+	}
+}
+
+// WithRuntimeTrace sets whether retry sequences are annotated for the execution tracer: every retry
+// sequence runs in a runtime/trace task, and every attempt and backoff delay in a region within it, so
+// that `go tool trace` shows where the time of a retry sequence goes between executing and sleeping.
+// The annotations are only emitted while tracing is enabled.
+//
+// Parameters:
+//   - enabled: Whether retry sequences are annotated.
+//
+// Returns:
+//   - Option: A functional option that modifies the Configuration to set the runtimeTrace field.
+//
+// Example:
+//
+//	retrier.WithRuntimeTrace(true) shows the "retrier.attempt" and "retrier.backoff" regions in the trace.
+func WithRuntimeTrace(enabled bool) Option {
+	return func(c *Configuration) {
+		c.runtimeTrace = enabled
+	}
+}
+
+// WithServerHints sets whether the delay a server asked for, carried by the error of a failed attempt
+// implementing RetryAfter() time.Duration, e.g., one derived from a Retry-After header, is trusted over
+// the delay computed by the backoff strategy. Hinted delays are trusted by default, so that throttled
+// clients wait as long as the server asked, and WithServerHints(false) opts out of them. The hinted
+// delay is still fitted within the SLO.
+//
+// Parameters:
+//   - trust: Whether hinted delays replace the backoff delays.
+//
+// Returns:
+//   - Option: A functional option that modifies the Configuration to set the serverHints field.
+//
+// Example:
+//
+//	retrier.WithServerHints(false) ignores the Retry-After delay of a 503 returned as an httpretrier.StatusError.
+func WithServerHints(trust bool) Option {
+	return func(c *Configuration) {
+		c.serverHints = trust
+	}
+}
+
+// WithRetryAfterFrom sets a function extracting the delay a server asked for from the error of a
+// failed attempt, for errors that do not implement RetryAfter() time.Duration, e.g., those of a
+// client library carrying an HTTP 429 Retry-After or a gRPC RetryInfo in its own way. The hinted
+// delays are trusted over the backoff delays, as those of RetryAfter are, unless WithServerHints(false)
+// opts out of them. Errors the function returns no positive delay for fall back to RetryAfter.
+//
+// Parameters:
+//   - extract: The function returning the hinted delay of an error, and whether it carries one.
+//
+// Returns:
+//   - Option: A functional option that modifies the Configuration to set the retryAfterFrom field.
+//
+// Example:
+//
+//	retrier.WithRetryAfterFrom(func(err error) (time.Duration, bool) {
+//	    var apiErr *api.Error
+//	    if errors.As(err, &apiErr) && apiErr.Code == 429 {
+//	        return apiErr.Wait, true
+//	    }
+//
+//	    return 0, false
+//	})
+func WithRetryAfterFrom(extract func(err error) (delay time.Duration, ok bool)) Option {
+	return func(c *Configuration) {
+		if extract == nil {
+			c.reject("WithRetryAfterFrom", "nil function")
+
+			return
+		}
+
+		c.retryAfterFrom = extract
+	}
+}
+
+// hint returns the delay hinted by the error of a failed attempt, through the function set with
+// WithRetryAfterFrom first, then through RetryAfter.
+//
+// Parameters:
+//   - err: The error of the failed attempt.
+//
+// Returns:
+//   - hint: The hinted delay.
+//   - ok:   Whether err carries a positive hint.
+func (c *Configuration) hint(err error) (hint time.Duration, ok bool) {
+	if c.retryAfterFrom != nil {
+		if hint, ok = c.retryAfterFrom(err); ok && hint > 0 {
+			return
+		}
+	}
+
+	hint, ok = serverHint(err)
+
+	return
+}
+
+// WithAbandonAfter sets the duration after which an attempt that has not returned is abandoned, e.g.,
+// an operation stuck on a call ignoring cancellation. An abandoned attempt fails with
+// ErrAttemptAbandoned and the retry sequence continues, while the operation keeps running in a leaked
+// goroutine until it returns; abandoned attempts are counted in Stats and AbandonedAttempts. By
+// default, the retry sequence waits for every attempt, trading latency for safety.
+//
+// As every attempt then runs in a goroutine of its own, operations must be safe for concurrent use
+// with the attempts that follow an abandoned one.
+//
+// Parameters:
+//   - after: The duration after which an attempt is abandoned. 0 waits for every attempt.
+//
+// Returns:
+//   - Option: A functional option that modifies the Configuration to set the abandonAfter field.
+//
+// Example:
+//
+//	retrier.WithAbandonAfter(2 * time.Second) retries a call hanging for more than 2 seconds.
+func WithAbandonAfter(after time.Duration) Option {
+	return func(c *Configuration) {
+		if after < 0 {
+			c.reject("WithAbandonAfter", fmt.Sprintf("negative duration %s", after))
+
+			return
+		}
+
+		c.abandonAfter = after
+	}
+}
+
+// WithSoftGiveUp sets the number of failed attempts after which the caller receives the current error,
+// e.g., in prefetch or cache repair flows where the caller should not wait. If a continuation is
+// provided, the retry sequence continues in the background with its remaining attempts, and the
+// continuation receives its eventual outcome, e.g., to repair the cache once the operation succeeds.
+//
+// The background retry sequence is detached from the cancellation and deadline of the caller's context,
+// which it would otherwise not outlive; it is bounded by the maximum number of attempts only. It does
+// not populate the Stats and ResultMeta of the caller, nor take part in supersession and negative caching.
+//
+// Parameters:
+//   - attempts:     The number of failed attempts after which the caller receives the current error.
+//   - continuation: The callback receiving the result and error of the background retry sequence, or
+//     nil not to continue in the background.
+//
+// Returns:
+//   - Option: A functional option that modifies the Configuration to set the softGiveUp and continuation fields.
+//
+// Example:
+//
+//	retrier.WithSoftGiveUp(1, func(result any, err error) {
+//	    if err == nil {
+//	        cache.Set(key, result)
+//	    }
+//	})
+func WithSoftGiveUp(attempts int, continuation func(result any, err error)) Option {
+	return func(c *Configuration) {
+		if attempts <= 0 {
+			c.reject("WithSoftGiveUp", fmt.Sprintf("non-positive attempts %d", attempts))
+
+			return
+		}
+
+		c.softGiveUp = attempts
+		c.continuation = continuation
+	}
+}
+
+// WithClassifier sets the Classifier of the outcomes of the attempts, whose classes are counted in
+// Stats. A failure classified as ClassPermanentFailure stops the retry sequence, which returns it.
+// Classify is used by default.
+//
+// Parameters:
+//   - classifier: The Classifier of the outcomes of the attempts.
+//
+// Returns:
+//   - Option: A functional option that modifies the Configuration to set the classifier field.
+//
+// Example:
+//
+//	retrier.WithClassifier(func(err error) retrier.Class {
+//	    if errors.Is(err, sql.ErrNoRows) {
+//	        return retrier.ClassPermanentFailure
+//	    }
+//
+//	    return retrier.Classify(err)
+//	})
+func WithClassifier(classifier Classifier) Option {
+	return func(c *Configuration) {
+		if classifier == nil {
+			c.reject("WithClassifier", "nil classifier")
+
+			return
+		}
+
+		c.classifier = classifier
+	}
+}
+
+// WithRetryIf sets a predicate deciding, for every failed attempt, whether it is retried, e.g.,
+// retrying network timeouts but not validation errors. A failure for which the predicate returns false
+// stops the retry sequence, which returns it, and is classified as ClassPermanentFailure.
+//
+// Parameters:
+//   - retryIf: The predicate returning whether the error of a failed attempt is retried.
+//
+// Returns:
+//   - Option: A functional option that modifies the Configuration to set the retryIf field.
+//
+// Example:
+//
+//	retrier.WithRetryIf(func(err error) bool {
+//	    return !errors.Is(err, ErrValidation)
+//	})
+func WithRetryIf(retryIf func(err error) bool) Option {
+	return func(c *Configuration) {
+		if retryIf == nil {
+			c.reject("WithRetryIf", "nil predicate")
+
+			return
+		}
+
+		c.retryIf = retryIf
+	}
+}
+
+// WithDynamicClassifier sets a provider evaluated at the start of every retry sequence, with its
+// context, to select the Classifier of the sequence, so that new retryability rules can be rolled out
+// gradually, e.g., behind a feature flag, without redeploying. A provider returning nil falls back to
+// the Classifier set through WithClassifier, or Classify.
+//
+// Parameters:
+//   - provider: The function returning the Classifier for the context of a retry sequence.
+//
+// Returns:
+//   - Option: A functional option that modifies the Configuration to set the classifierProvider field.
+//
+// Example:
+//
+//	retrier.WithDynamicClassifier(func(ctx context.Context) retrier.Classifier {
+//	    if flags.Bool(ctx, "strict-retries") {
+//	        return strictClassifier
+//	    }
+//
+//	    return nil
+//	})
+func WithDynamicClassifier(provider func(ctx context.Context) Classifier) Option {
+	return func(c *Configuration) {
+		if provider == nil {
+			c.reject("WithDynamicClassifier", "nil provider")
+
+			return
+		}
+
+		c.classifierProvider = provider
+	}
+}
+
+// WithRecoveryObserver sets a callback receiving, when a retry sequence succeeds after failing, the
+// time it took to recover, from the end of its first failed attempt to the end of its successful one.
+//
+// Parameters:
+//   - observer: The callback receiving the recovery time.
+//
+// Returns:
+//   - Option: A functional option that modifies the Configuration to set the recoveryObserver field.
+//
+// Example:
+//
+//	retrier.WithRecoveryObserver(recoveryHistogram.Observe) records how long the dependency takes to recover.
+func WithRecoveryObserver(observer func(recovery time.Duration)) Option {
+	return func(c *Configuration) {
+		if observer == nil {
+			c.reject("WithRecoveryObserver", "nil observer")
+
+			return
+		}
+
+		c.recoveryObserver = observer
+	}
+}
+
+// WithAutoTune sets the experimental backoff.AutoTuner as the backoff strategy, and feeds it with the
+// recovery times of the retry sequences through WithRecoveryObserver.
+//
+// Parameters:
+//   - tuner: The AutoTuner, shared by the retry sequences calling the same dependency.
+//
+// Returns:
+//   - Option: A functional option that modifies the Configuration to set the backoff and recoveryObserver fields.
+//
+// Example:
+//
+//	tuner := backoff.AutoTune()
+//
+//	err := retrier.Retry(ctx, operation, retrier.WithAutoTune(tuner))
+func WithAutoTune(tuner *backoff.AutoTuner) Option {
+	return func(c *Configuration) {
+		if tuner == nil {
+			c.reject("WithAutoTune", "nil tuner")
+
+			return
+		}
+
+		c.backoff = tuner.Backoff()
+		c.recoveryObserver = tuner.Observe
+	}
+}
+
+// WithContextErrors sets how an operation returning context.Canceled or context.DeadlineExceeded from
+// its own sub-calls, e.g., an inner client whose timeout is shorter than the retry sequence, is
+// treated: deferred to the Classifier, which retries it by default, always retried, or never retried.
+// The errors returned once the context of the retry sequence itself is done are not affected.
+//
+// Parameters:
+//   - treatment: The ContextErrorTreatment.
+//
+// Returns:
+//   - Option: A functional option that modifies the Configuration to set the contextErrors field.
+//
+// Example:
+//
+//	retrier.WithContextErrors(retrier.ContextErrorsGiveUp) stops retrying once an inner call is cancelled.
+func WithContextErrors(treatment ContextErrorTreatment) Option {
+	return func(c *Configuration) {
+		if treatment < ContextErrorsClassify || treatment > ContextErrorsGiveUp {
+			c.reject("WithContextErrors", fmt.Sprintf("unknown treatment %d", treatment))
+
+			return
+		}
+
+		c.contextErrors = treatment
+	}
+}
+
+// WithHookTiming sets when the notifiers of a failed attempt run relative to the backoff delay that
+// follows it: inline before it (HookTimingBeforeSleep, the default), inline after it
+// (HookTimingAfterSleep), or in the background (HookTimingAsync), so that slow hooks, such as metrics
+// emission, do not delay the retry sequence.
+//
+// Parameters:
+//   - timing: The HookTiming.
+//
+// Returns:
+//   - Option: A functional option that modifies the Configuration to set the hookTiming field.
+//
+// Example:
+//
+//	retrier.WithHookTiming(retrier.HookTimingAsync) emits metrics without delaying retries.
+func WithHookTiming(timing HookTiming) Option {
+	return func(c *Configuration) {
+		if timing < HookTimingBeforeSleep || timing > HookTimingAsync {
+			c.reject("WithHookTiming", fmt.Sprintf("unknown timing %d", timing))
+
+			return
+		}
+
+		c.hookTiming = timing
+	}
+}
+
+// WithFingerprint groups, for the notifiers, consecutive failures whose errors share the same
+// fingerprint: the first failure of a group is notified as usual, while the ones repeating it are
+// notified once, when the group ends, as a single *RepeatedError counting them. Notifiers can then
+// emit "same error x4" rather than four identical log lines.
+//
+// Parameters:
+//   - fingerprint: The function returning the fingerprint of an error. A nil function stands for Fingerprint.
+//
+// Returns:
+//   - Option: A functional option that modifies the Configuration to set the fingerprint field.
+//
+// Example:
+//
+//	retrier.WithFingerprint(func(err error) string {
+//	    var status *httpretrier.StatusError
+//	    if errors.As(err, &status) {
+//	        return strconv.Itoa(status.StatusCode)
+//	    }
+//
+//	    return retrier.Fingerprint(err)
+//	})
+func WithFingerprint(fingerprint func(err error) string) Option {
+	return func(c *Configuration) {
+		if fingerprint == nil {
+			fingerprint = Fingerprint
+		}
+
+		c.fingerprint = fingerprint
+	}
+}
+
+// WithBudget sets a retry budget shared with other retry sequences, e.g., every call to the same
+// dependency, so that they collectively stop retrying once the budget is exhausted instead of
+// amplifying an outage into a retry storm. First attempts are never refused; a retry sequence whose
+// retry is refused gives up with an error wrapping budget.ErrExhausted and the error of its last attempt.
+//
+// Parameters:
+//   - b: The shared Budget.
+//
+// Returns:
+//   - Option: A functional option that modifies the Configuration to set the budget field.
+//
+// Example:
+//
+//	b := budget.New(0.2, 10*time.Second)
+//	err := retrier.Retry(ctx, operation, retrier.WithBudget(b))
+func WithBudget(b *budget.Budget) Option {
+	return func(c *Configuration) {
+		if b == nil {
+			c.reject("WithBudget", "nil budget")
+
+			return
+		}
+
+		c.budget = b
+	}
+}
+
+// WithAlignTo rounds the wake time of every retry up to the next multiple of an interval on the wall
+// clock, e.g., to 5s boundaries, for downstream batch systems that require predictable load windows.
+// The aligned delay is still subject to WithSLO.
+//
+// Parameters:
+//   - interval: The interval the wake times are aligned to. Zero disables the alignment.
+//
+// Returns:
+//   - Option: A functional option that modifies the Configuration to set the alignTo field.
+//
+// Example:
+//
+//	retrier.WithAlignTo(5*time.Second) retries at :00, :05, :10, and so on.
+func WithAlignTo(interval time.Duration) Option {
+	return func(c *Configuration) {
+		if interval < 0 {
+			c.reject("WithAlignTo", fmt.Sprintf("negative interval %s", interval))
+
+			return
+		}
+
+		c.alignTo = interval
+	}
+}
+
+// WithReresolve sets a hook called before every retry following a backoff delay of at least after, so
+// that integrations can re-resolve DNS or re-select an endpoint, e.g., after a failover during which
+// the cached addresses died. Retries following shorter delays reuse the current endpoint.
+// httpretrier.Reresolve is the built-in hook for HTTP transports.
+//
+// Parameters:
+//   - after:     The minimum delay after which the hook is called. Zero calls it before every delayed retry.
+//   - reresolve: The hook, called with the context of the retry sequence.
+//
+// Returns:
+//   - Option: A functional option that modifies the Configuration to set the reresolve and reresolveAfter fields.
+//
+// Example:
+//
+//	retrier.WithReresolve(10*time.Second, httpretrier.Reresolve(transport))
+func WithReresolve(after time.Duration, reresolve func(ctx context.Context)) Option {
+	return func(c *Configuration) {
+		if reresolve == nil {
+			c.reject("WithReresolve", "nil hook")
+
+			return
+		}
+
+		if after < 0 {
+			c.reject("WithReresolve", fmt.Sprintf("negative delay %s", after))
+
+			return
+		}
+
+		c.reresolve, c.reresolveAfter = reresolve, after
+	}
+}
+
+// WithLedger sets the Ledger the operation records the compensations of its side effects in. When the
+// retry sequence gives up, the compensations are run in reverse order, and the errors of the failed
+// ones are joined to the error of the sequence, wrapping ErrCompensationFailed; when it succeeds, they
+// are discarded. As a Ledger serves a single retry sequence, the option should be set per call.
+//
+// Parameters:
+//   - ledger: The Ledger of the retry sequence.
+//
+// Returns:
+//   - Option: A functional option that modifies the Configuration to set the ledger field.
+//
+// Example:
+//
+//	ledger := new(retrier.Ledger)
+//	err := retrier.Retry(ctx, func() error {
+//	    return book(ctx, ledger)
+//	}, retrier.WithLedger(ledger))
+func WithLedger(ledger *Ledger) Option {
+	return func(c *Configuration) {
+		if ledger == nil {
+			c.reject("WithLedger", "nil ledger")
+
+			return
+		}
+
+		c.ledger = ledger
+	}
+}
+
+// WithRuntimeSnapshot sets whether a lightweight snapshot of the Go runtime (goroutines, heap, and
+// garbage collection pauses) is captured when a retry sequence gives up, and attached to its error as
+// a *SnapshotError, helping tell dependency outages from local resource exhaustion. The snapshot is
+// captured without stopping the world.
+//
+// Parameters:
+//   - enabled: Whether the snapshot is captured.
+//
+// Returns:
+//   - Option: A functional option that modifies the Configuration to set the runtimeSnapshot field.
+//
+// Example:
+//
+//	err := retrier.Retry(ctx, operation, retrier.WithRuntimeSnapshot(true))
+//
+//	var snapshot *retrier.SnapshotError
+//	if errors.As(err, &snapshot) {
+//	    log.Printf("gave up with %d goroutines", snapshot.Snapshot.Goroutines)
+//	}
+func WithRuntimeSnapshot(enabled bool) Option {
+	return func(c *Configuration) {
+		c.runtimeSnapshot = enabled
+	}
+}
+
+// WithSchedule follows an explicit schedule of delays, e.g., [1s, 5s, 30s, 2m], and gives up once it
+// is exhausted: it sets the backoff strategy to backoff.Schedule and the maximum number of attempts to
+// one more than the number of delays.
+//
+// Parameters:
+//   - durations: The schedule of delays between attempts.
+//
+// Returns:
+//   - Option: A functional option that modifies the Configuration to set the backoff and maxRetries fields.
+//
+// Example:
+//
+//	retrier.WithSchedule(time.Second, 5*time.Second, 30*time.Second, 2*time.Minute) makes 5 attempts.
+func WithSchedule(durations ...time.Duration) Option {
+	return Options(
+		WithBackoff(backoff.Schedule(durations...)),
+		WithMaxRetries(len(durations)+1),
+	)
+}
+
+// WithAuditWriter appends an audit record of every attempt to a writer, e.g., an append-only file:
+// when it started, its retry sequence and attempt number, its outcome, and the delay before the next
+// attempt, giving a durable audit trail independent of the logging stack. Every record is written
+// with a single call on its own line, and the writes of concurrent retry sequences are serialized.
+// Write errors are recorded in Stats.HookFailures.
+//
+// Parameters:
+//   - writer: The writer the records are appended to.
+//   - format: The AuditFormat of the records.
+//
+// Returns:
+//   - Option: A functional option that modifies the Configuration to set the audit field.
+//
+// Example:
+//
+//	file, _ := os.OpenFile("retries.jsonl", os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600)
+//	retrier.WithAuditWriter(file, retrier.AuditFormatJSONL)
+func WithAuditWriter(writer io.Writer, format AuditFormat) Option {
+	return func(c *Configuration) {
+		if writer == nil {
+			c.reject("WithAuditWriter", "nil writer")
+
+			return
+		}
+
+		c.audit = &auditLog{writer: writer, format: format}
+	}
+}
+
+// WithClock sets the source of time of the retry sequence: its backoff delays, and its elapsed time as
+// reported by Stats, AttemptInfo, and enforced by WithMaxElapsedTime and WithSLO. Tests can pass the
+// fake clock of the retriertest package to advance time virtually instead of sleeping for real.
+// Defaults to the clock of the time package.
+//
+// Parameters:
+//   - clock: The Clock of the retry sequence.
+//
+// Returns:
+//   - Option: A functional option that modifies the Configuration to set the clock field.
+//
+// Example:
+//
+//	clock := retriertest.NewFakeClock(time.Now())
+//	retrier.WithClock(clock) lets the test call clock.Advance(time.Minute) to end a one-minute delay.
+func WithClock(clock Clock) Option {
+	return func(c *Configuration) {
+		if clock == nil {
+			c.reject("WithClock", "nil clock")
+
+			return
+		}
+
+		c.clock = clock
+	}
+}
+
+// WithLogger logs every retry sequence to a structured logger: each failed attempt followed by a
+// backoff, with its error, backoff, elapsed time, and remaining attempts, and the outcome of the
+// sequence, sparing the same logging notifier in every caller. Records carry the identifier of the
+// retry sequence, are passed the context of the sequence for the handler, and are logged at the
+// levels set through WithLogLevels. Like the notifiers, they are subject to WithSampling.
+//
+// Parameters:
+//   - logger: The logger the retry sequences are logged to.
+//
+// Returns:
+//   - Option: A functional option that modifies the Configuration to set the logger field.
+//
+// Example:
+//
+//	retrier.WithLogger(slog.Default()) logs failed attempts as warnings and exhausted retries as errors.
+func WithLogger(logger *slog.Logger) Option {
+	return func(c *Configuration) {
+		if logger == nil {
+			c.reject("WithLogger", "nil logger")
+
+			return
+		}
+
+		c.logger = logger
+	}
+}
+
+// WithLogLevels sets the levels at which the logger set through WithLogger logs the failed attempts,
+// the retry sequences that succeed, and the ones that give up, which default to slog.LevelWarn,
+// slog.LevelDebug, and slog.LevelError respectively.
+//
+// Parameters:
+//   - levels: The LogLevels of the records.
+//
+// Returns:
+//   - Option: A functional option that modifies the Configuration to set the logLevels field.
+//
+// Example:
+//
+//	retrier.WithLogLevels(retrier.LogLevels{Attempt: slog.LevelDebug, Success: slog.LevelDebug, Failure: slog.LevelWarn})
+func WithLogLevels(levels LogLevels) Option {
+	return func(c *Configuration) {
+		c.logLevels = levels
+	}
+}
+
+// WithTimeline sets a callback receiving, when a retry sequence ends, the timeline of its attempts:
+// when each started, how long it took, and whether it failed. Timelines collected from production can
+// be analyzed with policy.Analyze, which reports wasted sleep and premature retries and suggests
+// tuned delays.
+//
+// Parameters:
+//   - record: The callback receiving the timeline of every retry sequence.
+//
+// Returns:
+//   - Option: A functional option that modifies the Configuration to set the timeline field.
+//
+// Example:
+//
+//	retrier.WithTimeline(func(timeline policy.Timeline) {
+//	    collector.Add(timeline)
+//	})
+func WithTimeline(record func(timeline policy.Timeline)) Option {
+	return func(c *Configuration) {
+		if record == nil {
+			c.reject("WithTimeline", "nil callback")
+
+			return
+		}
+
+		c.timeline = record
 	}
 }
@@ -0,0 +1,175 @@
+package retriertest
+
+import (
+	"sync"
+	"time"
+
+	"go.source.hueristiq.com/retrier"
+)
+
+// FakeClock is a retrier.Clock whose time only passes when Advance is called, so that tests of retry
+// sequences set up with retrier.WithClock run without sleeping for real. It is safe for concurrent
+// use.
+type FakeClock struct {
+	mutex   sync.Mutex
+	changed *sync.Cond
+	now     time.Time
+	timers  []*fakeTimer
+}
+
+// NewFakeClock creates a FakeClock starting at the given time.
+//
+// Parameters:
+//   - now: The initial time of the clock.
+//
+// Returns:
+//   - clock: The FakeClock.
+//
+// Example:
+//
+//	clock := retriertest.NewFakeClock(time.Now())
+//
+//	go func() {
+//	    clock.BlockUntil(1)
+//	    clock.Advance(time.Minute)
+//	}()
+//
+//	err := retrier.Retry(ctx, operation, retrier.WithClock(clock))
+func NewFakeClock(now time.Time) (clock *FakeClock) {
+	clock = &FakeClock{now: now}
+
+	clock.changed = sync.NewCond(&clock.mutex)
+
+	return
+}
+
+// Now returns the current time of the clock.
+//
+// Returns:
+//   - now: The current time of the clock.
+func (c *FakeClock) Now() (now time.Time) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	now = c.now
+
+	return
+}
+
+// Sleep blocks until the clock is advanced by d.
+//
+// Parameters:
+//   - d: The duration to sleep for.
+func (c *FakeClock) Sleep(d time.Duration) {
+	<-c.NewTimer(d).C()
+}
+
+// NewTimer returns a timer firing once the clock is advanced by d, or immediately if d is not positive.
+//
+// Parameters:
+//   - d: The duration after which the timer fires.
+//
+// Returns:
+//   - timer: The timer.
+func (c *FakeClock) NewTimer(d time.Duration) (timer retrier.Timer) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	t := &fakeTimer{clock: c, deadline: c.now.Add(d), c: make(chan time.Time, 1)}
+
+	if d <= 0 {
+		t.c <- c.now
+	} else {
+		c.timers = append(c.timers, t)
+
+		c.changed.Broadcast()
+	}
+
+	timer = t
+
+	return
+}
+
+// Advance moves the time of the clock forward by d, firing the timers that expire by then.
+//
+// Parameters:
+//   - d: The duration to advance the clock by.
+func (c *FakeClock) Advance(d time.Duration) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	c.now = c.now.Add(d)
+
+	pending := c.timers[:0]
+
+	for _, t := range c.timers {
+		if t.deadline.After(c.now) {
+			pending = append(pending, t)
+
+			continue
+		}
+
+		t.c <- c.now
+	}
+
+	clear(c.timers[len(pending):])
+
+	c.timers = pending
+
+	c.changed.Broadcast()
+}
+
+// BlockUntil blocks until at least n timers, including sleeps, are waiting on the clock, e.g., until a
+// retry sequence is waiting for its backoff delay, so that the test can advance the clock past it.
+//
+// Parameters:
+//   - n: The number of waiting timers to wait for.
+func (c *FakeClock) BlockUntil(n int) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	for len(c.timers) < n {
+		c.changed.Wait()
+	}
+}
+
+// fakeTimer is a timer of a FakeClock.
+type fakeTimer struct {
+	clock    *FakeClock
+	deadline time.Time
+	c        chan time.Time
+}
+
+// C returns the channel the fake time is sent on once the clock is advanced past the deadline.
+//
+// Returns:
+//   - c: The channel the time is sent on once the Timer fires.
+func (t *fakeTimer) C() (c <-chan time.Time) {
+	c = t.c
+
+	return
+}
+
+// Stop removes the timer from the timers waiting on the clock, so that advancing the clock no longer
+// fires it.
+//
+// Returns:
+//   - stopped: Whether the Timer was stopped before it fired.
+func (t *fakeTimer) Stop() (stopped bool) {
+	t.clock.mutex.Lock()
+	defer t.clock.mutex.Unlock()
+
+	for i, pending := range t.clock.timers {
+		if pending == t {
+			t.clock.timers = append(t.clock.timers[:i], t.clock.timers[i+1:]...)
+
+			t.clock.changed.Broadcast()
+
+			stopped = true
+
+			return
+		}
+	}
+
+	return
+}
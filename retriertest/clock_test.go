@@ -0,0 +1,154 @@
+package retriertest_test
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.source.hueristiq.com/retrier"
+	"go.source.hueristiq.com/retrier/retriertest"
+)
+
+func TestFakeClock(t *testing.T) {
+	t.Parallel()
+
+	start := time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC)
+	clock := retriertest.NewFakeClock(start)
+
+	assert.Equal(t, start, clock.Now())
+
+	early, late := clock.NewTimer(time.Second), clock.NewTimer(time.Minute)
+
+	clock.Advance(time.Second)
+
+	select {
+	case now := <-early.C():
+		assert.Equal(t, start.Add(time.Second), now)
+	default:
+		t.Fatal("Expected the expired timer to fire")
+	}
+
+	select {
+	case <-late.C():
+		t.Fatal("Expected the pending timer not to fire")
+	default:
+	}
+
+	assert.True(t, late.Stop(), "Expected a pending timer to be stopped")
+	assert.False(t, early.Stop(), "Expected a fired timer not to be stopped")
+
+	select {
+	case <-clock.NewTimer(0).C():
+	default:
+		t.Fatal("Expected a zero timer to fire immediately")
+	}
+}
+
+func TestFakeClock_Retry(t *testing.T) {
+	t.Parallel()
+
+	clock := retriertest.NewFakeClock(time.Now())
+
+	var stats retrier.Stats
+
+	calls := 0
+
+	done := make(chan error, 1)
+
+	go func() {
+		done <- retrier.Retry(context.Background(), func() error {
+			calls++
+
+			if calls < 3 {
+				return errors.New("unavailable")
+			}
+
+			return nil
+		},
+			retrier.WithClock(clock),
+			retrier.WithMinDelay(time.Hour),
+			retrier.WithMaxDelay(time.Hour),
+			retrier.WithStats(&stats))
+	}()
+
+	for range 2 {
+		clock.BlockUntil(1)
+		clock.Advance(time.Hour)
+	}
+
+	require.NoError(t, <-done)
+	assert.Equal(t, 3, calls)
+	assert.Equal(t, 2*time.Hour, stats.TotalDelay, "Expected the delays to pass virtually")
+	assert.Equal(t, 2*time.Hour, stats.Elapsed, "Expected the elapsed time of the clock")
+}
+
+func TestFakeClock_AbandonAfter(t *testing.T) {
+	t.Parallel()
+
+	clock := retriertest.NewFakeClock(time.Now())
+
+	done := make(chan error, 1)
+
+	go func() {
+		done <- retrier.RetryCtx(context.Background(), func(ctx context.Context) error {
+			<-ctx.Done()
+
+			return ctx.Err()
+		},
+			retrier.WithClock(clock),
+			retrier.WithAbandonAfter(time.Hour),
+			retrier.WithMinDelay(time.Hour),
+			retrier.WithMaxDelay(time.Hour),
+			retrier.WithMaxRetries(1))
+	}()
+
+	// The attempt is abandoned after the timeout, then the retry sequence ends after the last delay.
+	for range 2 {
+		clock.BlockUntil(1)
+		clock.Advance(time.Hour)
+	}
+
+	require.ErrorIs(t, <-done, retrier.ErrAttemptAbandoned, "Expected the attempt to be abandoned once the clock passed the timeout")
+}
+
+func TestFakeClock_RetryHedged(t *testing.T) {
+	t.Parallel()
+
+	clock := retriertest.NewFakeClock(time.Now())
+
+	var calls atomic.Int32
+
+	type outcome struct {
+		result string
+		err    error
+	}
+
+	done := make(chan outcome, 1)
+
+	go func() {
+		result, err := retrier.RetryHedged(context.Background(), func(ctx context.Context) (string, error) {
+			if calls.Add(1) == 1 {
+				<-ctx.Done()
+
+				return "", ctx.Err()
+			}
+
+			return "hedged", nil
+		}, time.Hour, retrier.WithClock(clock))
+
+		done <- outcome{result: result, err: err}
+	}()
+
+	clock.BlockUntil(1)
+	clock.Advance(time.Hour)
+
+	o := <-done
+
+	require.NoError(t, o.err)
+	assert.Equal(t, "hedged", o.result, "Expected the speculative call to be launched once the clock passed the hedging delay")
+	assert.Equal(t, int32(2), calls.Load())
+}
@@ -0,0 +1,10 @@
+// Package retriertest provides utilities for testing retry setups against realistic failure modes.
+//
+// FlakyServer is an httptest-based server that fails a configurable number of requests before
+// succeeding. Failing requests can return an error status, a 429 Too Many Requests response carrying
+// a Retry-After header, have their connection dropped, or be delayed, so that the retry setup of an
+// HTTP client, including its timeouts, can be exercised end to end in integration tests.
+//
+// FakeClock is a retrier.Clock whose time only passes when the test advances it, so that retry
+// sequences set up with retrier.WithClock wait for their backoff delays virtually instead of for real.
+package retriertest
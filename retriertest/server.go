@@ -0,0 +1,210 @@
+package retriertest
+
+import (
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// FlakyServer is an httptest.Server failing the first requests it receives, then succeeding. It is
+// safe for concurrent use.
+//
+// Fields:
+//   - Server: The underlying httptest.Server; its URL field is the address to send requests to.
+type FlakyServer struct {
+	*httptest.Server
+
+	mutex      sync.Mutex
+	failures   int
+	status     int
+	retryAfter time.Duration
+	drop       bool
+	delay      time.Duration
+	handler    http.Handler
+	requests   int
+}
+
+// Option is a function type that modifies a FlakyServer. It is used to customize the failures of the
+// server.
+type Option func(server *FlakyServer)
+
+// NewFlakyServer starts a FlakyServer, which by default fails the first request with 503 Service
+// Unavailable and responds 200 OK afterwards. The server should be closed once the test ends.
+//
+// Parameters:
+//   - opts: Optional options customizing the failures of the server.
+//
+// Returns:
+//   - server: The started FlakyServer.
+//
+// Example:
+//
+//	server := retriertest.NewFlakyServer(retriertest.WithFailures(2), retriertest.WithRetryAfter(time.Second))
+//	defer server.Close()
+//	// The first two requests to server.URL are answered 429 with "Retry-After: 1", the third 200.
+func NewFlakyServer(opts ...Option) (server *FlakyServer) {
+	server = &FlakyServer{
+		failures: 1,
+		status:   http.StatusServiceUnavailable,
+		handler: http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			w.WriteHeader(http.StatusOK)
+		}),
+	}
+
+	for _, opt := range opts {
+		opt(server)
+	}
+
+	server.Server = httptest.NewServer(http.HandlerFunc(server.serve))
+
+	return
+}
+
+// Requests returns the number of requests received by the server so far.
+//
+// Returns:
+//   - requests: The number of requests received.
+func (s *FlakyServer) Requests() (requests int) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	requests = s.requests
+
+	return
+}
+
+// serve fails the request if the server has not failed enough requests yet, or passes it to the
+// success handler otherwise.
+func (s *FlakyServer) serve(w http.ResponseWriter, r *http.Request) {
+	s.mutex.Lock()
+
+	s.requests++
+
+	failing := s.requests <= s.failures
+
+	s.mutex.Unlock()
+
+	if !failing {
+		s.handler.ServeHTTP(w, r)
+
+		return
+	}
+
+	if s.delay > 0 {
+		timer := time.NewTimer(s.delay)
+
+		select {
+		case <-timer.C:
+		case <-r.Context().Done():
+			timer.Stop()
+
+			return
+		}
+	}
+
+	if s.drop {
+		if hijacker, ok := w.(http.Hijacker); ok {
+			if conn, _, err := hijacker.Hijack(); err == nil {
+				_ = conn.Close()
+
+				return
+			}
+		}
+
+		panic(http.ErrAbortHandler)
+	}
+
+	if s.retryAfter > 0 {
+		w.Header().Set("Retry-After", strconv.Itoa(int((s.retryAfter+time.Second-1)/time.Second)))
+	}
+
+	w.WriteHeader(s.status)
+}
+
+// WithFailures sets the number of requests the server fails before succeeding.
+//
+// Parameters:
+//   - failures: The number of failing requests. A negative number fails every request.
+//
+// Returns:
+//   - Option: An option setting the number of failing requests.
+func WithFailures(failures int) Option {
+	return func(s *FlakyServer) {
+		if failures < 0 {
+			failures = math.MaxInt
+		}
+
+		s.failures = failures
+	}
+}
+
+// WithStatus sets the status code of failing responses, 503 Service Unavailable by default.
+//
+// Parameters:
+//   - status: The HTTP status code of failing responses.
+//
+// Returns:
+//   - Option: An option setting the status code of failing responses.
+func WithStatus(status int) Option {
+	return func(s *FlakyServer) {
+		s.status = status
+	}
+}
+
+// WithRetryAfter makes failing responses 429 Too Many Requests, carrying a Retry-After header with the
+// given delay, rounded up to the second.
+//
+// Parameters:
+//   - delay: The delay advertised in the Retry-After header.
+//
+// Returns:
+//   - Option: An option making failing responses advertise a Retry-After delay.
+func WithRetryAfter(delay time.Duration) Option {
+	return func(s *FlakyServer) {
+		s.status = http.StatusTooManyRequests
+		s.retryAfter = delay
+	}
+}
+
+// WithDroppedConnections makes the server close the connection of failing requests without
+// responding, as a crashing or restarting backend would.
+//
+// Returns:
+//   - Option: An option dropping the connection of failing requests.
+func WithDroppedConnections() Option {
+	return func(s *FlakyServer) {
+		s.drop = true
+	}
+}
+
+// WithDelay delays failing responses, so that clients whose timeout is shorter observe a timeout.
+//
+// Parameters:
+//   - delay: The delay before failing requests are answered.
+//
+// Returns:
+//   - Option: An option delaying failing responses.
+func WithDelay(delay time.Duration) Option {
+	return func(s *FlakyServer) {
+		s.delay = delay
+	}
+}
+
+// WithHandler sets the handler serving requests once the server stops failing, which responds 200 OK
+// by default.
+//
+// Parameters:
+//   - handler: The handler serving successful requests.
+//
+// Returns:
+//   - Option: An option setting the handler of successful requests.
+func WithHandler(handler http.Handler) Option {
+	return func(s *FlakyServer) {
+		if handler != nil {
+			s.handler = handler
+		}
+	}
+}
@@ -0,0 +1,101 @@
+package retriertest_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.source.hueristiq.com/retrier"
+	"go.source.hueristiq.com/retrier/retriertest"
+)
+
+var errUnexpectedStatus = errors.New("unexpected status")
+
+func get(ctx context.Context, client *http.Client, url string) (status int, retryAfter string, err error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, http.NoBody)
+	if err != nil {
+		return
+	}
+
+	res, err := client.Do(req)
+	if err != nil {
+		return
+	}
+
+	defer res.Body.Close()
+
+	status, retryAfter = res.StatusCode, res.Header.Get("Retry-After")
+
+	return
+}
+
+func TestFlakyServer_Status(t *testing.T) {
+	t.Parallel()
+
+	server := retriertest.NewFlakyServer(retriertest.WithFailures(2), retriertest.WithStatus(http.StatusBadGateway))
+	defer server.Close()
+
+	expected := []int{http.StatusBadGateway, http.StatusBadGateway, http.StatusOK}
+
+	for i, want := range expected {
+		status, _, err := get(context.Background(), server.Client(), server.URL)
+
+		require.NoError(t, err, "Expected request %d to be answered", i)
+		assert.Equal(t, want, status, "Unexpected status of request %d", i)
+	}
+
+	assert.Equal(t, 3, server.Requests(), "Expected every request to be counted")
+}
+
+func TestFlakyServer_RetryAfter(t *testing.T) {
+	t.Parallel()
+
+	server := retriertest.NewFlakyServer(retriertest.WithRetryAfter(1500 * time.Millisecond))
+	defer server.Close()
+
+	status, retryAfter, err := get(context.Background(), server.Client(), server.URL)
+
+	require.NoError(t, err, "Expected the request to be answered")
+	assert.Equal(t, http.StatusTooManyRequests, status, "Expected a 429 response")
+	assert.Equal(t, "2", retryAfter, "Expected the Retry-After delay to be rounded up to the second")
+}
+
+func TestFlakyServer_DroppedConnections(t *testing.T) {
+	t.Parallel()
+
+	server := retriertest.NewFlakyServer(retriertest.WithDroppedConnections())
+	defer server.Close()
+
+	_, _, err := get(context.Background(), server.Client(), server.URL)
+
+	require.Error(t, err, "Expected the connection to be dropped")
+}
+
+func TestFlakyServer_Delay(t *testing.T) {
+	t.Parallel()
+
+	server := retriertest.NewFlakyServer(retriertest.WithFailures(1), retriertest.WithDelay(200*time.Millisecond))
+	defer server.Close()
+
+	client := server.Client()
+	client.Timeout = 20 * time.Millisecond
+
+	err := retrier.Retry(context.Background(), func() error {
+		status, _, err := get(context.Background(), client, server.URL)
+		if err == nil && status != http.StatusOK {
+			err = errUnexpectedStatus
+		}
+
+		return err
+	},
+		retrier.WithMaxRetries(3),
+		retrier.WithMinDelay(time.Millisecond),
+		retrier.WithMaxDelay(time.Millisecond))
+
+	require.NoError(t, err, "Expected the retried request to succeed after the timeout")
+	assert.Equal(t, 2, server.Requests(), "Expected the timed out request to be retried once")
+}
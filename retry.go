@@ -2,9 +2,17 @@ package retrier
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"runtime"
+	"runtime/trace"
+	"strconv"
 	"time"
 
 	"go.source.hueristiq.com/retrier/backoff"
+	"go.source.hueristiq.com/retrier/budget"
+	"go.source.hueristiq.com/retrier/policy"
 )
 
 // Operation is a function type that represents an operation that can be retried.
@@ -31,6 +39,40 @@ func (o Operation) withEmptyData() (operationWithData OperationWithData[struct{}
 // that may return results along with a possible error.
 type OperationWithData[T any] func() (data T, err error)
 
+// withContext wraps an OperationWithData function to convert it into an OperationWithContextAndData
+// ignoring the context it is called with.
+//
+// Returns:
+//   - operationWithContext: An OperationWithContextAndData function calling o.
+func (o OperationWithData[T]) withContext() (operationWithContext OperationWithContextAndData[T]) {
+	operationWithContext = func(context.Context) (T, error) {
+		return o()
+	}
+
+	return
+}
+
+// OperationWithContext is a function type that represents an operation that can be retried, called
+// with the context of its attempt, so that it can honor cancellation natively.
+type OperationWithContext func(ctx context.Context) (err error)
+
+// withEmptyData wraps an OperationWithContext function to convert it into an
+// OperationWithContextAndData that returns an empty struct.
+//
+// Returns:
+//   - operationWithData: An OperationWithContextAndData function that returns an empty struct and error.
+func (o OperationWithContext) withEmptyData() (operationWithData OperationWithContextAndData[struct{}]) {
+	operationWithData = func(ctx context.Context) (struct{}, error) {
+		return struct{}{}, o(ctx)
+	}
+
+	return
+}
+
+// OperationWithContextAndData is a function type that represents an operation that returns data along
+// with an error, called with the context of its attempt.
+type OperationWithContextAndData[T any] func(ctx context.Context) (data T, err error)
+
 // Retry attempts to execute the provided operation with a retry mechanism, using the provided options.
 // If the operation continues to fail, it will retry based on the configuration, which may include max retries,
 // backoff strategies, and min/max delay between retries.
@@ -66,64 +108,796 @@ func Retry(ctx context.Context, operation Operation, opts ...Option) (err error)
 //
 // Returns:
 //   - result: The result of the operation if it succeeds within the allowed retry attempts.
-//   - err: The error returned by the last failed attempt, or the context's error if the operation is canceled.
+//   - err: The error returned by the last failed attempt, the context's error if the operation is canceled,
+//     ErrSuperseded if a newer retry sequence superseded it, the failure cached by WithNegativeCache, or the
+//     configuration error if the options cannot be resolved.
 //
 // Example:
 //
 //	result, err := retrier.RetryWithData(ctx, fetchData, retrier.WithMaxRetries(5), retrier.WithBackoff(backoff.Exponential()))
 //	// Retries 'fetchData' up to 5 times with exponential backoff.
 func RetryWithData[T any](ctx context.Context, operation OperationWithData[T], opts ...Option) (result T, err error) {
-	cfg := &Configuration{
-		maxRetries: 3,
-		maxDelay:   1000 * time.Millisecond,
-		minDelay:   100 * time.Millisecond,
-		backoff:    backoff.Exponential(),
+	cfg, err := NewValidated(opts...)
+	if err != nil {
+		return
+	}
+
+	result, err = retry(ctx, cfg, operation.withContext(), false)
+
+	return
+}
+
+// RetryCtx is Retry for an operation taking a context. The operation is called with the context of
+// its attempt, derived from ctx, which is canceled once the retry sequence is superseded through
+// WithSupersede, or once the attempt is abandoned through WithAbandonAfter, so that the operation
+// stops working on behalf of an attempt whose outcome is no longer awaited.
+//
+// Parameters:
+//   - ctx:       A context to control the lifetime of the retry operation.
+//   - operation: The operation to be retried.
+//   - opts:      Optional configuration options.
+//
+// Returns:
+//   - err: The error of the retry sequence, as returned by Retry.
+//
+// Example:
+//
+//	err := retrier.RetryCtx(ctx, func(ctx context.Context) error {
+//	    return client.Ping(ctx)
+//	}, retrier.WithMaxRetries(5))
+func RetryCtx(ctx context.Context, operation OperationWithContext, opts ...Option) (err error) {
+	_, err = RetryCtxWithData(ctx, operation.withEmptyData(), opts...)
+
+	return
+}
+
+// RetryCtxWithData is RetryWithData for an operation taking a context. The operation is called with
+// the context of its attempt, as described by RetryCtx.
+//
+// Parameters:
+//   - ctx:       A context to control the lifetime of the retry operation.
+//   - operation: The operation to be retried, which returns a value of type T and an error.
+//   - opts:      Optional configuration options.
+//
+// Returns:
+//   - result: The result of the operation if it succeeds within the allowed retry attempts.
+//   - err:    The error of the retry sequence, as returned by RetryWithData.
+//
+// Example:
+//
+//	user, err := retrier.RetryCtxWithData(ctx, func(ctx context.Context) (User, error) {
+//	    return client.GetUser(ctx, id)
+//	}, retrier.WithMaxRetries(5))
+func RetryCtxWithData[T any](ctx context.Context, operation OperationWithContextAndData[T], opts ...Option) (result T, err error) {
+	cfg, err := NewValidated(opts...)
+	if err != nil {
+		return
+	}
+
+	result, err = retry(ctx, cfg, operation, true)
+
+	return
+}
+
+// retry runs the retry sequence of an operation with a resolved Configuration, which it may adjust
+// for this sequence alone and must therefore not be shared.
+//
+// Parameters:
+//   - ctx:          A context to control the lifetime of the retry sequence.
+//   - cfg:          The resolved Configuration of the retry sequence.
+//   - operation:    The operation to be retried.
+//   - contextAware: Whether the operation reads its context, which then carries the AttemptMeta of
+//     every attempt.
+//
+// Returns:
+//   - result: The result of the operation if it succeeds within the allowed retry attempts.
+//   - err:    The error of the retry sequence, as returned by RetryWithData.
+func retry[T any](ctx context.Context, cfg *Configuration, operation OperationWithContextAndData[T], contextAware bool) (result T, err error) {
+	// Select the Classifier of this retry sequence, if provided dynamically.
+	if cfg.classifierProvider != nil {
+		if classifier := cfg.classifierProvider(ctx); classifier != nil {
+			cfg.classifier = classifier
+		}
+	}
+
+	// A request marked as not to be retried gets a single attempt.
+	if NoRetry(ctx) && (cfg.maxRetries < 0 || cfg.maxRetries > 1) {
+		cfg.maxRetries = 1
+	}
+
+	// Warn once that the schedule cannot fit before the deadline of ctx, before the stateful backoff
+	// strategy, which the check would advance, replaces the backoff strategy.
+	cfg.warnSchedule(ctx)
+
+	// Create the stateful backoff strategy of this retry sequence, if configured.
+	if cfg.newStrategy != nil {
+		cfg.backoff = backoff.FromStrategy(cfg.newStrategy(cfg.minDelay, cfg.maxDelay))
+	}
+
+	// Return the failure cached by a retry sequence with the same key, if any, without executing the operation.
+	negativeKey := negativeCacheKey(ctx, cfg)
+
+	if negativeKey != "" {
+		if err = lookupNegative(negativeKey, cfg.clock.Now()); err != nil {
+			return
+		}
+	}
+
+	if cfg.supersedeKey != nil {
+		var release func()
+
+		ctx, release = supersede(ctx, cfg.supersedeKey)
+
+		defer release()
+	}
+
+	sequenceID := newSequenceID()
+
+	// Annotate the retry sequence for the execution tracer, if enabled.
+	if cfg.runtimeTrace && trace.IsEnabled() {
+		var task *trace.Task
+
+		ctx, task = trace.NewTask(ctx, "retrier.Retry")
+
+		defer task.End()
+
+		trace.Log(ctx, "sequence", sequenceID)
+	}
+
+	if cfg.resultMeta != nil {
+		*cfg.resultMeta = ResultMeta{}
+	}
+
+	var (
+		stats      = Stats{SequenceID: sequenceID, SLO: cfg.slo}
+		start      = cfg.clock.Now()
+		attempting time.Duration
+		retainer   = newErrorRetainer(cfg.errorRetention, cfg.maxRetries >= 0)
+		pacing     = pacer{clock: cfg.clock}
+		saturation float64
+		failedAt   time.Time
+		exhausted  bool
+		permanent  bool
+	)
+
+	// Decide once whether this retry sequence is observed, so sampled sequences are reported in full.
+	sampled := cfg.samplingRate >= 1 || rand.Float64() < cfg.samplingRate //nolint:gosec // Sampling does not need a cryptographically secure source.
+
+	hooks := dispatcher{timing: cfg.hookTiming, fingerprint: cfg.fingerprint, lifecycle: cfg.hooks}
+
+	if sampled {
+		hooks.notifiers = cfg.notifiers
+	}
+
+	if cfg.stats != nil {
+		defer func() {
+			stats.HookFailures = hooks.failures
+			stats.Elapsed = cfg.clock.Now().Sub(start)
+			stats.SLOMet = cfg.slo > 0 && err == nil && stats.Elapsed <= cfg.slo
+			stats.Errors = retainer.retainedErrors()
+
+			*cfg.stats = stats
+		}()
+	}
+
+	// Record the timeline of the attempts of the retry sequence, if configured.
+	var timeline policy.Timeline
+
+	if cfg.timeline != nil {
+		defer func() {
+			cfg.timeline(timeline)
+		}()
+	}
+
+	// Write the audit record of the last attempt, whose delay is known once it is retried, or once the
+	// retry sequence ends.
+	var audited *AuditRecord
+
+	if cfg.audit != nil {
+		defer func() {
+			if audited != nil {
+				if failure := cfg.audit.write(*audited); failure != nil {
+					hooks.failures = append(hooks.failures, failure)
+				}
+			}
+		}()
+	}
+
+	// Log the outcome of the retry sequence once its error is final, if configured.
+	if cfg.logger != nil && sampled {
+		defer func() {
+			logOutcome(ctx, cfg.logger, cfg.logLevels, sequenceID, stats.Attempts, cfg.clock.Now().Sub(start), err)
+		}()
 	}
 
-	for _, opt := range opts {
-		opt(cfg)
+	// Attach a snapshot of the runtime to the error of a retry sequence that gives up, if configured.
+	if cfg.runtimeSnapshot {
+		defer func() {
+			if err != nil {
+				err = &SnapshotError{Err: err, Snapshot: captureRuntime()}
+			}
+		}()
+	}
+
+	// Whether the retry sequence was handed over to the background by a soft give-up.
+	var handedOver bool
+
+	// Run the hooks of the end of the retry sequence once its error is final, if configured.
+	if len(cfg.hooks) > 0 {
+		defer func() {
+			if handedOver {
+				return
+			}
+
+			hooks.run(func(h Hooks) {
+				switch {
+				case err == nil && h.OnSuccess != nil:
+					h.OnSuccess(stats.Attempts)
+				case err != nil && h.OnGiveUp != nil:
+					h.OnGiveUp(stats.Attempts, err)
+				}
+			})
+		}()
+	}
+
+	// Compensate the side effects recorded in the ledger if the retry sequence gives up, or forget them
+	// if it succeeds. A sequence handed over to the background leaves them to its continuation.
+	if cfg.ledger != nil {
+		defer func() {
+			if err == nil {
+				cfg.ledger.discard()
+
+				return
+			}
+
+			if handedOver {
+				return
+			}
+
+			if failure := cfg.ledger.compensate(); failure != nil {
+				err = errors.Join(err, failure)
+			}
+		}()
+	}
+
+	// Join the errors of the attempts of a retry sequence that gives up, if configured.
+	if cfg.aggregateErrors {
+		defer func() {
+			if err != nil && stats.Attempts > 0 {
+				err = aggregate(err, retainer.retainedErrors(), stats.Attempts, cfg.clock.Now().Sub(start), sequenceID)
+			}
+		}()
 	}
 
-	for attempt := range cfg.maxRetries {
+	// Build the middleware chain once, reusing the same Attempt for every attempt of the sequence.
+	current := acquireAttempt()
+
+	current.SequenceID = sequenceID
+
+	defer releaseAttempt(current)
+
+	// The context of every attempt carries its AttemptMeta, if the operation reads it.
+	var attemptCtx context.Context
+
+	operations := newChain(current, cfg.middlewares, func() (T, error) {
+		return operation(attemptCtx)
+	})
+
+	// Deliver the notifications still held once the retry sequence ends, before the stats are filled.
+	defer hooks.close()
+
+	if cfg.budget != nil {
+		cfg.budget.Request()
+	}
+
+retrying:
+	for attempt := 0; cfg.maxRetries < 0 || attempt < cfg.maxRetries; attempt++ {
+		// Deliver the notifications deferred past the delay, before the next attempt.
+		hooks.flush()
+
 		select {
 		case <-ctx.Done():
 			// If the context is done, return the context's error.
-			err = ctx.Err()
+			err = contextError(ctx)
 
 			return
 		default:
-			// Execute the operation and check for success.
-			result, err = operation()
+			if len(cfg.hooks) > 0 {
+				hooks.run(func(h Hooks) {
+					if h.BeforeAttempt != nil {
+						h.BeforeAttempt(attempt)
+					}
+				})
+			}
+
+			// Execute the operation, wrapped by the middlewares, and check for success.
+			recordPressure(attempt > 0, saturation)
+
+			started := cfg.clock.Now()
+
+			var called bool
+
+			endRegion := startRegion(ctx, cfg.runtimeTrace, "retrier.attempt", attempt)
+
+			attemptCtx = ctx
+
+			// Until overwritten by this attempt, err holds the error of the previous one.
+			if contextAware {
+				attemptCtx = context.WithValue(ctx, attemptKey{}, AttemptMeta{Number: attempt, PreviousErr: err, Elapsed: cfg.clock.Now().Sub(start)})
+			}
+
+			if cfg.abandonAfter > 0 {
+				var abandoned bool
+
+				if result, called, abandoned, err = executeWithin(attemptCtx, cfg.clock, cfg.abandonAfter, cfg.middlewares, operation, sequenceID, attempt); abandoned {
+					stats.Abandoned++
+				}
+			} else {
+				result, called, err = operations.execute(attempt)
+			}
+
+			endRegion()
+
+			attempting += cfg.clock.Now().Sub(started)
+			stats.Attempts++
+
+			if cfg.timeline != nil {
+				timeline = append(timeline, policy.AttemptRecord{Start: started, Duration: cfg.clock.Now().Sub(started), Failed: err != nil})
+			}
+
+			class := cfg.classifier(err)
+
+			// Context errors are treated only while the retry sequence itself is live, as they are the
+			// sequence's own once its context is done, rather than the operation's sub-calls'.
+			if ctx.Err() == nil {
+				class = treatContextError(cfg.contextErrors, err, class)
+			}
+
+			// A failure marked with Permanent, or that the caller's predicate does not retry, is permanent.
+			if IsPermanent(err) || err != nil && cfg.retryIf != nil && !cfg.retryIf(err) {
+				class = ClassPermanentFailure
+			}
+
+			if cfg.stats != nil {
+				if stats.Classes == nil {
+					stats.Classes = make(map[Class]int)
+				}
+
+				stats.Classes[class]++
+			}
+
+			if cfg.audit != nil {
+				audited = &AuditRecord{Time: started, SequenceID: sequenceID, Attempt: attempt, Outcome: class.String()}
+
+				if err != nil {
+					audited.Error = err.Error()
+				}
+			}
+
 			if err == nil {
-				// Operation succeeded, return the result.
+				// Report the time the retry sequence took to recover, if it failed before.
+				if cfg.recoveryObserver != nil && !failedAt.IsZero() {
+					cfg.recoveryObserver(cfg.clock.Now().Sub(failedAt))
+				}
+
+				// Operation succeeded, record the provenance of the result and return it.
+				if cfg.resultMeta != nil {
+					*cfg.resultMeta = ResultMeta{Attempt: attempt, ProducedAt: cfg.clock.Now(), Source: ResultSourceOperation}
+
+					if !called {
+						cfg.resultMeta.Source = ResultSourceMiddleware
+					}
+				}
+
 				return
 			}
 
+			retainer.add(attempt, err)
+
+			if len(cfg.hooks) > 0 {
+				hooks.run(func(h Hooks) {
+					if h.AfterFailure != nil {
+						h.AfterFailure(attempt, err)
+					}
+				})
+			}
+
+			if failedAt.IsZero() {
+				failedAt = cfg.clock.Now()
+			}
+
+			// Retrying cannot fix a permanent failure, give up, returning the error marked with Permanent as is.
+			if class == ClassPermanentFailure {
+				err, permanent = unwrapPermanent(err), true
+
+				break retrying
+			}
+
+			// Replaying a non-idempotent operation after an ambiguous failure may apply it twice, give up.
+			if !cfg.retriesAmbiguous() && IsAmbiguous(err) && !IsNotSent(err) {
+				break retrying
+			}
+
 			// If the operation fails, calculate the backoff delay.
-			b := cfg.backoff(cfg.minDelay, cfg.maxDelay, attempt)
+			b := cfg.backoff(cfg.minDelay, cfg.maxDelay, attempt+cfg.backoffAttemptOffset)
+
+			// Resolve a negative delay returned by a custom backoff strategy.
+			var unresolved error
+
+			if b, unresolved = cfg.resolveDelay(b, err); unresolved != nil {
+				err = unresolved
+
+				break retrying
+			}
+
+			// Trust the delay the server asked for over the backoff delay, if configured.
+			if cfg.serverHints {
+				if hint, ok := cfg.hint(err); ok {
+					b = hint
+				}
+			}
+
+			// Align the wake time to the wall clock, if configured.
+			if cfg.alignTo > 0 {
+				b = alignWake(cfg.clock.Now(), b, cfg.alignTo)
+			}
+
+			// Fit the delay and the next attempt within the SLO, or give up if the attempt cannot finish in time.
+			if cfg.slo > 0 {
+				var ok bool
 
-			// Trigger notifier if configured, providing feedback on the error and backoff duration.
-			if cfg.notifier != nil {
-				cfg.notifier(err, b)
+				if b, ok = fitSLO(cfg.slo, cfg.clock.Now().Sub(start), attempting/time.Duration(stats.Attempts), b); !ok {
+					break retrying
+				}
+			}
+
+			// Give up if the next attempt would start after the maximum elapsed time.
+			if cfg.maxElapsedTime > 0 && cfg.clock.Now().Sub(start)+b > cfg.maxElapsedTime {
+				break retrying
+			}
+
+			// Give up once the retry budget shared with other retry sequences is exhausted.
+			if cfg.budget != nil && (cfg.maxRetries < 0 || attempt+1 < cfg.maxRetries) && !cfg.budget.TryRetry() {
+				err = fmt.Errorf("%w: %w", budget.ErrExhausted, err)
+
+				break retrying
+			}
+
+			// Trigger the notifiers if configured, providing feedback on the error and backoff duration.
+			remaining := -1
+
+			if cfg.maxRetries >= 0 {
+				remaining = cfg.maxRetries - attempt - 1
+			}
+
+			info := AttemptInfo{Attempt: attempt, Err: err, Backoff: b, Elapsed: cfg.clock.Now().Sub(start), Remaining: remaining, SequenceID: sequenceID}
+
+			hooks.dispatch(info)
+
+			// Log the failure, unless the last attempt failed and the retry sequence is about to end.
+			if cfg.logger != nil && sampled && remaining != 0 {
+				logAttempt(ctx, cfg.logger, cfg.logLevels.Attempt, sequenceID, info)
+			}
+
+			// Record the delay of a failure followed by another attempt, leaving the last one to the end.
+			if audited != nil && (cfg.maxRetries < 0 || attempt+1 < cfg.maxRetries) {
+				audited.Delay = b
+
+				if failure := cfg.audit.write(*audited); failure != nil {
+					hooks.failures = append(hooks.failures, failure)
+				}
+
+				audited = nil
+			}
+
+			// Give up softly, handing the remaining attempts over to the background if configured.
+			if next := attempt + 1; next == cfg.softGiveUp && (cfg.maxRetries < 0 || next < cfg.maxRetries) {
+				if cfg.continuation != nil {
+					continueInBackground(ctx, cfg, operation, next, b)
+
+					handedOver = true
+				}
+
+				break retrying
+			}
+
+			// Past the last attempt, the retry sequence ends once the delay is over.
+			exhausted = cfg.maxRetries >= 0 && attempt+1 >= cfg.maxRetries
+
+			saturation = saturationOf(b, cfg.maxDelay)
+
+			// A zero delay does not need a timer, yield the processor and proceed to the next attempt
+			// immediately, unless too many immediate retries ran within the current scheduling quantum.
+			if b == 0 {
+				if b = pacing.yield(); b == 0 {
+					continue
+				}
+			}
+
+			// Tell sleeping before the next attempt from giving up, which the last delay precedes.
+			if len(cfg.hooks) > 0 && !exhausted {
+				hooks.run(func(h Hooks) {
+					if h.BeforeSleep != nil {
+						h.BeforeSleep(attempt, b)
+					}
+				})
 			}
 
 			// Wait for the backoff period before the next retry attempt.
-			ticker := time.NewTicker(b)
+			endRegion = startRegion(ctx, cfg.runtimeTrace, "retrier.backoff", attempt)
+
+			timer := cfg.clock.NewTimer(b)
 
 			select {
-			case <-ticker.C:
-				// Backoff delay is over, stop the ticker and proceed to the next retry attempt.
-				ticker.Stop()
+			case <-timer.C():
+				// Backoff delay is over, stop the timer and proceed to the next retry attempt.
+				timer.Stop()
+				endRegion()
+
+				stats.TotalDelay += b
+
+				// Re-resolve the endpoint after a long delay, which may have outlived it, if configured.
+				if cfg.reresolve != nil && b >= cfg.reresolveAfter {
+					cfg.reresolve(ctx)
+				}
 			case <-ctx.Done():
-				// If the context is done, stop the ticker and return the context's error.
-				ticker.Stop()
+				// If the context is done, stop the timer and return the context's error.
+				timer.Stop()
+				endRegion()
 
-				err = ctx.Err()
+				err = contextError(ctx)
 
 				return
 			}
 		}
 	}
 
+	// The retry sequence gave up without a permanent failure, e.g., out of attempts, elapsed time, SLO,
+	// or retry budget, describe how it did.
+	if err != nil && !permanent {
+		err = &RetryError{Attempts: stats.Attempts, TotalDelay: stats.TotalDelay, Elapsed: cfg.clock.Now().Sub(start), LastErr: err, SequenceID: sequenceID}
+	}
+
+	// The retry sequence gave up on a permanent failure, cache it for other callers if configured.
+	if negativeKey != "" && permanent {
+		storeNegative(negativeKey, cfg.negativeCacheTTL, err, cfg.clock.Now())
+	}
+
+	return
+}
+
+// alignWake rounds the wake time of a delay up to the next multiple of an interval on the wall clock.
+//
+// Parameters:
+//   - now:      The current time.
+//   - delay:    The delay before the wake time.
+//   - interval: The interval the wake time is aligned to.
+//
+// Returns:
+//   - aligned: The delay before the aligned wake time.
+func alignWake(now time.Time, delay, interval time.Duration) (aligned time.Duration) {
+	wake := now.Add(delay)
+
+	boundary := wake.Truncate(interval)
+	if boundary.Before(wake) {
+		boundary = boundary.Add(interval)
+	}
+
+	aligned = boundary.Sub(now)
+
+	return
+}
+
+// Wrap returns a retried version of a function, so that an existing call site can adopt retries by
+// swapping the function it calls instead of restructuring its call flow. Every call of the returned
+// function is a retry sequence governed by policy.
+//
+// Parameters:
+//   - policy: The Configuration governing the retry sequences, e.g., built with NewValidated. A nil
+//     policy stands for the default Configuration.
+//   - fn:     The function to retry. It is called with the context of the call of the returned function.
+//
+// Returns:
+//   - wrapped: The retried version of fn.
+//
+// Example:
+//
+//	policy, _ := retrier.NewValidated(retrier.WithMaxRetries(5))
+//
+//	fetchUser = retrier.Wrap(policy, fetchUser)
+//	// Every fetchUser(ctx) call is now attempted up to 5 times.
+func Wrap[T any](policy *Configuration, fn func(ctx context.Context) (T, error)) (wrapped func(ctx context.Context) (T, error)) {
+	var opts []Option
+
+	if policy != nil {
+		opts = []Option{WithConfiguration(policy)}
+	}
+
+	wrapped = func(ctx context.Context) (result T, err error) {
+		return RetryWithData(ctx, func() (T, error) {
+			return fn(ctx)
+		}, opts...)
+	}
+
+	return
+}
+
+// continueInBackground continues a softly given up retry sequence in a background goroutine, and
+// passes its eventual outcome to the continuation.
+//
+// Parameters:
+//   - ctx:       The context of the retry sequence, detached from its cancellation.
+//   - cfg:       The Configuration of the retry sequence.
+//   - operation: The operation to retry.
+//   - next:      The zero-based number of the next attempt.
+//   - delay:     The delay before the next attempt.
+func continueInBackground[T any](ctx context.Context, cfg *Configuration, operation OperationWithContextAndData[T], next int, delay time.Duration) {
+	ctx = context.WithoutCancel(ctx)
+
+	remaining := -1
+	if cfg.maxRetries >= 0 {
+		remaining = cfg.maxRetries - next
+	}
+
+	detach := func(c *Configuration) {
+		c.softGiveUp, c.continuation = 0, nil
+		c.supersedeKey, c.negativeCacheKey = nil, nil
+		c.stats, c.resultMeta = nil, nil
+	}
+
+	go func() {
+		cfg.clock.Sleep(delay)
+
+		result, err := RetryCtxWithData(ctx, operation,
+			WithConfiguration(cfg),
+			WithMaxRetries(remaining),
+			WithBackoffAttemptOffset(cfg.backoffAttemptOffset+next),
+			detach)
+
+		cfg.continuation(result, err)
+	}()
+}
+
+const (
+	// immediateQuantum is the scheduling quantum within which immediate retries are capped.
+	immediateQuantum = 10 * time.Millisecond
+	// immediateRetriesPerQuantum is the maximum number of immediate retries within a scheduling quantum.
+	immediateRetriesPerQuantum = 64
+)
+
+// pacer keeps a retry sequence whose backoff strategy returns zero delays from monopolizing a CPU
+// core when a fast operation keeps failing.
+//
+// Fields:
+//   - clock: The clock of the retry sequence.
+//   - start: The start of the current scheduling quantum.
+//   - count: The number of immediate retries within the current scheduling quantum.
+type pacer struct {
+	clock Clock
+	start time.Time
+	count int
+}
+
+// yield yields the processor before an immediate retry, and caps the immediate retries within a
+// scheduling quantum.
+//
+// Returns:
+//   - wait: The delay to wait for until the end of the scheduling quantum if the cap is reached, or
+//     0 if the retry can proceed immediately.
+func (p *pacer) yield() (wait time.Duration) {
+	runtime.Gosched()
+
+	now := p.clock.Now()
+
+	if p.count == 0 || now.Sub(p.start) >= immediateQuantum {
+		p.start, p.count = now, 0
+	}
+
+	p.count++
+
+	if p.count > immediateRetriesPerQuantum {
+		wait = immediateQuantum - now.Sub(p.start)
+
+		p.count = 0
+	}
+
+	return
+}
+
+// noRegion is returned by startRegion when the retry sequence is not annotated.
+func noRegion() {}
+
+// startRegion starts a runtime/trace region for an attempt or a backoff delay, if the retry sequence
+// is annotated and tracing is enabled.
+//
+// Parameters:
+//   - ctx:     The context of the retry sequence, carrying its trace task.
+//   - enabled: Whether the retry sequence is annotated.
+//   - name:    The name of the region.
+//   - attempt: The zero-based number of the attempt, logged in the region.
+//
+// Returns:
+//   - end: A function ending the region.
+func startRegion(ctx context.Context, enabled bool, name string, attempt int) (end func()) {
+	if !enabled || !trace.IsEnabled() {
+		end = noRegion
+
+		return
+	}
+
+	region := trace.StartRegion(ctx, name)
+
+	trace.Log(ctx, "attempt", strconv.Itoa(attempt))
+
+	end = region.End
+
+	return
+}
+
+// chain is the middleware chain of a retry sequence. It is built once per sequence and reuses the
+// same Attempt for every attempt, so that executing an attempt does not allocate.
+type chain[T any] struct {
+	attempt *Attempt
+	run     Operation
+	result  T
+	called  bool
+}
+
+// newChain builds the middleware chain of a retry sequence around the operation.
+//
+// Parameters:
+//   - attempt:     The Attempt reused by every attempt of the sequence.
+//   - middlewares: The middlewares wrapping the operation, outermost first.
+//   - operation:   The operation to execute.
+//
+// Returns:
+//   - c: The chain, ready to execute attempts.
+func newChain[T any](attempt *Attempt, middlewares []Middleware, operation OperationWithData[T]) (c *chain[T]) {
+	c = &chain[T]{attempt: attempt}
+
+	next := Operation(func() (err error) {
+		c.called = true
+
+		c.result, err = operation()
+
+		return
+	})
+
+	for i := len(middlewares) - 1; i >= 0; i-- {
+		middleware, inner := middlewares[i], next
+
+		next = func() (err error) {
+			return middleware(attempt, inner)
+		}
+	}
+
+	c.run = next
+
+	return
+}
+
+// execute runs a single attempt of the operation through the middleware chain, and clears the values
+// stored by the middlewares once it ends.
+//
+// Parameters:
+//   - number: The zero-based number of the attempt within the retry sequence.
+//
+// Returns:
+//   - result: The result of the operation, or the zero value if a middleware did not call it.
+//   - called: Whether the operation was called by the middleware chain.
+//   - err:    The error returned by the middleware chain.
+func (c *chain[T]) execute(number int) (result T, called bool, err error) {
+	var zero T
+
+	c.attempt.Number = number
+	c.result, c.called = zero, false
+
+	err = c.run()
+
+	result, called = c.result, c.called
+	c.result = zero
+
+	c.attempt.end()
+
 	return
 }
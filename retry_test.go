@@ -1,8 +1,20 @@
 package retrier_test
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
 	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"os"
+	"regexp"
+	"runtime/trace"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -11,9 +23,15 @@ import (
 	"github.com/stretchr/testify/require"
 	"go.source.hueristiq.com/retrier"
 	"go.source.hueristiq.com/retrier/backoff"
+	"go.source.hueristiq.com/retrier/budget"
+	"go.source.hueristiq.com/retrier/jitter"
+	"go.source.hueristiq.com/retrier/policy"
 )
 
-var errTestOperation = errors.New("operation failed")
+var (
+	errTestOperation = errors.New("operation failed")
+	errNotFound      = errors.New("not found")
+)
 
 // Mock operation that will fail a given number of times before succeeding.
 type mockOperation struct {
@@ -174,3 +192,2945 @@ func TestRetry_ContextCanceled(t *testing.T) {
 	require.Error(t, err, "Expected operation to fail due to canceled context")
 	require.ErrorIs(t, err, context.Canceled, "Expected timeout error")
 }
+
+func TestRetry_BackoffAttemptOffset(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		offset   int
+		expected []int
+	}{
+		{0, []int{0, 1}},
+		{1, []int{1, 2}},
+	}
+
+	for _, tt := range tests {
+		mockOp := &mockOperation{failureCount: 2}
+
+		var attempts []int
+
+		err := retrier.Retry(context.Background(), mockOp.Operation,
+			retrier.WithMaxRetries(5),
+			retrier.WithMinDelay(time.Millisecond),
+			retrier.WithMaxDelay(time.Millisecond),
+			retrier.WithBackoffAttemptOffset(tt.offset),
+			retrier.WithBackoff(func(minDelay, _ time.Duration, attempt int) time.Duration {
+				attempts = append(attempts, attempt)
+
+				return minDelay
+			}))
+
+		require.NoError(t, err, "Expected operation to succeed after retries")
+		assert.Equal(t, tt.expected, attempts, "Unexpected attempt numbers passed to backoff for offset %d", tt.offset)
+	}
+}
+
+func TestNewValidated_DelayBoundsResolution(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		resolution       retrier.DelayBoundsResolution
+		expectedMinDelay time.Duration
+		expectedMaxDelay time.Duration
+	}{
+		{retrier.DelayBoundsClamp, 10 * time.Millisecond, 10 * time.Millisecond},
+		{retrier.DelayBoundsSwap, 10 * time.Millisecond, 20 * time.Millisecond},
+	}
+
+	for _, tt := range tests {
+		cfg, err := retrier.NewValidated(
+			retrier.WithMinDelay(20*time.Millisecond),
+			retrier.WithMaxDelay(10*time.Millisecond),
+			retrier.WithDelayBoundsResolution(tt.resolution))
+
+		require.NoError(t, err, "Expected inverted delay bounds to be resolved")
+
+		var minDelay, maxDelay time.Duration
+
+		mockOp := &mockOperation{failureCount: 1}
+
+		err = retrier.Retry(context.Background(), mockOp.Operation,
+			retrier.WithConfiguration(cfg),
+			retrier.WithBackoff(func(minD, maxD time.Duration, _ int) time.Duration {
+				minDelay, maxDelay = minD, maxD
+
+				return time.Millisecond
+			}))
+
+		require.NoError(t, err, "Expected operation to succeed after retries")
+		assert.Equal(t, tt.expectedMinDelay, minDelay, "Unexpected resolved minimum delay")
+		assert.Equal(t, tt.expectedMaxDelay, maxDelay, "Unexpected resolved maximum delay")
+	}
+}
+
+func TestNewValidated_DelayBoundsError(t *testing.T) {
+	t.Parallel()
+
+	_, err := retrier.NewValidated(
+		retrier.WithMinDelay(20*time.Millisecond),
+		retrier.WithMaxDelay(10*time.Millisecond),
+		retrier.WithDelayBoundsResolution(retrier.DelayBoundsError))
+
+	require.ErrorIs(t, err, retrier.ErrInvalidDelayBounds, "Expected invalid delay bounds error")
+
+	mockOp := &mockOperation{}
+
+	err = retrier.Retry(context.Background(), mockOp.Operation,
+		retrier.WithMinDelay(20*time.Millisecond),
+		retrier.WithMaxDelay(10*time.Millisecond),
+		retrier.WithDelayBoundsResolution(retrier.DelayBoundsError))
+
+	require.ErrorIs(t, err, retrier.ErrInvalidDelayBounds, "Expected invalid delay bounds error")
+	assert.Equal(t, 0, mockOp.callCount, "Expected the operation not to be called")
+}
+
+type requestIDKey struct{}
+
+func TestRetry_MiddlewareAttemptValues(t *testing.T) {
+	t.Parallel()
+
+	mockOp := &mockOperation{failureCount: 2}
+
+	var (
+		order    []string
+		seen     []any
+		leftover []bool
+	)
+
+	setter := func(attempt *retrier.Attempt, next retrier.Operation) error {
+		_, ok := attempt.Get(requestIDKey{})
+
+		leftover = append(leftover, ok)
+
+		attempt.Set(requestIDKey{}, attempt.Number)
+
+		order = append(order, "setter")
+
+		return next()
+	}
+
+	reader := func(attempt *retrier.Attempt, next retrier.Operation) error {
+		value, _ := attempt.Get(requestIDKey{})
+
+		seen = append(seen, value)
+
+		order = append(order, "reader")
+
+		return next()
+	}
+
+	err := retrier.Retry(context.Background(), mockOp.Operation,
+		retrier.WithMaxRetries(5),
+		retrier.WithMinDelay(time.Millisecond),
+		retrier.WithMaxDelay(time.Millisecond),
+		retrier.WithMiddleware(setter, reader))
+
+	require.NoError(t, err, "Expected operation to succeed after retries")
+	assert.Equal(t, 3, mockOp.callCount, "Expected the operation to be called 3 times")
+	assert.Equal(t, []any{0, 1, 2}, seen, "Expected each attempt to see the value set by the previous middleware")
+	assert.Equal(t, []bool{false, false, false}, leftover, "Expected values to be cleared when the attempt ends")
+	assert.Equal(t, []string{"setter", "reader", "setter", "reader", "setter", "reader"}, order, "Unexpected middleware order")
+}
+
+func TestRetryWithData_MiddlewareShortCircuit(t *testing.T) {
+	t.Parallel()
+
+	calls := 0
+
+	result, err := retrier.RetryWithData(context.Background(), func() (int, error) {
+		calls++
+
+		return 42, nil
+	},
+		retrier.WithMiddleware(func(_ *retrier.Attempt, _ retrier.Operation) error {
+			return nil
+		}))
+
+	require.NoError(t, err, "Expected the middleware result to be returned")
+	assert.Equal(t, 0, result, "Expected the zero value when the operation is not called")
+	assert.Equal(t, 0, calls, "Expected the operation not to be called")
+}
+
+func TestRetry_Sampling(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		rate     float64
+		expected int
+	}{
+		{0, 0},
+		{1, 2},
+		{-1, 0},
+		{2, 2},
+	}
+
+	for _, tt := range tests {
+		mockOp := &mockOperation{failureCount: 2}
+		notifications := 0
+
+		err := retrier.Retry(context.Background(), mockOp.Operation,
+			retrier.WithMaxRetries(5),
+			retrier.WithMinDelay(time.Millisecond),
+			retrier.WithMaxDelay(time.Millisecond),
+			retrier.WithSampling(tt.rate),
+			retrier.WithNotifier(func(_ error, _ time.Duration) {
+				notifications++
+			}))
+
+		require.NoError(t, err, "Expected operation to succeed after retries")
+		assert.Equal(t, tt.expected, notifications, "Unexpected number of notifications for sampling rate %f", tt.rate)
+	}
+}
+
+func TestRetry_Supersede(t *testing.T) {
+	t.Parallel()
+
+	key := func(_ context.Context) string { return "TestRetry_Supersede" }
+
+	started := make(chan struct{})
+	done := make(chan error)
+
+	go func() {
+		once := false
+
+		done <- retrier.Retry(context.Background(), func() error {
+			if !once {
+				once = true
+
+				close(started)
+			}
+
+			return errTestOperation
+		},
+			retrier.WithMaxRetries(100),
+			retrier.WithMinDelay(time.Second),
+			retrier.WithMaxDelay(time.Second),
+			retrier.WithSupersede(key))
+	}()
+
+	<-started
+
+	err := retrier.Retry(context.Background(), func() error { return nil }, retrier.WithSupersede(key))
+
+	require.NoError(t, err, "Expected the newer retry sequence to succeed")
+
+	select {
+	case err = <-done:
+		require.ErrorIs(t, err, retrier.ErrSuperseded, "Expected the older retry sequence to be superseded")
+		require.ErrorIs(t, err, context.Canceled, "Expected the superseded error to wrap context.Canceled")
+	case <-time.After(500 * time.Millisecond):
+		t.Fatal("Expected the older retry sequence to be cancelled")
+	}
+}
+
+func TestRetry_NegativeCache(t *testing.T) {
+	t.Parallel()
+
+	key := func(_ context.Context) string { return "TestRetry_NegativeCache" }
+
+	failing := &mockOperation{failureCount: 10}
+
+	err := retrier.Retry(context.Background(), failing.Operation,
+		retrier.WithMaxRetries(2),
+		retrier.WithMinDelay(time.Millisecond),
+		retrier.WithMaxDelay(time.Millisecond),
+		retrier.WithNegativeCache(100*time.Millisecond, key))
+
+	require.ErrorIs(t, err, errTestOperation, "Expected operation to fail after retries")
+	assert.Equal(t, 2, failing.callCount, "Expected the operation to be called 2 times")
+
+	succeeding := &mockOperation{}
+
+	err = retrier.Retry(context.Background(), succeeding.Operation,
+		retrier.WithNegativeCache(100*time.Millisecond, key))
+
+	require.NoError(t, err, "Expected running out of attempts not to be cached")
+	assert.Equal(t, 1, succeeding.callCount, "Expected the operation to be called once")
+
+	rejected := 0
+
+	err = retrier.Retry(context.Background(), func() error {
+		rejected++
+
+		return retrier.Permanent(errTestOperation)
+	}, retrier.WithNegativeCache(100*time.Millisecond, key))
+
+	require.ErrorIs(t, err, errTestOperation, "Expected operation to fail permanently")
+	assert.Equal(t, 1, rejected, "Expected the permanent failure not to be retried")
+
+	succeeding = &mockOperation{}
+
+	err = retrier.Retry(context.Background(), succeeding.Operation,
+		retrier.WithNegativeCache(100*time.Millisecond, key))
+
+	require.ErrorIs(t, err, errTestOperation, "Expected the cached failure to be returned")
+	assert.Equal(t, 0, succeeding.callCount, "Expected the operation not to be called while the failure is cached")
+
+	time.Sleep(150 * time.Millisecond)
+
+	err = retrier.Retry(context.Background(), succeeding.Operation,
+		retrier.WithNegativeCache(100*time.Millisecond, key))
+
+	require.NoError(t, err, "Expected the operation to be attempted once the cached failure expired")
+	assert.Equal(t, 1, succeeding.callCount, "Expected the operation to be called once")
+}
+
+func TestRetry_SLO(t *testing.T) {
+	t.Parallel()
+
+	mockOp := &mockOperation{failureCount: 100}
+
+	var stats retrier.Stats
+
+	start := time.Now()
+
+	err := retrier.Retry(context.Background(), mockOp.Operation,
+		retrier.WithMaxRetries(100),
+		retrier.WithMinDelay(20*time.Millisecond),
+		retrier.WithMaxDelay(time.Second),
+		retrier.WithBackoff(backoff.Exponential()),
+		retrier.WithSLO(100*time.Millisecond),
+		retrier.WithStats(&stats))
+
+	require.ErrorIs(t, err, errTestOperation, "Expected operation to fail once the SLO cannot be met")
+	assert.Less(t, time.Since(start), 150*time.Millisecond, "Expected the retry sequence to fit the SLO")
+	assert.Equal(t, mockOp.callCount, stats.Attempts, "Expected stats to report every attempt")
+	assert.Less(t, stats.Attempts, 100, "Expected attempts that cannot finish in time to be dropped")
+	assert.False(t, stats.SLOMet, "Expected the SLO not to be met by a failing sequence")
+}
+
+func TestRetry_SLOMet(t *testing.T) {
+	t.Parallel()
+
+	mockOp := &mockOperation{failureCount: 1}
+
+	var stats retrier.Stats
+
+	err := retrier.Retry(context.Background(), mockOp.Operation,
+		retrier.WithMinDelay(time.Millisecond),
+		retrier.WithMaxDelay(time.Millisecond),
+		retrier.WithSLO(time.Second),
+		retrier.WithStats(&stats))
+
+	require.NoError(t, err, "Expected operation to succeed after retries")
+	assert.True(t, stats.SLOMet, "Expected the SLO to be met")
+	assert.Equal(t, 2, stats.Attempts, "Expected stats to report 2 attempts")
+	assert.Equal(t, time.Millisecond, stats.TotalDelay, "Expected stats to report the delay between attempts")
+	assert.Equal(t, time.Second, stats.SLO, "Expected stats to report the SLO")
+}
+
+func TestRetry_Strict(t *testing.T) {
+	t.Parallel()
+
+	mockOp := &mockOperation{}
+
+	err := retrier.Retry(context.Background(), mockOp.Operation,
+		retrier.WithStrict(),
+		retrier.WithBackoff(nil),
+		retrier.WithMinDelay(-time.Second))
+
+	require.ErrorIs(t, err, retrier.ErrInvalidOption, "Expected configuration problems to be returned in strict mode")
+
+	var optionErr *retrier.OptionError
+
+	require.ErrorAs(t, err, &optionErr, "Expected a typed option error")
+	assert.Equal(t, "WithBackoff", optionErr.Option, "Expected the first problem to be reported first")
+	assert.Contains(t, err.Error(), "WithMinDelay", "Expected every problem to be reported")
+	assert.Equal(t, 0, mockOp.callCount, "Expected the operation not to be called")
+
+	nils := []struct {
+		name   string
+		option retrier.Option
+	}{
+		{"WithSupersede", retrier.WithSupersede(nil)},
+		{"WithRecoveryObserver", retrier.WithRecoveryObserver(nil)},
+		{"WithStats", retrier.WithStats(nil)},
+		{"WithResultMeta", retrier.WithResultMeta(nil)},
+	}
+
+	for _, tt := range nils {
+		_, err = retrier.NewValidated(retrier.WithStrict(), tt.option)
+
+		require.ErrorIs(t, err, retrier.ErrInvalidOption, "Expected %s(nil) to be rejected", tt.name)
+		assert.Contains(t, err.Error(), tt.name, "Expected the rejection to name %s", tt.name)
+	}
+}
+
+func TestRetry_NonStrictIgnoresInvalidOptions(t *testing.T) {
+	t.Parallel()
+
+	mockOp := &mockOperation{failureCount: 1}
+
+	err := retrier.Retry(context.Background(), mockOp.Operation,
+		retrier.WithMinDelay(time.Millisecond),
+		retrier.WithMaxDelay(time.Millisecond),
+		retrier.WithBackoff(nil),
+		retrier.WithMinDelay(-time.Second))
+
+	require.NoError(t, err, "Expected invalid options to be ignored outside strict mode")
+	assert.Equal(t, 2, mockOp.callCount, "Expected the operation to be called 2 times")
+}
+
+func TestRetry_Profiles(t *testing.T) {
+	t.Parallel()
+
+	profiles := []retrier.Option{
+		retrier.ProfileAggressive(),
+		retrier.ProfileConservative(),
+		retrier.ProfileInteractive(),
+		retrier.ProfileBatch(),
+	}
+
+	for _, profile := range profiles {
+		mockOp := &mockOperation{failureCount: 1}
+
+		var attempts []time.Duration
+
+		err := retrier.Retry(context.Background(), mockOp.Operation,
+			retrier.WithStrict(),
+			profile,
+			// Profiles are starting points, later options override their settings.
+			retrier.WithMinDelay(time.Millisecond),
+			retrier.WithMaxDelay(time.Millisecond),
+			retrier.WithNotifier(func(_ error, backoff time.Duration) {
+				attempts = append(attempts, backoff)
+			}))
+
+		require.NoError(t, err, "Expected operation to succeed after retries")
+		assert.Len(t, attempts, 1, "Expected a single retry")
+		assert.LessOrEqual(t, attempts[0], time.Millisecond, "Expected later options to override the profile")
+	}
+}
+
+func TestRetry_NonIdempotentAmbiguousFailure(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		idempotent bool
+		expected   int
+	}{
+		{true, 3},
+		{false, 1},
+	}
+
+	for _, tt := range tests {
+		calls := 0
+
+		err := retrier.Retry(context.Background(), func() error {
+			calls++
+
+			return retrier.Ambiguous(errTestOperation)
+		},
+			retrier.WithMinDelay(time.Millisecond),
+			retrier.WithMaxDelay(time.Millisecond),
+			retrier.WithIdempotent(tt.idempotent))
+
+		require.ErrorIs(t, err, errTestOperation, "Expected the ambiguous failure to unwrap to the operation error")
+		assert.True(t, retrier.IsAmbiguous(err), "Expected the failure to be reported as ambiguous")
+		assert.Equal(t, tt.expected, calls, "Unexpected number of calls for idempotent=%t", tt.idempotent)
+	}
+}
+
+func TestRetry_RetryAmbiguous(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		idempotent bool
+		retry      bool
+		err        error
+		expected   int
+	}{
+		{false, true, retrier.Ambiguous(errTestOperation), 3},
+		{true, false, retrier.Ambiguous(errTestOperation), 1},
+		{true, false, retrier.NotSent(errTestOperation), 3},
+		{false, false, retrier.NotSent(errTestOperation), 3},
+	}
+
+	for _, tt := range tests {
+		calls := 0
+
+		err := retrier.Retry(context.Background(), func() error {
+			calls++
+
+			return tt.err
+		},
+			retrier.WithMinDelay(time.Millisecond),
+			retrier.WithMaxDelay(time.Millisecond),
+			retrier.WithIdempotent(tt.idempotent),
+			retrier.WithRetryAmbiguous(tt.retry))
+
+		require.ErrorIs(t, err, errTestOperation, "Expected the marked failure to unwrap to the operation error")
+		assert.Equal(t, tt.expected, calls, "Unexpected number of calls for idempotent=%t, retry=%t", tt.idempotent, tt.retry)
+	}
+}
+
+func TestClassifyNetworkError(t *testing.T) {
+	t.Parallel()
+
+	dial := &net.OpError{Op: "dial", Net: "tcp", Err: errTestOperation}
+	read := &net.OpError{Op: "read", Net: "tcp", Err: errTestOperation}
+	dns := &net.DNSError{Err: "no such host", Name: "example.invalid"}
+
+	assert.True(t, retrier.IsNotSent(retrier.ClassifyNetworkError(dial)), "Expected dial errors not to be sent")
+	assert.True(t, retrier.IsNotSent(retrier.ClassifyNetworkError(dns)), "Expected DNS errors not to be sent")
+	assert.True(t, retrier.IsAmbiguous(retrier.ClassifyNetworkError(read)), "Expected read errors to be ambiguous")
+	assert.Equal(t, errTestOperation, retrier.ClassifyNetworkError(errTestOperation), "Expected other errors to be unchanged")
+	assert.NoError(t, retrier.ClassifyNetworkError(nil), "Expected nil to be unchanged")
+}
+
+func TestRetryWithData_ResultMeta(t *testing.T) {
+	t.Parallel()
+
+	mockOp := &mockOperation{failureCount: 1}
+
+	var meta retrier.ResultMeta
+
+	before := time.Now()
+
+	result, err := retrier.RetryWithData(context.Background(), func() (int, error) {
+		return 42, mockOp.Operation()
+	},
+		retrier.WithMinDelay(time.Millisecond),
+		retrier.WithMaxDelay(time.Millisecond),
+		retrier.WithResultMeta(&meta))
+
+	require.NoError(t, err, "Expected operation to succeed after retries")
+	assert.Equal(t, 42, result, "Expected operation result to be 42")
+	assert.Equal(t, 1, meta.Attempt, "Expected the result to be produced by the second attempt")
+	assert.Equal(t, retrier.ResultSourceOperation, meta.Source, "Expected the result to be produced by the operation")
+	assert.False(t, meta.ProducedAt.Before(before), "Expected the result timestamp to be set")
+
+	_, err = retrier.RetryWithData(context.Background(), func() (int, error) {
+		return 0, errTestOperation
+	},
+		retrier.WithMaxRetries(1),
+		retrier.WithMiddleware(func(_ *retrier.Attempt, _ retrier.Operation) error {
+			return nil
+		}),
+		retrier.WithResultMeta(&meta))
+
+	require.NoError(t, err, "Expected the middleware result to be returned")
+	assert.Equal(t, retrier.ResultSourceMiddleware, meta.Source, "Expected the result to be produced by the middleware")
+}
+
+func TestRetry_Unbounded(t *testing.T) {
+	t.Parallel()
+
+	mockOp := &mockOperation{failureCount: 20}
+
+	var stats retrier.Stats
+
+	err := retrier.Retry(context.Background(), mockOp.Operation,
+		retrier.WithMaxRetries(-1),
+		retrier.WithMinDelay(time.Microsecond),
+		retrier.WithMaxDelay(time.Microsecond),
+		retrier.WithStats(&stats))
+
+	require.NoError(t, err, "Expected operation to succeed after retries")
+	assert.Equal(t, 21, mockOp.callCount, "Expected the operation to be retried until it succeeds")
+	assert.Equal(t, []error{errTestOperation}, stats.Errors, "Expected unbounded sequences to retain the last error only")
+}
+
+func TestRetry_ErrorRetention(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		retention retrier.ErrorRetention
+		expected  []int
+	}{
+		{retrier.ErrorRetentionDefault, []int{0, 1, 2, 3, 4, 5, 6, 7, 8, 9}},
+		{retrier.ErrorRetentionAll, []int{0, 1, 2, 3, 4, 5, 6, 7, 8, 9}},
+		{retrier.ErrorRetentionLastOnly, []int{9}},
+		{retrier.ErrorRetentionSampled, []int{0, 1, 2, 4, 8, 9}},
+	}
+
+	for _, tt := range tests {
+		calls := 0
+
+		var stats retrier.Stats
+
+		err := retrier.Retry(context.Background(), func() error {
+			calls++
+
+			return &attemptError{attempt: calls - 1}
+		},
+			retrier.WithMaxRetries(10),
+			retrier.WithMinDelay(time.Microsecond),
+			retrier.WithMaxDelay(time.Microsecond),
+			retrier.WithErrorRetention(tt.retention),
+			retrier.WithStats(&stats))
+
+		require.Error(t, err, "Expected operation to fail after retries")
+
+		attempts := make([]int, 0, len(stats.Errors))
+
+		for _, retained := range stats.Errors {
+			var target *attemptError
+
+			require.ErrorAs(t, retained, &target, "Expected the retained errors to be the attempt errors")
+
+			attempts = append(attempts, target.attempt)
+		}
+
+		assert.Equal(t, tt.expected, attempts, "Unexpected retained errors for retention %d", tt.retention)
+	}
+}
+
+type attemptError struct {
+	attempt int
+}
+
+func (e *attemptError) Error() string {
+	return "attempt " + strconv.Itoa(e.attempt) + " failed"
+}
+
+func TestAttempt_Copy(t *testing.T) {
+	t.Parallel()
+
+	mockOp := &mockOperation{failureCount: 2}
+
+	var retained []*retrier.Attempt
+
+	err := retrier.Retry(context.Background(), mockOp.Operation,
+		retrier.WithMaxRetries(5),
+		retrier.WithMinDelay(time.Millisecond),
+		retrier.WithMaxDelay(time.Millisecond),
+		retrier.WithMiddleware(func(attempt *retrier.Attempt, next retrier.Operation) error {
+			attempt.Set(requestIDKey{}, attempt.Number)
+
+			retained = append(retained, attempt.Copy())
+
+			return next()
+		}))
+
+	require.NoError(t, err, "Expected operation to succeed after retries")
+	require.Len(t, retained, 3, "Expected a snapshot per attempt")
+
+	for i, snapshot := range retained {
+		value, ok := snapshot.Get(requestIDKey{})
+
+		assert.True(t, ok, "Expected the snapshot to keep the values after the attempt ended")
+		assert.Equal(t, i, value, "Unexpected value in snapshot %d", i)
+		assert.Equal(t, i, snapshot.Number, "Unexpected number of snapshot %d", i)
+	}
+}
+
+func BenchmarkRetry_Middlewares(b *testing.B) {
+	middleware := func(attempt *retrier.Attempt, next retrier.Operation) error {
+		attempt.Set(requestIDKey{}, attempt.Number)
+
+		return next()
+	}
+
+	operation := func() error {
+		return errTestOperation
+	}
+
+	opts := []retrier.Option{
+		retrier.WithMaxRetries(10),
+		retrier.WithBackoff(func(_, _ time.Duration, _ int) time.Duration {
+			return time.Nanosecond
+		}),
+		retrier.WithMiddleware(middleware, middleware, middleware),
+	}
+
+	b.ReportAllocs()
+
+	for range b.N {
+		_ = retrier.Retry(context.Background(), operation, opts...)
+	}
+}
+
+func TestRetry_RuntimeTrace(t *testing.T) {
+	t.Parallel()
+
+	var buffer bytes.Buffer
+
+	require.NoError(t, trace.Start(&buffer), "Expected the execution tracer to start")
+
+	mockOp := &mockOperation{failureCount: 1}
+
+	err := retrier.Retry(context.Background(), mockOp.Operation,
+		retrier.WithMaxRetries(3),
+		retrier.WithMinDelay(time.Millisecond),
+		retrier.WithMaxDelay(time.Millisecond),
+		retrier.WithRuntimeTrace(true))
+
+	trace.Stop()
+
+	require.NoError(t, err, "Expected operation to succeed after retries")
+
+	for _, name := range []string{"retrier.Retry", "retrier.attempt", "retrier.backoff"} {
+		assert.True(t, bytes.Contains(buffer.Bytes(), []byte(name)), "Expected the trace to contain %q", name)
+	}
+}
+
+func TestRetry_NegativeDelayResolution(t *testing.T) {
+	t.Parallel()
+
+	negative := func(_, _ time.Duration, _ int) time.Duration {
+		return -time.Second
+	}
+
+	tests := []struct {
+		resolution    retrier.NegativeDelayResolution
+		expectedCalls int
+		expectedDelay time.Duration
+	}{
+		{retrier.NegativeDelayZero, 3, 0},
+		{retrier.NegativeDelayMinDelay, 3, 3 * time.Millisecond},
+		{retrier.NegativeDelayError, 1, 0},
+	}
+
+	for _, tt := range tests {
+		calls := 0
+
+		var stats retrier.Stats
+
+		err := retrier.Retry(context.Background(), func() error {
+			calls++
+
+			return errTestOperation
+		},
+			retrier.WithMaxRetries(3),
+			retrier.WithMinDelay(time.Millisecond),
+			retrier.WithBackoff(negative),
+			retrier.WithNegativeDelayResolution(tt.resolution),
+			retrier.WithStats(&stats))
+
+		require.ErrorIs(t, err, errTestOperation, "Expected the error of the last attempt for resolution %d", tt.resolution)
+		assert.Equal(t, tt.expectedCalls, calls, "Unexpected number of calls for resolution %d", tt.resolution)
+		assert.Equal(t, tt.expectedDelay, stats.TotalDelay, "Unexpected total delay for resolution %d", tt.resolution)
+
+		if tt.resolution == retrier.NegativeDelayError {
+			assert.ErrorIs(t, err, retrier.ErrNegativeDelay, "Expected the negative delay to be reported")
+		}
+	}
+}
+
+func TestContextWithOptions(t *testing.T) {
+	t.Parallel()
+
+	assert.Empty(t, retrier.OptionsFromContext(context.Background()), "Expected no options in a bare context")
+
+	parent := retrier.ContextWithOptions(context.Background(), retrier.WithMaxRetries(2))
+	child := retrier.ContextWithOptions(parent, retrier.WithMinDelay(time.Millisecond), retrier.WithMaxDelay(time.Millisecond))
+
+	assert.Len(t, retrier.OptionsFromContext(parent), 1, "Expected the parent options to be unaffected by the child")
+
+	mockOp := &mockOperation{failureCount: 5}
+
+	err := retrier.Retry(child, mockOp.Operation, retrier.OptionsFromContext(child)...)
+
+	require.Error(t, err, "Expected operation to fail after retries")
+	assert.Equal(t, 2, mockOp.callCount, "Expected the options carried by the context to be applied")
+}
+
+func TestRace(t *testing.T) {
+	t.Parallel()
+
+	succeed := func() error { return nil }
+	fail := func() error { return errTestOperation }
+
+	opts := []retrier.Option{
+		retrier.WithMaxRetries(2),
+		retrier.WithMinDelay(time.Millisecond),
+		retrier.WithMaxDelay(time.Millisecond),
+	}
+
+	err := retrier.Race(context.Background(), []retrier.Operation{fail, succeed, succeed}, 2, opts...)
+
+	require.NoError(t, err, "Expected the quorum to be reached")
+
+	err = retrier.Race(context.Background(), []retrier.Operation{fail, fail, succeed}, 2, opts...)
+
+	require.ErrorIs(t, err, retrier.ErrQuorumNotReached, "Expected the quorum not to be reached")
+	require.ErrorIs(t, err, errTestOperation, "Expected the errors of the failed operations")
+
+	err = retrier.Race(context.Background(), []retrier.Operation{succeed}, 2, opts...)
+
+	require.ErrorIs(t, err, retrier.ErrQuorumNotReached, "Expected a quorum larger than the operations to be rejected")
+}
+
+func TestRace_CancelsRemaining(t *testing.T) {
+	t.Parallel()
+
+	stopped := make(chan int, 1)
+	calls := 0
+
+	slow := func() error {
+		calls++
+
+		if calls == 2 {
+			stopped <- calls
+		}
+
+		return errTestOperation
+	}
+
+	err := retrier.Race(context.Background(), []retrier.Operation{func() error { return nil }, slow}, 1,
+		retrier.WithMaxRetries(-1),
+		retrier.WithMinDelay(50*time.Millisecond),
+		retrier.WithMaxDelay(50*time.Millisecond))
+
+	require.NoError(t, err, "Expected the first operation to reach the quorum")
+
+	select {
+	case <-stopped:
+		t.Fatal("Expected the retry sequence of the remaining operation to be cancelled")
+	case <-time.After(150 * time.Millisecond):
+	}
+}
+
+func TestWrap(t *testing.T) {
+	t.Parallel()
+
+	policy, err := retrier.NewValidated(
+		retrier.WithMaxRetries(3),
+		retrier.WithMinDelay(time.Millisecond),
+		retrier.WithMaxDelay(time.Millisecond))
+
+	require.NoError(t, err, "Expected the policy to be valid")
+
+	calls := 0
+
+	fetch := func(ctx context.Context) (string, error) {
+		calls++
+
+		if calls < 3 {
+			return "", errTestOperation
+		}
+
+		value, _ := ctx.Value(requestIDKey{}).(string)
+
+		return value, nil
+	}
+
+	fetch = retrier.Wrap(policy, fetch)
+
+	result, err := fetch(context.WithValue(context.Background(), requestIDKey{}, "request"))
+
+	require.NoError(t, err, "Expected the wrapped function to succeed after retries")
+	assert.Equal(t, "request", result, "Expected the wrapped function to receive the context of the call")
+	assert.Equal(t, 3, calls, "Expected the wrapped function to be retried")
+}
+
+func TestConfiguration_Validate(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		opts     []retrier.Option
+		expected error
+	}{
+		{[]retrier.Option{retrier.WithMaxRetries(5)}, nil},
+		{[]retrier.Option{retrier.WithMaxRetries(5), retrier.WithSLO(10 * time.Second)}, nil},
+		{[]retrier.Option{
+			retrier.WithMaxRetries(5),
+			retrier.WithMinDelay(30 * time.Second),
+			retrier.WithMaxDelay(30 * time.Second),
+			retrier.WithSLO(10 * time.Second),
+		}, retrier.ErrScheduleExceedsBudget},
+		{[]retrier.Option{
+			retrier.WithMaxRetries(-1),
+			retrier.WithSLO(time.Second),
+		}, nil},
+		// The shortest delays of equal jitter are 1.5s, 3s, and 6s, from its Bounds.
+		{[]retrier.Option{
+			retrier.WithMaxRetries(4),
+			retrier.WithMinDelay(time.Second),
+			retrier.WithMaxDelay(time.Minute),
+			retrier.WithBackoff(backoff.ExponentialWithEqualJitter()),
+			retrier.WithSLO(10500 * time.Millisecond),
+		}, nil},
+		{[]retrier.Option{
+			retrier.WithMaxRetries(4),
+			retrier.WithMinDelay(time.Second),
+			retrier.WithMaxDelay(time.Minute),
+			retrier.WithBackoff(backoff.ExponentialWithEqualJitter()),
+			retrier.WithSLO(10 * time.Second),
+		}, retrier.ErrScheduleExceedsBudget},
+		// The floor of the jitter raises the shortest delays to 2s, 3s, and 6s.
+		{[]retrier.Option{
+			retrier.WithMaxRetries(4),
+			retrier.WithMinDelay(time.Second),
+			retrier.WithMaxDelay(time.Minute),
+			retrier.WithBackoff(backoff.ExponentialWithEqualJitter(jitter.WithFloor(time.Second))),
+			retrier.WithSLO(10900 * time.Millisecond),
+		}, retrier.ErrScheduleExceedsBudget},
+		{[]retrier.Option{
+			retrier.WithMaxRetries(4),
+			retrier.WithMinDelay(time.Second),
+			retrier.WithMaxDelay(time.Minute),
+			retrier.WithBackoff(backoff.ExponentialWithEqualJitter(jitter.WithFloor(time.Second))),
+			retrier.WithSLO(11 * time.Second),
+		}, nil},
+		{[]retrier.Option{
+			retrier.WithMaxRetries(5),
+			retrier.WithMinDelay(30 * time.Second),
+			retrier.WithMaxDelay(30 * time.Second),
+			retrier.WithMaxElapsedTime(10 * time.Second),
+		}, retrier.ErrScheduleExceedsBudget},
+		{[]retrier.Option{
+			retrier.WithMaxRetries(5),
+			retrier.WithMinDelay(time.Second),
+			retrier.WithMaxDelay(time.Second),
+			retrier.WithMaxElapsedTime(10 * time.Second),
+			retrier.WithSLO(3 * time.Second),
+		}, retrier.ErrScheduleExceedsBudget},
+	}
+
+	for i, tt := range tests {
+		cfg, err := retrier.NewValidated(tt.opts...)
+
+		require.NoError(t, err, "Expected configuration %d to be valid", i)
+
+		if tt.expected == nil {
+			assert.NoError(t, cfg.Validate(), "Expected no warning for configuration %d", i)
+		} else {
+			assert.ErrorIs(t, cfg.Validate(), tt.expected, "Expected a warning for configuration %d", i)
+		}
+	}
+}
+
+type hintedError struct {
+	hint time.Duration
+}
+
+func (e *hintedError) Error() string {
+	return "retry after " + e.hint.String()
+}
+
+func (e *hintedError) RetryAfter() time.Duration {
+	return e.hint
+}
+
+func TestRetry_ServerHints(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		opts     []retrier.Option
+		expected time.Duration
+	}{
+		{"default", nil, 6 * time.Millisecond},
+		{"trusted", []retrier.Option{retrier.WithServerHints(true)}, 6 * time.Millisecond},
+		{"ignored", []retrier.Option{retrier.WithServerHints(false)}, 2 * time.Millisecond},
+		{"ignored with extractor", []retrier.Option{
+			retrier.WithServerHints(false),
+			retrier.WithRetryAfterFrom(func(error) (time.Duration, bool) { return 5 * time.Millisecond, true }),
+		}, 2 * time.Millisecond},
+	}
+
+	for _, tt := range tests {
+		var stats retrier.Stats
+
+		opts := append([]retrier.Option{
+			retrier.WithMaxRetries(2),
+			retrier.WithMinDelay(time.Millisecond),
+			retrier.WithMaxDelay(time.Millisecond),
+			retrier.WithStats(&stats),
+		}, tt.opts...)
+
+		err := retrier.Retry(context.Background(), func() error {
+			return fmt.Errorf("request failed: %w", &hintedError{hint: 3 * time.Millisecond})
+		}, opts...)
+
+		require.Error(t, err, "Expected operation to fail after retries")
+		assert.Equal(t, tt.expected, stats.TotalDelay, "Unexpected total delay with %s hints", tt.name)
+	}
+}
+
+func TestRetry_RetryAfterFrom(t *testing.T) {
+	t.Parallel()
+
+	var stats retrier.Stats
+
+	extract := func(err error) (time.Duration, bool) {
+		if errors.Is(err, errTestOperation) {
+			return 3 * time.Millisecond, true
+		}
+
+		return 0, false
+	}
+
+	err := retrier.Retry(context.Background(), func() error {
+		return errTestOperation
+	},
+		retrier.WithMaxRetries(2),
+		retrier.WithMinDelay(time.Millisecond),
+		retrier.WithMaxDelay(time.Millisecond),
+		retrier.WithRetryAfterFrom(extract),
+		retrier.WithStats(&stats))
+
+	require.ErrorIs(t, err, errTestOperation, "Expected operation to fail after retries")
+	assert.Equal(t, 6*time.Millisecond, stats.TotalDelay, "Expected the extracted delays to replace the backoff delays")
+
+	err = retrier.Retry(context.Background(), func() error {
+		return &hintedError{hint: 2 * time.Millisecond}
+	},
+		retrier.WithMaxRetries(2),
+		retrier.WithMinDelay(time.Millisecond),
+		retrier.WithMaxDelay(time.Millisecond),
+		retrier.WithRetryAfterFrom(extract),
+		retrier.WithStats(&stats))
+
+	require.Error(t, err, "Expected operation to fail after retries")
+	assert.Equal(t, 4*time.Millisecond, stats.TotalDelay, "Expected errors without an extracted delay to fall back to RetryAfter")
+}
+
+func TestRetry_ImmediateRetriesPacing(t *testing.T) {
+	t.Parallel()
+
+	immediate := func(_, _ time.Duration, _ int) time.Duration {
+		return 0
+	}
+
+	tests := []struct {
+		retries int
+		paced   bool
+	}{
+		{10, false},
+		{500, true},
+	}
+
+	for _, tt := range tests {
+		var stats retrier.Stats
+
+		err := retrier.Retry(context.Background(), func() error {
+			return errTestOperation
+		},
+			retrier.WithMaxRetries(tt.retries),
+			retrier.WithBackoff(immediate),
+			retrier.WithStats(&stats))
+
+		require.ErrorIs(t, err, errTestOperation, "Expected operation to fail after retries")
+		assert.Equal(t, tt.retries, stats.Attempts, "Expected every attempt to be executed")
+		assert.Equal(t, tt.paced, stats.TotalDelay > 0, "Unexpected pacing of %d immediate retries", tt.retries)
+	}
+}
+
+func TestRetry_AbandonAfter(t *testing.T) {
+	t.Parallel()
+
+	release := make(chan struct{})
+
+	var calls atomic.Int32
+
+	var stats retrier.Stats
+
+	result, err := retrier.RetryWithData(context.Background(), func() (int, error) {
+		if calls.Add(1) == 1 {
+			<-release
+
+			return 0, errTestOperation
+		}
+
+		return 42, nil
+	},
+		retrier.WithMaxRetries(3),
+		retrier.WithMinDelay(time.Millisecond),
+		retrier.WithMaxDelay(time.Millisecond),
+		retrier.WithAbandonAfter(10*time.Millisecond),
+		retrier.WithStats(&stats))
+
+	require.NoError(t, err, "Expected the attempt following the abandoned one to succeed")
+	assert.Equal(t, 42, result, "Expected the result of the second attempt")
+	assert.Equal(t, 1, stats.Abandoned, "Expected the hanging attempt to be abandoned")
+	assert.Equal(t, int64(1), retrier.AbandonedAttempts(), "Expected the abandoned attempt to be accounted as running")
+
+	close(release)
+
+	assert.Eventually(t, func() bool {
+		return retrier.AbandonedAttempts() == 0
+	}, time.Second, time.Millisecond, "Expected the abandoned attempt to be released once it returns")
+}
+
+//nolint:paralleltest // The attempt left running would be counted by the AbandonedAttempts of other tests.
+func TestRetry_AbandonAfter_Canceled(t *testing.T) {
+	release := make(chan struct{})
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	var stats retrier.Stats
+
+	err := retrier.Retry(ctx, func() error {
+		cancel()
+
+		<-release
+
+		return errTestOperation
+	},
+		retrier.WithAbandonAfter(time.Hour),
+		retrier.WithStats(&stats))
+
+	require.ErrorIs(t, err, context.Canceled, "Expected the error of the canceled retry sequence")
+	assert.Zero(t, stats.Abandoned, "Expected an attempt outliving its canceled retry sequence not to be counted as abandoned")
+
+	close(release)
+
+	assert.Eventually(t, func() bool {
+		return retrier.AbandonedAttempts() == 0
+	}, time.Second, time.Millisecond, "Expected the attempt to be released once it returns")
+}
+
+func TestRetry_SequenceID(t *testing.T) {
+	t.Parallel()
+
+	uuid := regexp.MustCompile(`^[0-9a-f]{8}-[0-9a-f]{4}-7[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`)
+
+	ids := make([]string, 0, 2)
+
+	for range 2 {
+		mockOp := &mockOperation{failureCount: 2}
+
+		var (
+			seen     []string
+			notified []string
+			stats    retrier.Stats
+		)
+
+		err := retrier.Retry(context.Background(), mockOp.Operation,
+			retrier.WithMaxRetries(3),
+			retrier.WithMinDelay(time.Millisecond),
+			retrier.WithMaxDelay(time.Millisecond),
+			retrier.WithStats(&stats),
+			retrier.WithNotifierV2(func(info retrier.AttemptInfo) {
+				notified = append(notified, info.SequenceID)
+			}),
+			retrier.WithMiddleware(func(attempt *retrier.Attempt, next retrier.Operation) error {
+				seen = append(seen, attempt.SequenceID)
+
+				return next()
+			}))
+
+		require.NoError(t, err, "Expected operation to succeed after retries")
+		assert.Regexp(t, uuid, stats.SequenceID, "Expected the sequence ID to be a UUID version 7")
+		assert.Equal(t, []string{stats.SequenceID, stats.SequenceID, stats.SequenceID}, seen, "Expected the attempts to share the sequence ID")
+		assert.Equal(t, []string{stats.SequenceID, stats.SequenceID}, notified, "Expected the notifications to carry the sequence ID")
+
+		ids = append(ids, stats.SequenceID)
+	}
+
+	assert.NotEqual(t, ids[0], ids[1], "Expected every retry sequence to have its own ID")
+}
+
+func TestRetry_NotifiersOrderingAndIsolation(t *testing.T) {
+	t.Parallel()
+
+	var (
+		order []string
+		stats retrier.Stats
+	)
+
+	mockOp := &mockOperation{failureCount: 2}
+
+	err := retrier.Retry(context.Background(), mockOp.Operation,
+		retrier.WithMaxRetries(3),
+		retrier.WithMinDelay(time.Millisecond),
+		retrier.WithMaxDelay(time.Millisecond),
+		retrier.WithStats(&stats),
+		retrier.WithNotifier(func(_ error, _ time.Duration) {
+			order = append(order, "metrics")
+		}),
+		retrier.WithNotifier(func(_ error, _ time.Duration) {
+			order = append(order, "tracing")
+
+			panic("tracing exporter unavailable")
+		}, func(_ error, _ time.Duration) {
+			order = append(order, "logging")
+		}))
+
+	require.NoError(t, err, "Expected a panicking notifier not to stop the retry sequence")
+	assert.Equal(t, []string{"metrics", "tracing", "logging", "metrics", "tracing", "logging"}, order, "Expected notifiers to be called in registration order")
+	require.Len(t, stats.HookFailures, 2, "Expected a hook failure per panic")
+
+	var failure *retrier.HookPanicError
+
+	require.ErrorAs(t, stats.HookFailures[0], &failure, "Expected the panics to be recorded as HookPanicErrors")
+	require.ErrorIs(t, failure, retrier.ErrHookPanicked, "Expected the failure to wrap ErrHookPanicked")
+	assert.Equal(t, retrier.HookNotifier, failure.Kind, "Expected the panic of a notifier")
+	assert.Equal(t, 1, failure.Hook, "Expected the index of the panicking notifier")
+	assert.Equal(t, "tracing exporter unavailable", failure.Value, "Expected the panic value")
+}
+
+func TestRetry_NotifierV2(t *testing.T) {
+	t.Parallel()
+
+	var (
+		infos []retrier.AttemptInfo
+		order []string
+	)
+
+	mockOp := &mockOperation{failureCount: 2}
+
+	err := retrier.Retry(context.Background(), mockOp.Operation,
+		retrier.WithMaxRetries(4),
+		retrier.WithMinDelay(time.Millisecond),
+		retrier.WithMaxDelay(time.Millisecond),
+		retrier.WithNotifier(func(_ error, _ time.Duration) {
+			order = append(order, "v1")
+		}),
+		retrier.WithNotifierV2(func(info retrier.AttemptInfo) {
+			order = append(order, "v2")
+			infos = append(infos, info)
+		}))
+
+	require.NoError(t, err)
+	assert.Equal(t, []string{"v1", "v2", "v1", "v2"}, order, "Expected both kinds of notifiers to be called in registration order")
+	require.Len(t, infos, 2)
+
+	for i, info := range infos {
+		assert.Equal(t, i, info.Attempt, "Expected the number of the failed attempt")
+		require.ErrorIs(t, info.Err, errTestOperation, "Expected the error of the failed attempt")
+		assert.Equal(t, time.Millisecond, info.Backoff, "Expected the backoff before the next attempt")
+		assert.Equal(t, 3-i, info.Remaining, "Expected the number of remaining attempts")
+	}
+
+	assert.Greater(t, infos[1].Elapsed, infos[0].Elapsed, "Expected the cumulative elapsed time")
+
+	infos = nil
+
+	_ = retrier.Retry(context.Background(), (&mockOperation{failureCount: 1}).Operation,
+		retrier.WithMaxRetries(-1),
+		retrier.WithMinDelay(time.Millisecond),
+		retrier.WithMaxDelay(time.Millisecond),
+		retrier.WithNotifierV2(func(info retrier.AttemptInfo) {
+			infos = append(infos, info)
+		}))
+
+	require.Len(t, infos, 1)
+	assert.Equal(t, -1, infos[0].Remaining, "Expected unlimited attempts to be reported as -1")
+}
+
+func TestConfiguration_ValidateContext(t *testing.T) {
+	t.Parallel()
+
+	cfg, err := retrier.NewValidated(
+		retrier.WithMaxRetries(5),
+		retrier.WithMinDelay(30*time.Second),
+		retrier.WithMaxDelay(30*time.Second))
+
+	require.NoError(t, err)
+	require.NoError(t, cfg.ValidateContext(context.Background()), "Expected no warning without a deadline")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	err = cfg.ValidateContext(ctx)
+
+	require.ErrorIs(t, err, retrier.ErrScheduleExceedsBudget, "Expected a warning for a schedule exceeding the deadline")
+	assert.Contains(t, err.Error(), "context deadline", "Expected the deadline to be named as the budget")
+
+	ctx, cancel = context.WithTimeout(context.Background(), time.Hour)
+	defer cancel()
+
+	assert.NoError(t, cfg.ValidateContext(ctx), "Expected no warning for a schedule fitting the deadline")
+}
+
+func TestRetry_ScheduleWarning(t *testing.T) {
+	t.Parallel()
+
+	var buffer bytes.Buffer
+
+	logger := slog.New(slog.NewJSONHandler(&buffer, nil))
+
+	cfg, err := retrier.NewValidated(
+		retrier.WithMaxRetries(3),
+		retrier.WithMinDelay(time.Millisecond),
+		retrier.WithMaxDelay(time.Millisecond),
+		retrier.WithSLO(time.Microsecond),
+		retrier.WithLogger(logger))
+
+	require.NoError(t, err)
+	assert.Equal(t, 1, strings.Count(buffer.String(), "retry schedule exceeds its time budget"), "Expected a warning once the configuration is created")
+
+	for range 2 {
+		require.NoError(t, retrier.Retry(context.Background(), (&mockOperation{}).Operation, retrier.WithConfiguration(cfg)))
+	}
+
+	assert.Equal(t, 1, strings.Count(buffer.String(), "retry schedule exceeds its time budget"), "Expected the warning not to be repeated for the copies of the configuration")
+
+	buffer.Reset()
+
+	cfg, err = retrier.NewValidated(
+		retrier.WithMaxRetries(3),
+		retrier.WithMinDelay(time.Second),
+		retrier.WithMaxDelay(time.Second),
+		retrier.WithLogger(logger))
+
+	require.NoError(t, err)
+	assert.Empty(t, buffer.String(), "Expected no warning without a time budget")
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	for range 2 {
+		require.NoError(t, retrier.Retry(ctx, (&mockOperation{}).Operation, retrier.WithConfiguration(cfg)))
+	}
+
+	assert.Equal(t, 1, strings.Count(buffer.String(), "the time left until the context deadline"), "Expected a warning once for the deadline of the context")
+}
+
+func TestRetry_Logger(t *testing.T) {
+	t.Parallel()
+
+	var buffer bytes.Buffer
+
+	logger := slog.New(slog.NewJSONHandler(&buffer, &slog.HandlerOptions{Level: slog.LevelDebug}))
+
+	err := retrier.Retry(context.Background(), (&mockOperation{failureCount: 5}).Operation,
+		retrier.WithMaxRetries(3),
+		retrier.WithMinDelay(time.Millisecond),
+		retrier.WithMaxDelay(time.Millisecond),
+		retrier.WithLogger(logger))
+
+	require.ErrorIs(t, err, errTestOperation)
+
+	var records []map[string]any
+
+	for _, line := range strings.Split(strings.TrimSpace(buffer.String()), "\n") {
+		var record map[string]any
+
+		require.NoError(t, json.Unmarshal([]byte(line), &record))
+
+		records = append(records, record)
+	}
+
+	require.Len(t, records, 3, "Expected a record per retried attempt and one for the outcome")
+
+	for i, record := range records[:2] {
+		assert.Equal(t, "WARN", record["level"])
+		assert.Equal(t, "retrying after failed attempt", record["msg"])
+		assert.InDelta(t, i, record["attempt"], 0)
+		assert.Equal(t, errTestOperation.Error(), record["error"])
+		assert.InDelta(t, 2-i, record["remaining"], 0)
+		assert.NotEmpty(t, record["sequence_id"])
+	}
+
+	assert.Equal(t, "ERROR", records[2]["level"])
+	assert.Equal(t, "retry sequence failed", records[2]["msg"])
+	assert.InDelta(t, 3, records[2]["attempts"], 0)
+	assert.Equal(t, records[0]["sequence_id"], records[2]["sequence_id"], "Expected the records to share the sequence ID")
+
+	buffer.Reset()
+
+	err = retrier.Retry(context.Background(), (&mockOperation{failureCount: 1}).Operation,
+		retrier.WithMinDelay(time.Millisecond),
+		retrier.WithMaxDelay(time.Millisecond),
+		retrier.WithLogger(logger),
+		retrier.WithLogLevels(retrier.LogLevels{Attempt: slog.LevelDebug, Success: slog.LevelInfo, Failure: slog.LevelError}))
+
+	require.NoError(t, err)
+	assert.Contains(t, buffer.String(), `"level":"DEBUG","msg":"retrying after failed attempt"`, "Expected the configured attempt level")
+	assert.Contains(t, buffer.String(), `"level":"INFO","msg":"retry sequence succeeded"`, "Expected the configured success level")
+}
+
+func TestRetry_Hooks(t *testing.T) {
+	t.Parallel()
+
+	var (
+		events []string
+		stats  retrier.Stats
+	)
+
+	hooks := retrier.Hooks{
+		BeforeAttempt: func(attempt int) {
+			events = append(events, fmt.Sprintf("attempt %d", attempt))
+		},
+		AfterFailure: func(attempt int, err error) {
+			events = append(events, fmt.Sprintf("failure %d: %v", attempt, err))
+		},
+		BeforeSleep: func(attempt int, delay time.Duration) {
+			events = append(events, fmt.Sprintf("sleep %d: %s", attempt, delay))
+		},
+		OnSuccess: func(attempts int) {
+			events = append(events, fmt.Sprintf("success after %d", attempts))
+		},
+		OnGiveUp: func(attempts int, err error) {
+			var exhausted *retrier.RetryError
+
+			events = append(events, fmt.Sprintf("give up after %d, exhausted: %t", attempts, errors.As(err, &exhausted)))
+		},
+	}
+
+	opts := []retrier.Option{
+		retrier.WithMaxRetries(2),
+		retrier.WithMinDelay(time.Millisecond),
+		retrier.WithMaxDelay(time.Millisecond),
+		retrier.WithHooks(hooks),
+	}
+
+	err := retrier.Retry(context.Background(), (&mockOperation{failureCount: 1}).Operation, opts...)
+
+	require.NoError(t, err)
+	assert.Equal(t, []string{"attempt 0", "failure 0: operation failed", "sleep 0: 1ms", "attempt 1", "success after 2"}, events)
+
+	events = nil
+
+	err = retrier.Retry(context.Background(), (&mockOperation{failureCount: 5}).Operation, opts...)
+
+	require.Error(t, err)
+	assert.Equal(t, []string{
+		"attempt 0", "failure 0: operation failed", "sleep 0: 1ms",
+		"attempt 1", "failure 1: operation failed", "give up after 2, exhausted: true",
+	}, events, "Expected no sleep hook before giving up")
+
+	events = nil
+
+	err = retrier.Retry(context.Background(), (&mockOperation{}).Operation,
+		retrier.WithStats(&stats),
+		retrier.WithHooks(retrier.Hooks{OnSuccess: func(int) {
+			panic("metrics unavailable")
+		}}),
+		retrier.WithHooks(hooks))
+
+	require.NoError(t, err, "Expected a panicking hook not to fail the retry sequence")
+	assert.Equal(t, []string{"attempt 0", "success after 1"}, events, "Expected the hooks that follow a panicking one to run")
+	require.Len(t, stats.HookFailures, 1)
+	require.ErrorIs(t, stats.HookFailures[0], retrier.ErrHookPanicked)
+
+	var failure *retrier.HookPanicError
+
+	require.ErrorAs(t, stats.HookFailures[0], &failure)
+	assert.Equal(t, retrier.HookLifecycle, failure.Kind, "Expected the panic of a lifecycle hook")
+	assert.Equal(t, 0, failure.Hook, "Expected the index of the panicking Hooks")
+	assert.EqualError(t, failure, "hook panicked: lifecycle hook 0: metrics unavailable")
+}
+
+func TestRetry_Policy(t *testing.T) {
+	t.Parallel()
+
+	p, err := policy.FromJSON([]byte(`{"max_retries": 2, "min_delay": "1ms", "max_delay": "1ms", "strategy": "constant"}`))
+
+	require.NoError(t, err)
+
+	var stats retrier.Stats
+
+	mockOp := &mockOperation{failureCount: 5}
+
+	err = retrier.Retry(context.Background(), mockOp.Operation, retrier.WithPolicy(p), retrier.WithStats(&stats))
+
+	require.ErrorIs(t, err, errTestOperation)
+	assert.Equal(t, 2, mockOp.callCount, "Expected the maximum number of attempts of the policy")
+	assert.Equal(t, "linear", retrier.New(retrier.WithPolicy(p)).Explain(errTestOperation, 0).Strategy.Name, "Expected the backoff strategy of the policy")
+
+	_, err = retrier.NewValidated(retrier.WithStrict(), retrier.WithPolicy(policy.Policy{MaxRetries: 3, Strategy: "fibonacci"}))
+
+	require.ErrorContains(t, err, `unknown strategy "fibonacci"`, "Expected an invalid policy to be rejected in strict mode")
+
+	_, err = retrier.NewValidated(retrier.WithStrict(), retrier.WithPolicy(policy.Policy{MaxRetries: 3, MinDelay: time.Second, MaxDelay: time.Millisecond}))
+
+	require.ErrorContains(t, err, "MinDelay 1s is greater than MaxDelay 1ms", "Expected an inconsistent policy to be rejected in strict mode")
+
+	var optionErr *retrier.OptionError
+
+	require.ErrorAs(t, err, &optionErr)
+	assert.Equal(t, "WithPolicy", optionErr.Option, "Expected the rejection to name the option")
+
+	cfg, err := retrier.NewValidated(retrier.WithMaxRetries(4), retrier.WithPolicy(policy.Policy{MaxRetries: 0}))
+
+	require.NoError(t, err, "Expected an inconsistent policy to be ignored outside strict mode")
+
+	var attempts int
+
+	_ = retrier.Retry(context.Background(), func() error {
+		attempts++
+
+		return errTestOperation
+	}, retrier.WithConfiguration(cfg), retrier.WithMinDelay(time.Millisecond), retrier.WithMaxDelay(time.Millisecond))
+
+	assert.Equal(t, 4, attempts, "Expected none of the settings of a rejected policy to be applied")
+}
+
+//nolint:paralleltest // t.Setenv cannot be used in parallel tests.
+func TestFromEnv(t *testing.T) {
+	t.Setenv("PAYMENTS_RETRY_MAX", "2")
+	t.Setenv("PAYMENTS_RETRY_WAIT_MIN", "1ms")
+	t.Setenv("PAYMENTS_RETRY_WAIT_MAX", "5ms")
+	t.Setenv("PAYMENTS_BACKOFF", "linear")
+
+	mockOp := &mockOperation{failureCount: 5}
+
+	err := retrier.Retry(context.Background(), mockOp.Operation, retrier.FromEnv("PAYMENTS"))
+
+	require.ErrorIs(t, err, errTestOperation)
+	assert.Equal(t, 2, mockOp.callCount, "Expected the maximum number of attempts from the environment")
+
+	explanation := retrier.New(retrier.WithMaxRetries(5), retrier.FromEnv("PAYMENTS")).Explain(errTestOperation, 1)
+
+	assert.Equal(t, "linear", explanation.Strategy.Name, "Expected the backoff strategy from the environment")
+
+	explanation = retrier.New(retrier.WithMaxRetries(5), retrier.FromEnv("PAYMENTS_"), retrier.WithMaxRetries(5)).Explain(errTestOperation, 1)
+
+	assert.Equal(t, 2*time.Millisecond, explanation.Delay, "Expected linear delays growing by the minimum delay")
+
+	t.Setenv("PAYMENTS_RETRY_MAX", "many")
+	t.Setenv("PAYMENTS_BACKOFF", "fibonacci")
+
+	_, err = retrier.NewValidated(retrier.WithStrict(), retrier.FromEnv("PAYMENTS"))
+
+	require.ErrorContains(t, err, `PAYMENTS_RETRY_MAX: invalid integer "many"`, "Expected malformed values to be reported")
+	require.ErrorContains(t, err, `PAYMENTS_BACKOFF: unknown backoff strategy "fibonacci"`, "Expected unknown strategies to be reported")
+
+	_, err = retrier.NewValidated(retrier.WithStrict(), retrier.FromEnv("UNSET"))
+
+	require.NoError(t, err, "Expected unset variables to leave the configuration unchanged")
+}
+
+func TestAttempts(t *testing.T) {
+	t.Parallel()
+
+	var numbers []int
+
+	for attempt := range retrier.Attempts(context.Background(), retrier.WithMaxRetries(3), retrier.WithMinDelay(time.Millisecond), retrier.WithMaxDelay(time.Millisecond)) {
+		numbers = append(numbers, attempt.Number)
+
+		assert.NotEmpty(t, attempt.SequenceID, "Expected the attempts to carry the sequence ID")
+	}
+
+	assert.Equal(t, []int{0, 1, 2}, numbers, "Expected an iteration per attempt")
+
+	numbers = nil
+
+	for attempt := range retrier.Attempts(context.Background(), retrier.WithMaxRetries(5), retrier.WithMinDelay(time.Millisecond), retrier.WithMaxDelay(time.Millisecond)) {
+		numbers = append(numbers, attempt.Number)
+
+		if attempt.Number == 1 {
+			break
+		}
+	}
+
+	assert.Equal(t, []int{0, 1}, numbers, "Expected breaking out of the loop to end the retry sequence")
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	defer cancel()
+
+	numbers = nil
+
+	for attempt := range retrier.Attempts(ctx, retrier.WithMaxRetries(-1), retrier.WithMinDelay(time.Hour), retrier.WithMaxDelay(time.Hour)) {
+		numbers = append(numbers, attempt.Number)
+
+		cancel()
+	}
+
+	assert.Equal(t, []int{0}, numbers, "Expected the context to interrupt the backoff delay")
+
+	numbers = nil
+
+	for attempt := range retrier.Attempts(context.Background(), retrier.WithStrict(), retrier.WithMaxDelay(-time.Second)) {
+		numbers = append(numbers, attempt.Number)
+	}
+
+	assert.Equal(t, []int{0}, numbers, "Expected an invalid configuration to fall back to a single attempt")
+}
+
+func TestOptions(t *testing.T) {
+	t.Parallel()
+
+	notified := 0
+
+	defaults := retrier.Options(
+		retrier.WithMaxRetries(5),
+		retrier.WithMinDelay(time.Millisecond),
+		retrier.WithMaxDelay(time.Millisecond),
+		nil,
+		retrier.WithNotifier(func(_ error, _ time.Duration) {
+			notified++
+		}),
+	)
+
+	mockOp := &mockOperation{failureCount: 10}
+
+	err := retrier.Retry(context.Background(), mockOp.Operation, defaults, retrier.WithMaxRetries(2))
+
+	require.Error(t, err, "Expected operation to fail after retries")
+	assert.Equal(t, 2, mockOp.callCount, "Expected options passed after the bundle to override it")
+	assert.Equal(t, 2, notified, "Expected the notifier of the bundle to be applied")
+}
+
+func TestUntilAcquired(t *testing.T) {
+	t.Parallel()
+
+	tries, released := 0, false
+
+	tryAcquire := func(_ context.Context) (func(), bool, error) {
+		tries++
+
+		if tries < 3 {
+			return nil, false, nil
+		}
+
+		return func() { released = true }, true, nil
+	}
+
+	opts := []retrier.Option{
+		retrier.WithMaxRetries(3),
+		retrier.WithMinDelay(time.Millisecond),
+		retrier.WithMaxDelay(time.Millisecond),
+	}
+
+	release, err := retrier.UntilAcquired(context.Background(), tryAcquire, opts...)
+
+	require.NoError(t, err, "Expected the lock to be acquired once released elsewhere")
+	require.NotNil(t, release, "Expected the release function of the successful attempt")
+
+	release()
+
+	assert.True(t, released, "Expected the release function to release the lock")
+	assert.Equal(t, 3, tries, "Expected the acquisition to be retried while contended")
+
+	_, err = retrier.UntilAcquired(context.Background(), func(_ context.Context) (func(), bool, error) {
+		return nil, false, nil
+	}, opts...)
+
+	require.ErrorIs(t, err, retrier.ErrNotAcquired, "Expected ErrNotAcquired when the lock stays contended")
+}
+
+func TestRetryPressure(t *testing.T) {
+	t.Parallel()
+
+	err := retrier.Retry(context.Background(), func() error {
+		return errTestOperation
+	},
+		retrier.WithMaxRetries(5),
+		retrier.WithMinDelay(time.Millisecond),
+		retrier.WithMaxDelay(time.Millisecond))
+
+	require.Error(t, err, "Expected operation to fail after retries")
+
+	pressure := retrier.RetryPressure()
+
+	assert.Greater(t, pressure, 0.0, "Expected the retries to raise the retry pressure")
+	assert.LessOrEqual(t, pressure, 1.0, "Expected the retry pressure to be a ratio")
+}
+
+func TestRetryWithData_SoftGiveUp(t *testing.T) {
+	t.Parallel()
+
+	var calls atomic.Int32
+
+	type outcome struct {
+		result any
+		err    error
+	}
+
+	eventual := make(chan outcome, 1)
+
+	var stats retrier.Stats
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	_, err := retrier.RetryWithData(ctx, func() (int, error) {
+		if calls.Add(1) < 3 {
+			return 0, errTestOperation
+		}
+
+		return 42, nil
+	},
+		retrier.WithMaxRetries(5),
+		retrier.WithMinDelay(time.Millisecond),
+		retrier.WithMaxDelay(time.Millisecond),
+		retrier.WithStats(&stats),
+		retrier.WithSoftGiveUp(1, func(result any, err error) {
+			eventual <- outcome{result, err}
+		}))
+
+	// The background retry sequence outlives the caller's context.
+	cancel()
+
+	require.ErrorIs(t, err, errTestOperation, "Expected the caller to receive the current error")
+	assert.Equal(t, 1, stats.Attempts, "Expected the caller to give up after the first attempt")
+
+	select {
+	case o := <-eventual:
+		require.NoError(t, o.err, "Expected the background retry sequence to succeed")
+		assert.Equal(t, 42, o.result, "Expected the continuation to receive the eventual result")
+		assert.Equal(t, int32(3), calls.Load(), "Expected the background retry sequence to continue the attempts")
+	case <-time.After(time.Second):
+		t.Fatal("Expected the continuation to be called")
+	}
+}
+
+func TestRetry_NoRetry(t *testing.T) {
+	t.Parallel()
+
+	for _, mark := range []func(context.Context) context.Context{retrier.WithNoRetry, retrier.WithMaxOneAttempt} {
+		ctx := mark(context.Background())
+
+		assert.True(t, retrier.NoRetry(ctx), "Expected the context to be marked")
+
+		mockOp := &mockOperation{failureCount: 5}
+
+		err := retrier.Retry(ctx, mockOp.Operation, retrier.WithMaxRetries(-1))
+
+		require.ErrorIs(t, err, errTestOperation, "Expected the error of the single attempt")
+		assert.Equal(t, 1, mockOp.callCount, "Expected a single attempt for a request marked as not to be retried")
+	}
+
+	assert.False(t, retrier.NoRetry(context.Background()), "Expected a bare context not to be marked")
+}
+
+func TestClassify(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		err      error
+		expected retrier.Class
+	}{
+		{nil, retrier.ClassSuccess},
+		{errTestOperation, retrier.ClassTransientFailure},
+		{retrier.Ambiguous(errTestOperation), retrier.ClassAmbiguous},
+		{retrier.NotSent(retrier.Ambiguous(errTestOperation)), retrier.ClassTransientFailure},
+		{&hintedError{hint: time.Second}, retrier.ClassThrottled},
+		{fmt.Errorf("query: %w", context.DeadlineExceeded), retrier.ClassTimeout},
+		{&net.OpError{Op: "read", Err: os.ErrDeadlineExceeded}, retrier.ClassTimeout},
+	}
+
+	for _, tt := range tests {
+		assert.Equal(t, tt.expected, retrier.Classify(tt.err), "Unexpected class of %v", tt.err)
+	}
+
+	assert.Equal(t, "transient_failure", retrier.ClassTransientFailure.String(), "Unexpected class name")
+}
+
+func TestRetry_Classifier(t *testing.T) {
+	t.Parallel()
+
+	calls := 0
+
+	var stats retrier.Stats
+
+	err := retrier.Retry(context.Background(), func() error {
+		calls++
+
+		if calls == 1 {
+			return errTestOperation
+		}
+
+		return errNotFound
+	},
+		retrier.WithMaxRetries(5),
+		retrier.WithMinDelay(time.Millisecond),
+		retrier.WithMaxDelay(time.Millisecond),
+		retrier.WithStats(&stats),
+		retrier.WithClassifier(func(err error) retrier.Class {
+			if errors.Is(err, errNotFound) {
+				return retrier.ClassPermanentFailure
+			}
+
+			return retrier.Classify(err)
+		}))
+
+	require.ErrorIs(t, err, errNotFound, "Expected the permanent failure to be returned")
+	assert.Equal(t, 2, calls, "Expected the permanent failure to stop the retry sequence")
+	assert.Equal(t, map[retrier.Class]int{retrier.ClassTransientFailure: 1, retrier.ClassPermanentFailure: 1}, stats.Classes, "Unexpected classes")
+}
+
+func TestRetry_AutoTune(t *testing.T) {
+	t.Parallel()
+
+	tuner := backoff.AutoTune()
+
+	mockOp := &mockOperation{failureCount: 1}
+
+	err := retrier.Retry(context.Background(), mockOp.Operation,
+		retrier.WithMaxRetries(3),
+		retrier.WithMinDelay(time.Millisecond),
+		retrier.WithMaxDelay(time.Millisecond),
+		retrier.WithAutoTune(tuner))
+
+	require.NoError(t, err, "Expected operation to succeed after retries")
+	assert.GreaterOrEqual(t, tuner.Horizon(), time.Millisecond, "Expected the recovery of the retry sequence to be observed")
+}
+
+func TestRetry_ContextErrors(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		treatment retrier.ContextErrorTreatment
+		expected  int
+	}{
+		{retrier.ContextErrorsClassify, 3},
+		{retrier.ContextErrorsRetry, 3},
+		{retrier.ContextErrorsGiveUp, 1},
+	}
+
+	for _, tt := range tests {
+		calls := 0
+
+		err := retrier.Retry(context.Background(), func() error {
+			calls++
+
+			return fmt.Errorf("inner call: %w", context.DeadlineExceeded)
+		},
+			retrier.WithMaxRetries(3),
+			retrier.WithMinDelay(time.Millisecond),
+			retrier.WithMaxDelay(time.Millisecond),
+			retrier.WithClassifier(func(err error) retrier.Class {
+				if tt.treatment == retrier.ContextErrorsRetry && err != nil {
+					return retrier.ClassPermanentFailure
+				}
+
+				return retrier.Classify(err)
+			}),
+			retrier.WithContextErrors(tt.treatment))
+
+		require.ErrorIs(t, err, context.DeadlineExceeded, "Expected the error of the operation for treatment %d", tt.treatment)
+		assert.Equal(t, tt.expected, calls, "Unexpected number of attempts for treatment %d", tt.treatment)
+	}
+}
+
+func TestRetry_HookTiming(t *testing.T) {
+	t.Parallel()
+
+	const delay = 20 * time.Millisecond
+
+	t.Run("BeforeSleep", func(t *testing.T) {
+		t.Parallel()
+
+		var failedAt time.Time
+
+		waited := []time.Duration{}
+
+		_ = retrier.Retry(context.Background(), func() error {
+			failedAt = time.Now()
+
+			return errTestOperation
+		},
+			retrier.WithMaxRetries(2),
+			retrier.WithMinDelay(delay),
+			retrier.WithMaxDelay(delay),
+			retrier.WithNotifier(func(_ error, _ time.Duration) {
+				waited = append(waited, time.Since(failedAt))
+			}),
+			retrier.WithHookTiming(retrier.HookTimingBeforeSleep))
+
+		require.Len(t, waited, 2, "Expected a notification per failed attempt")
+		assert.Less(t, waited[0], delay, "Expected the notifier to run before the delay")
+	})
+
+	t.Run("AfterSleep", func(t *testing.T) {
+		t.Parallel()
+
+		var failedAt time.Time
+
+		waited := []time.Duration{}
+
+		_ = retrier.Retry(context.Background(), func() error {
+			failedAt = time.Now()
+
+			return errTestOperation
+		},
+			retrier.WithMaxRetries(2),
+			retrier.WithMinDelay(delay),
+			retrier.WithMaxDelay(delay),
+			retrier.WithNotifier(func(_ error, _ time.Duration) {
+				waited = append(waited, time.Since(failedAt))
+			}),
+			retrier.WithHookTiming(retrier.HookTimingAfterSleep))
+
+		require.Len(t, waited, 2, "Expected a notification per failed attempt, including the last one")
+		assert.GreaterOrEqual(t, waited[0], delay, "Expected the notifier to run after the delay")
+	})
+
+	t.Run("Async", func(t *testing.T) {
+		t.Parallel()
+
+		notified := make(chan int, 3)
+		release := make(chan struct{})
+		calls := 0
+
+		start := time.Now()
+
+		_ = retrier.Retry(context.Background(), func() error {
+			calls++
+
+			return &attemptError{attempt: calls}
+		},
+			retrier.WithMaxRetries(3),
+			retrier.WithMinDelay(time.Millisecond),
+			retrier.WithMaxDelay(time.Millisecond),
+			retrier.WithNotifier(func(err error, _ time.Duration) {
+				<-release
+
+				var failed *attemptError
+
+				if errors.As(err, &failed) {
+					notified <- failed.attempt
+				}
+			}),
+			retrier.WithHookTiming(retrier.HookTimingAsync))
+
+		assert.Less(t, time.Since(start), time.Second, "Expected blocked notifiers not to delay the retry sequence")
+
+		close(release)
+
+		assert.Equal(t, 1, <-notified, "Expected notifications in order")
+		assert.Equal(t, 2, <-notified, "Expected notifications in order")
+		assert.Equal(t, 3, <-notified, "Expected notifications in order")
+	})
+}
+
+type strictRetriesKey struct{}
+
+func TestRetry_DynamicClassifier(t *testing.T) {
+	t.Parallel()
+
+	permanent := func(_ error) retrier.Class {
+		return retrier.ClassPermanentFailure
+	}
+
+	provider := func(ctx context.Context) retrier.Classifier {
+		if strict, _ := ctx.Value(strictRetriesKey{}).(bool); strict {
+			return permanent
+		}
+
+		return nil
+	}
+
+	run := func(ctx context.Context) (calls int) {
+		_ = retrier.Retry(ctx, func() error {
+			calls++
+
+			return errTestOperation
+		},
+			retrier.WithMaxRetries(3),
+			retrier.WithMinDelay(time.Millisecond),
+			retrier.WithMaxDelay(time.Millisecond),
+			retrier.WithDynamicClassifier(provider))
+
+		return
+	}
+
+	assert.Equal(t, 3, run(context.Background()), "Expected the default classifier when the provider returns nil")
+	assert.Equal(t, 1, run(context.WithValue(context.Background(), strictRetriesKey{}, true)), "Expected the provided classifier")
+
+	_, err := retrier.NewValidated(retrier.WithStrict(), retrier.WithDynamicClassifier(nil))
+
+	require.Error(t, err, "Expected a nil provider to be rejected")
+}
+
+func TestPool(t *testing.T) {
+	t.Parallel()
+
+	pool := retrier.NewPool(2, retrier.WithMaxRetries(3), retrier.WithMinDelay(time.Millisecond), retrier.WithMaxDelay(time.Millisecond))
+
+	var running, peak atomic.Int32
+
+	outcomes := make([]<-chan error, 0, 10)
+
+	for range 10 {
+		mockOp := &mockOperation{failureCount: 1}
+
+		done, err := pool.Submit(context.Background(), func() error {
+			if n := running.Add(1); n > peak.Load() {
+				peak.Store(n)
+			}
+
+			defer running.Add(-1)
+
+			time.Sleep(time.Millisecond)
+
+			return mockOp.Operation()
+		})
+
+		require.NoError(t, err, "Expected the task to be submitted")
+
+		outcomes = append(outcomes, done)
+	}
+
+	for _, done := range outcomes {
+		require.NoError(t, <-done, "Expected every task to succeed after a retry")
+	}
+
+	assert.LessOrEqual(t, peak.Load(), int32(2), "Expected at most 2 attempts at a time")
+
+	calls := 0
+
+	done, err := pool.Submit(context.Background(), func() error {
+		calls++
+
+		return errTestOperation
+	}, retrier.WithMaxRetries(1))
+
+	require.NoError(t, err, "Expected the task to be submitted")
+	require.ErrorIs(t, <-done, errTestOperation, "Expected the error of the task")
+	assert.Equal(t, 1, calls, "Expected the per-task override of the policy")
+
+	pool.Close()
+
+	_, err = pool.Submit(context.Background(), func() error { return nil })
+
+	require.ErrorIs(t, err, retrier.ErrPoolClosed, "Expected submissions to be rejected once closed")
+}
+
+func TestRetryUntilStable(t *testing.T) {
+	t.Parallel()
+
+	equals := func(a, b int) bool { return a == b }
+	opts := []retrier.Option{
+		retrier.WithMaxRetries(10),
+		retrier.WithMinDelay(time.Millisecond),
+		retrier.WithMaxDelay(time.Millisecond),
+	}
+
+	reads := []int{1, 2, 2, 3, 3, 3}
+	calls := 0
+
+	result, err := retrier.RetryUntilStable(context.Background(), func() (int, error) {
+		read := reads[min(calls, len(reads)-1)]
+
+		calls++
+
+		return read, nil
+	}, equals, 3, opts...)
+
+	require.NoError(t, err, "Expected the result to stabilize")
+	assert.Equal(t, 3, result, "Expected the stable result")
+	assert.Equal(t, 6, calls, "Expected polling to stop once the result is stable")
+
+	calls = 0
+
+	result, err = retrier.RetryUntilStable(context.Background(), func() (int, error) {
+		calls++
+
+		return calls, nil
+	}, equals, 2, opts...)
+
+	require.ErrorIs(t, err, retrier.ErrNotStable, "Expected ErrNotStable when the result never stabilizes")
+	assert.Equal(t, 10, result, "Expected the last result observed")
+}
+
+func TestRetry_Fingerprint(t *testing.T) {
+	t.Parallel()
+
+	for _, timing := range []retrier.HookTiming{retrier.HookTimingBeforeSleep, retrier.HookTimingAfterSleep} {
+		failures := []error{errTestOperation, errTestOperation, errTestOperation, errNotFound, errNotFound}
+		calls := 0
+		notified := []string{}
+
+		_ = retrier.Retry(context.Background(), func() error {
+			calls++
+
+			return failures[calls-1]
+		},
+			retrier.WithMaxRetries(len(failures)),
+			retrier.WithMinDelay(time.Millisecond),
+			retrier.WithMaxDelay(time.Millisecond),
+			retrier.WithNotifier(func(err error, _ time.Duration) {
+				notified = append(notified, err.Error())
+			}),
+			retrier.WithHookTiming(timing),
+			retrier.WithFingerprint(nil))
+
+		expected := []string{
+			errTestOperation.Error(),
+			errTestOperation.Error() + " (repeated 2 times)",
+			errNotFound.Error(),
+			errNotFound.Error() + " (repeated 1 times)",
+		}
+
+		assert.Equal(t, expected, notified, "Unexpected notifications for timing %d", timing)
+	}
+
+	var repeated *retrier.RepeatedError
+
+	err := error(&retrier.RepeatedError{Err: errTestOperation, Repeats: 3})
+
+	require.ErrorAs(t, err, &repeated, "Expected a RepeatedError")
+	require.ErrorIs(t, err, errTestOperation, "Expected the repeated error to be unwrapped")
+	assert.NotEqual(t, retrier.Fingerprint(errTestOperation), retrier.Fingerprint(errNotFound), "Expected distinct fingerprints for distinct errors")
+	assert.Empty(t, retrier.Fingerprint(nil), "Expected no fingerprint for a nil error")
+}
+
+func TestRetry_RetryIf(t *testing.T) {
+	t.Parallel()
+
+	calls := 0
+
+	err := retrier.Retry(context.Background(), func() error {
+		calls++
+
+		if calls < 3 {
+			return errTestOperation
+		}
+
+		return errNotFound
+	},
+		retrier.WithMaxRetries(5),
+		retrier.WithMinDelay(time.Millisecond),
+		retrier.WithMaxDelay(time.Millisecond),
+		retrier.WithRetryIf(func(err error) bool {
+			return !errors.Is(err, errNotFound)
+		}))
+
+	require.ErrorIs(t, err, errNotFound, "Expected the error the predicate does not retry")
+	assert.Equal(t, 3, calls, "Expected the retry sequence to stop at the first error not retried")
+}
+
+func TestRetry_Permanent(t *testing.T) {
+	t.Parallel()
+
+	calls := 0
+
+	err := retrier.Retry(context.Background(), func() error {
+		calls++
+
+		if calls < 2 {
+			return errTestOperation
+		}
+
+		return retrier.Permanent(errNotFound)
+	},
+		retrier.WithMaxRetries(5),
+		retrier.WithMinDelay(time.Millisecond),
+		retrier.WithMaxDelay(time.Millisecond))
+
+	assert.Equal(t, errNotFound, err, "Expected the error wrapped by Permanent") //nolint:testifylint // The unwrapped error itself is expected.
+	assert.Equal(t, 2, calls, "Expected the retry sequence to stop at the permanent failure")
+
+	assert.True(t, retrier.IsPermanent(fmt.Errorf("wrapped: %w", retrier.Permanent(errNotFound))), "Expected wrapped permanent failures to be detected")
+	assert.False(t, retrier.IsPermanent(errNotFound), "Expected unmarked errors not to be permanent")
+	require.NoError(t, retrier.Permanent(nil), "Expected a nil error to be returned as is")
+	assert.Equal(t, retrier.ClassPermanentFailure, retrier.Classify(retrier.Permanent(errNotFound)), "Expected permanent failures to be classified as such")
+}
+
+func TestPool_SubmitKeyed(t *testing.T) {
+	t.Parallel()
+
+	pool := retrier.NewPool(1, retrier.WithMaxRetries(1))
+
+	defer pool.Close()
+
+	started, block := make(chan struct{}), make(chan struct{})
+
+	blocker, err := pool.Submit(context.Background(), func() error {
+		close(started)
+
+		<-block
+
+		return nil
+	})
+
+	require.NoError(t, err, "Expected the task to be submitted")
+
+	<-started
+
+	var (
+		mutex sync.Mutex
+		order []string
+	)
+
+	record := func(key string) retrier.Operation {
+		return func() error {
+			mutex.Lock()
+			defer mutex.Unlock()
+
+			order = append(order, key)
+
+			return nil
+		}
+	}
+
+	outcomes := make([]<-chan error, 0, 6)
+
+	for range 5 {
+		done, err := pool.SubmitKeyed(context.Background(), "hot", record("hot"))
+
+		require.NoError(t, err, "Expected the task to be submitted")
+
+		outcomes = append(outcomes, done)
+	}
+
+	time.Sleep(20 * time.Millisecond)
+
+	done, err := pool.SubmitKeyed(context.Background(), "cold", record("cold"))
+
+	require.NoError(t, err, "Expected the task to be submitted")
+
+	outcomes = append(outcomes, done)
+
+	time.Sleep(20 * time.Millisecond)
+
+	close(block)
+
+	require.NoError(t, <-blocker, "Expected the blocking task to succeed")
+
+	for _, done := range outcomes {
+		require.NoError(t, <-done, "Expected every task to succeed")
+	}
+
+	assert.Equal(t, []string{"hot", "cold", "hot", "hot", "hot", "hot"}, order, "Expected the keys to be served in round-robin")
+}
+
+func TestRetrier(t *testing.T) {
+	t.Parallel()
+
+	r := retrier.New(retrier.WithMaxRetries(3), retrier.WithMinDelay(time.Millisecond), retrier.WithMaxDelay(time.Millisecond))
+
+	require.NoError(t, r.Err(), "Expected the options to be resolved")
+
+	for range 2 {
+		mockOp := &mockOperation{failureCount: 2}
+
+		require.NoError(t, r.Do(context.Background(), mockOp.Operation), "Expected the operation to succeed on every call")
+		assert.Equal(t, 3, mockOp.callCount, "Expected the policy to apply to every call")
+	}
+
+	calls := 0
+
+	result, err := retrier.DoWithData(context.Background(), r, func() (int, error) {
+		calls++
+
+		if calls < 2 {
+			return 0, errTestOperation
+		}
+
+		return 42, nil
+	})
+
+	require.NoError(t, err, "Expected the operation to succeed")
+	assert.Equal(t, 42, result, "Expected the result of the operation")
+
+	invalid := retrier.New(retrier.WithStrict(), retrier.WithMinDelay(-time.Second))
+
+	require.Error(t, invalid.Err(), "Expected the configuration error")
+	require.ErrorIs(t, invalid.Do(context.Background(), func() error { return nil }), invalid.Err(), "Expected every call to return the configuration error")
+}
+
+func BenchmarkRetrier_Do(b *testing.B) {
+	operation := func() error {
+		return nil
+	}
+
+	r := retrier.New(retrier.WithMaxRetries(10), retrier.WithMinDelay(time.Millisecond), retrier.WithMaxDelay(time.Second))
+
+	b.ReportAllocs()
+
+	for range b.N {
+		_ = r.Do(context.Background(), operation)
+	}
+}
+
+func TestWaitAll(t *testing.T) {
+	t.Parallel()
+
+	postgres := &mockOperation{failureCount: 2}
+
+	report, err := retrier.WaitAll(context.Background(), map[string]retrier.Operation{
+		"postgres": postgres.Operation,
+		"redis":    func() error { return errTestOperation },
+	}, retrier.WithMaxRetries(3), retrier.WithMinDelay(time.Millisecond), retrier.WithMaxDelay(time.Millisecond))
+
+	require.ErrorIs(t, err, retrier.ErrNotReady, "Expected ErrNotReady")
+	require.ErrorIs(t, err, errTestOperation, "Expected the error of the dependency that is not ready")
+	assert.Contains(t, err.Error(), "redis", "Expected the dependency that is not ready to be named")
+
+	assert.True(t, report["postgres"].Ready, "Expected postgres to become ready")
+	assert.Equal(t, 3, report["postgres"].Attempts, "Unexpected number of attempts for postgres")
+	assert.False(t, report["redis"].Ready, "Expected redis not to become ready")
+	assert.Equal(t, 3, report["redis"].Attempts, "Unexpected number of attempts for redis")
+	require.ErrorIs(t, report["redis"].Err, errTestOperation, "Expected the error of redis")
+
+	_, err = retrier.WaitAll(context.Background(), map[string]retrier.Operation{"postgres": func() error { return nil }})
+
+	require.NoError(t, err, "Expected every dependency to be ready")
+}
+
+func TestRetrier_Explain(t *testing.T) {
+	t.Parallel()
+
+	r := retrier.New(
+		retrier.WithMaxRetries(3),
+		retrier.WithMinDelay(100*time.Millisecond),
+		retrier.WithMaxDelay(time.Second),
+		retrier.WithBackoff(backoff.Exponential()),
+		retrier.WithServerHints(true),
+		retrier.WithRetryIf(func(err error) bool {
+			return !errors.Is(err, errNotFound)
+		}))
+
+	explanation := r.Explain(errTestOperation, 1)
+
+	assert.True(t, explanation.Retry, "Expected a transient failure to be retried")
+	assert.Equal(t, retrier.ClassTransientFailure, explanation.Class, "Unexpected class")
+	assert.Equal(t, 200*time.Millisecond, explanation.Delay, "Unexpected delay")
+	assert.Equal(t, "exponential", explanation.Strategy.Name, "Unexpected strategy")
+
+	explanation = r.Explain(&hintedError{hint: 3 * time.Second}, 0)
+
+	assert.True(t, explanation.Retry, "Expected a throttled failure to be retried")
+	assert.Equal(t, 3*time.Second, explanation.Delay, "Expected the delay hinted by the server")
+
+	for _, err := range []error{retrier.Permanent(errTestOperation), errNotFound} {
+		explanation = r.Explain(err, 0)
+
+		assert.False(t, explanation.Retry, "Expected %v not to be retried", err)
+		assert.Equal(t, retrier.ClassPermanentFailure, explanation.Class, "Unexpected class for %v", err)
+	}
+
+	explanation = r.Explain(errTestOperation, 2)
+
+	assert.False(t, explanation.Retry, "Expected the last attempt not to be retried")
+	assert.Equal(t, "attempts exhausted", explanation.Reason, "Unexpected reason")
+}
+
+func TestRetry_Budget(t *testing.T) {
+	t.Parallel()
+
+	b := budget.New(0, time.Minute, budget.WithMinRetries(2))
+
+	opts := []retrier.Option{
+		retrier.WithMaxRetries(5),
+		retrier.WithMinDelay(time.Millisecond),
+		retrier.WithMaxDelay(time.Millisecond),
+		retrier.WithBudget(b),
+	}
+
+	calls := 0
+
+	err := retrier.Retry(context.Background(), func() error {
+		calls++
+
+		return errTestOperation
+	}, opts...)
+
+	require.ErrorIs(t, err, budget.ErrExhausted, "Expected the retry sequence to give up once the budget is exhausted")
+	require.ErrorIs(t, err, errTestOperation, "Expected the error of the last attempt")
+	assert.Equal(t, 3, calls, "Expected the first attempt and the retries of the budget")
+
+	calls = 0
+
+	err = retrier.Retry(context.Background(), func() error {
+		calls++
+
+		return errTestOperation
+	}, opts...)
+
+	require.ErrorIs(t, err, budget.ErrExhausted, "Expected the shared budget to stay exhausted")
+	assert.Equal(t, 1, calls, "Expected first attempts never to be refused")
+}
+
+func TestRetryHedged(t *testing.T) {
+	t.Parallel()
+
+	var (
+		calls     atomic.Int32
+		cancelled atomic.Bool
+	)
+
+	start := time.Now()
+
+	result, err := retrier.RetryHedged(context.Background(), func(ctx context.Context) (int, error) {
+		if calls.Add(1) == 1 {
+			// The first call is slow, and must be cancelled once the speculative one wins.
+			select {
+			case <-time.After(time.Second):
+			case <-ctx.Done():
+				cancelled.Store(true)
+			}
+
+			return 1, ctx.Err()
+		}
+
+		return 2, nil
+	}, 10*time.Millisecond, retrier.WithMaxRetries(1))
+
+	require.NoError(t, err, "Expected the speculative call to succeed")
+	assert.Equal(t, 2, result, "Expected the result of the speculative call")
+	assert.Less(t, time.Since(start), time.Second, "Expected the slow call not to be waited for")
+	assert.Eventually(t, cancelled.Load, time.Second, time.Millisecond, "Expected the losing call to be cancelled")
+
+	calls.Store(0)
+
+	_, err = retrier.RetryHedged(context.Background(), func(_ context.Context) (int, error) {
+		calls.Add(1)
+
+		return 0, errTestOperation
+	}, time.Second, retrier.WithMaxRetries(2), retrier.WithMinDelay(time.Millisecond), retrier.WithMaxDelay(time.Millisecond))
+
+	require.ErrorIs(t, err, errTestOperation, "Expected the error of the calls")
+	assert.Equal(t, int32(2), calls.Load(), "Expected fast failures to be retried rather than hedged")
+
+	var numbers []int
+
+	_, err = retrier.RetryHedged(context.Background(), func(ctx context.Context) (int, error) {
+		meta, ok := retrier.AttemptFromContext(ctx)
+
+		assert.True(t, ok, "Expected the calls to get the metadata of their attempt")
+
+		numbers = append(numbers, meta.Number)
+
+		return 0, errTestOperation
+	}, time.Second, retrier.WithMaxRetries(2), retrier.WithMinDelay(time.Millisecond), retrier.WithMaxDelay(time.Millisecond))
+
+	require.ErrorIs(t, err, errTestOperation, "Expected the error of the calls")
+	assert.Equal(t, []int{0, 1}, numbers, "Expected the number of the attempt of every call")
+
+	cancelled.Store(false)
+
+	_, err = retrier.RetryHedged(context.Background(), func(ctx context.Context) (int, error) {
+		<-ctx.Done()
+
+		cancelled.Store(true)
+
+		return 0, ctx.Err()
+	}, time.Second, retrier.WithMaxRetries(1), retrier.WithAbandonAfter(10*time.Millisecond))
+
+	require.ErrorIs(t, err, retrier.ErrAttemptAbandoned, "Expected the attempt to be abandoned")
+	assert.Eventually(t, cancelled.Load, time.Second, time.Millisecond, "Expected abandoning the attempt to cancel the call in flight")
+}
+
+func TestRetry_AlignTo(t *testing.T) {
+	t.Parallel()
+
+	const interval = 50 * time.Millisecond
+
+	var wakes []time.Time
+
+	_ = retrier.Retry(context.Background(), func() error {
+		wakes = append(wakes, time.Now())
+
+		return errTestOperation
+	},
+		retrier.WithMaxRetries(3),
+		retrier.WithMinDelay(time.Millisecond),
+		retrier.WithMaxDelay(time.Millisecond),
+		retrier.WithAlignTo(interval))
+
+	require.Len(t, wakes, 3, "Expected every attempt to run")
+
+	for _, wake := range wakes[1:] {
+		assert.Less(t, wake.Sub(wake.Truncate(interval)), 10*time.Millisecond, "Expected retries to wake right after an interval boundary")
+	}
+}
+
+func TestSimple(t *testing.T) {
+	t.Parallel()
+
+	mockOp := &mockOperation{failureCount: 2}
+
+	require.NoError(t, retrier.Simple(context.Background(), 3, time.Millisecond, mockOp.Operation), "Expected the operation to succeed")
+	assert.Equal(t, 3, mockOp.callCount, "Expected 3 attempts")
+
+	calls := 0
+
+	ctx := retrier.ContextWithOptions(context.Background(), retrier.WithMaxRetries(1))
+
+	err := retrier.Simple(ctx, 3, time.Millisecond, func() error {
+		calls++
+
+		return errTestOperation
+	})
+
+	require.ErrorIs(t, err, errTestOperation, "Expected the error of the operation")
+	assert.Equal(t, 1, calls, "Expected the options carried by the context to apply")
+}
+
+func TestRetry_Ledger(t *testing.T) {
+	t.Parallel()
+
+	ledger := new(retrier.Ledger)
+
+	var compensated []int
+
+	calls := 0
+
+	err := retrier.Retry(context.Background(), func() error {
+		calls++
+
+		attempt := calls
+
+		ledger.Add(func() error {
+			compensated = append(compensated, attempt)
+
+			if attempt == 2 {
+				return errNotFound
+			}
+
+			return nil
+		})
+
+		return errTestOperation
+	},
+		retrier.WithMaxRetries(3),
+		retrier.WithMinDelay(time.Millisecond),
+		retrier.WithMaxDelay(time.Millisecond),
+		retrier.WithLedger(ledger))
+
+	require.ErrorIs(t, err, errTestOperation, "Expected the error of the last attempt")
+	require.ErrorIs(t, err, retrier.ErrCompensationFailed, "Expected the failed compensation to be reported")
+	require.ErrorIs(t, err, errNotFound, "Expected the error of the failed compensation")
+	assert.Equal(t, []int{3, 2, 1}, compensated, "Expected the compensations to run in reverse order")
+	assert.Zero(t, ledger.Len(), "Expected the compensations to be cleared")
+
+	ledger = new(retrier.Ledger)
+	mockOp := &mockOperation{failureCount: 1}
+
+	err = retrier.Retry(context.Background(), func() error {
+		ledger.Add(func() error {
+			t.Error("Expected no compensation once the retry sequence succeeded")
+
+			return nil
+		})
+
+		return mockOp.Operation()
+	}, retrier.WithMinDelay(time.Millisecond), retrier.WithLedger(ledger))
+
+	require.NoError(t, err, "Expected the retry sequence to succeed")
+	assert.Zero(t, ledger.Len(), "Expected the compensations to be discarded")
+}
+
+func TestRetry_RuntimeSnapshot(t *testing.T) {
+	t.Parallel()
+
+	err := retrier.Retry(context.Background(), func() error {
+		return errTestOperation
+	}, retrier.WithMaxRetries(1), retrier.WithRuntimeSnapshot(true))
+
+	var snapshot *retrier.SnapshotError
+
+	require.ErrorAs(t, err, &snapshot, "Expected a snapshot attached to the error")
+	require.ErrorIs(t, err, errTestOperation, "Expected the error of the last attempt")
+	assert.Positive(t, snapshot.Snapshot.Goroutines, "Expected the goroutines to be counted")
+	assert.Positive(t, snapshot.Snapshot.HeapBytes, "Expected the heap to be measured")
+
+	err = retrier.Retry(context.Background(), func() error {
+		return nil
+	}, retrier.WithRuntimeSnapshot(true))
+
+	require.NoError(t, err, "Expected no snapshot once the retry sequence succeeded")
+}
+
+func TestRetry_Schedule(t *testing.T) {
+	t.Parallel()
+
+	var (
+		calls  int
+		delays []time.Duration
+	)
+
+	err := retrier.Retry(context.Background(), func() error {
+		calls++
+
+		return errTestOperation
+	},
+		retrier.WithSchedule(time.Millisecond, 2*time.Millisecond),
+		retrier.WithNotifier(func(_ error, delay time.Duration) {
+			delays = append(delays, delay)
+		}))
+
+	require.ErrorIs(t, err, errTestOperation, "Expected the error of the last attempt")
+	assert.Equal(t, 3, calls, "Expected the retry sequence to give up once the schedule is exhausted")
+	assert.Equal(t, []time.Duration{time.Millisecond, 2 * time.Millisecond}, delays[:2], "Expected the delays of the schedule")
+}
+
+func TestRetry_Timeline(t *testing.T) {
+	t.Parallel()
+
+	var timelines []policy.Timeline
+
+	mockOp := &mockOperation{failureCount: 2}
+
+	err := retrier.Retry(context.Background(), mockOp.Operation,
+		retrier.WithMinDelay(time.Millisecond),
+		retrier.WithMaxDelay(time.Millisecond),
+		retrier.WithTimeline(func(timeline policy.Timeline) {
+			timelines = append(timelines, timeline)
+		}))
+
+	require.NoError(t, err, "Expected the operation to succeed")
+	require.Len(t, timelines, 1, "Expected one timeline per retry sequence")
+	require.Len(t, timelines[0], 3, "Expected a record per attempt")
+	assert.True(t, timelines[0][0].Failed, "Expected the first attempt to have failed")
+	assert.False(t, timelines[0][2].Failed, "Expected the last attempt to have succeeded")
+	assert.True(t, timelines[0][1].Start.After(timelines[0][0].Start), "Expected the attempts in order")
+
+	assert.Equal(t, 1, policy.Analyze(timelines).Recovered, "Expected the timeline to be analyzable")
+}
+
+func TestRetry_Reresolve(t *testing.T) {
+	t.Parallel()
+
+	var (
+		attempts   int
+		reresolved []int
+		hookCtx    context.Context //nolint:containedctx // Records the context the hook receives.
+	)
+
+	ctx := context.Background()
+
+	err := retrier.Retry(ctx, func() error {
+		attempts++
+
+		return errTestOperation
+	},
+		retrier.WithSchedule(time.Millisecond, 20*time.Millisecond, time.Millisecond),
+		retrier.WithReresolve(10*time.Millisecond, func(ctx context.Context) {
+			reresolved = append(reresolved, attempts)
+			hookCtx = ctx
+		}))
+
+	require.ErrorIs(t, err, errTestOperation, "Expected the error of the last attempt")
+	assert.Equal(t, []int{2}, reresolved, "Expected a single re-resolution, after the long delay")
+	assert.Equal(t, ctx, hookCtx, "Expected the hook to receive the context of the retry sequence")
+}
+
+func TestRetry_NoJitter(t *testing.T) {
+	t.Parallel()
+
+	var delays []time.Duration
+
+	err := retrier.Retry(context.Background(), func() error {
+		return errTestOperation
+	},
+		retrier.WithMaxRetries(4),
+		retrier.WithMinDelay(time.Millisecond),
+		retrier.WithMaxDelay(10*time.Millisecond),
+		retrier.WithBackoff(backoff.ExponentialWithFullJitter()),
+		retrier.WithNoJitter(),
+		retrier.WithStrict(),
+		retrier.WithNotifier(func(_ error, delay time.Duration) {
+			delays = append(delays, delay)
+		}))
+
+	require.ErrorIs(t, err, errTestOperation, "Expected the error of the last attempt")
+	assert.Equal(t, []time.Duration{time.Millisecond, 2 * time.Millisecond, 4 * time.Millisecond, 8 * time.Millisecond}, delays, "Expected the unjittered exponential delays")
+
+	composed := []retrier.Option{
+		retrier.WithBackoff(backoff.WithJitter(backoff.Linear(0), jitter.NewFull())),
+		retrier.WithBackoff(backoff.LinearWithFullJitter(time.Millisecond)),
+		retrier.WithBackoff(backoff.LinearWithEqualJitter(time.Millisecond)),
+		retrier.WithBackoff(backoff.ExponentialWithSymmetricJitter(0.5, jitter.WithCeiling(time.Second))),
+		retrier.WithBackoff(backoff.WithCap(backoff.ExponentialWithFullJitter(), time.Millisecond)),
+		retrier.WithStrategy(func(minDelay, maxDelay time.Duration) backoff.Strategy {
+			return backoff.DecorrelatedJitter(minDelay, maxDelay)
+		}),
+	}
+
+	for i, option := range composed {
+		delays = nil
+
+		err = retrier.Retry(context.Background(), func() error {
+			return errTestOperation
+		},
+			option,
+			retrier.WithMaxRetries(4),
+			retrier.WithMinDelay(time.Millisecond),
+			retrier.WithMaxDelay(time.Millisecond),
+			retrier.WithNoJitter(),
+			retrier.WithNotifier(func(_ error, delay time.Duration) {
+				delays = append(delays, delay)
+			}))
+
+		require.ErrorIs(t, err, errTestOperation, "Expected composed strategy %d to be stripped", i)
+		assert.Equal(t, []time.Duration{time.Millisecond, time.Millisecond, time.Millisecond, time.Millisecond}, delays, "Expected the unjittered delays of composed strategy %d", i)
+	}
+
+	_, err = retrier.NewValidated(
+		retrier.WithBackoff(func(_, maxDelay time.Duration, _ int) time.Duration {
+			return jitter.Full(maxDelay)
+		}),
+		retrier.WithNoJitter())
+
+	require.ErrorIs(t, err, retrier.ErrInvalidOption, "Expected a strategy that cannot be stripped to be rejected, even outside strict mode")
+}
+
+func TestRetry_Strategy(t *testing.T) {
+	t.Parallel()
+
+	var (
+		created int
+		delays  []time.Duration
+	)
+
+	newStrategy := func(minDelay, maxDelay time.Duration) backoff.Strategy {
+		created++
+
+		return backoff.NewStrategy(backoff.Exponential(), minDelay, maxDelay)
+	}
+
+	for range 2 {
+		err := retrier.Retry(context.Background(), func() error {
+			return errTestOperation
+		},
+			retrier.WithMaxRetries(3),
+			retrier.WithMinDelay(time.Millisecond),
+			retrier.WithMaxDelay(10*time.Millisecond),
+			retrier.WithBackoff(backoff.Linear(time.Hour)),
+			retrier.WithStrategy(newStrategy),
+			retrier.WithNotifier(func(_ error, delay time.Duration) {
+				delays = append(delays, delay)
+			}))
+
+		require.ErrorIs(t, err, errTestOperation, "Expected the error of the last attempt")
+	}
+
+	assert.Equal(t, 2, created, "Expected a strategy per retry sequence")
+	assert.Equal(t, []time.Duration{
+		time.Millisecond, 2 * time.Millisecond, 4 * time.Millisecond,
+		time.Millisecond, 2 * time.Millisecond, 4 * time.Millisecond,
+	}, delays, "Expected every retry sequence to start the strategy over")
+}
+
+func TestRetry_AuditWriter(t *testing.T) {
+	t.Parallel()
+
+	var (
+		log   bytes.Buffer
+		stats retrier.Stats
+	)
+
+	mockOp := &mockOperation{failureCount: 1}
+
+	err := retrier.Retry(context.Background(), mockOp.Operation,
+		retrier.WithMinDelay(time.Millisecond),
+		retrier.WithMaxDelay(time.Millisecond),
+		retrier.WithStats(&stats),
+		retrier.WithAuditWriter(&log, retrier.AuditFormatJSONL))
+
+	require.NoError(t, err, "Expected the operation to succeed")
+
+	lines := strings.Split(strings.TrimSpace(log.String()), "\n")
+
+	require.Len(t, lines, 2, "Expected a record per attempt")
+
+	var records [2]retrier.AuditRecord
+
+	for i, line := range lines {
+		require.NoError(t, json.Unmarshal([]byte(line), &records[i]), "Expected JSON lines")
+	}
+
+	assert.Equal(t, stats.SequenceID, records[0].SequenceID, "Expected the sequence ID")
+	assert.Equal(t, "transient_failure", records[0].Outcome, "Expected the failure to be recorded")
+	assert.Equal(t, time.Millisecond, records[0].Delay, "Expected the delay before the retry")
+	assert.Equal(t, 1, records[1].Attempt, "Expected the attempt number")
+	assert.Equal(t, "success", records[1].Outcome, "Expected the success to be recorded")
+	assert.Zero(t, records[1].Delay, "Expected no delay after the last attempt")
+
+	log.Reset()
+
+	_ = retrier.Retry(context.Background(), func() error {
+		return errTestOperation
+	},
+		retrier.WithMaxRetries(1),
+		retrier.WithAuditWriter(&log, retrier.AuditFormatLogfmt))
+
+	assert.Contains(t, log.String(), `attempt=0 outcome=transient_failure error="operation failed" delay=0s`, "Expected a logfmt record")
+}
+
+func TestRetry_MaxElapsedTime(t *testing.T) {
+	t.Parallel()
+
+	var calls int
+
+	err := retrier.Retry(context.Background(), func() error {
+		calls++
+
+		return errTestOperation
+	},
+		retrier.WithMaxRetries(-1),
+		retrier.WithMinDelay(10*time.Millisecond),
+		retrier.WithMaxDelay(10*time.Millisecond),
+		retrier.WithMaxElapsedTime(35*time.Millisecond))
+
+	require.ErrorIs(t, err, errTestOperation, "Expected the error of the last attempt")
+	assert.GreaterOrEqual(t, calls, 2, "Expected the retry sequence to retry within the maximum elapsed time")
+	assert.LessOrEqual(t, calls, 4, "Expected the retry sequence to stop retrying")
+}
+
+func TestRetryCtx(t *testing.T) {
+	t.Parallel()
+
+	type key struct{}
+
+	ctx := context.WithValue(context.Background(), key{}, "value")
+
+	calls := 0
+
+	err := retrier.RetryCtx(ctx, func(ctx context.Context) error {
+		calls++
+
+		assert.Equal(t, "value", ctx.Value(key{}), "Expected the operation to receive a context derived from the retry sequence's")
+
+		if calls < 2 {
+			return errTestOperation
+		}
+
+		return nil
+	}, retrier.WithMinDelay(time.Millisecond))
+
+	require.NoError(t, err, "Expected the operation to succeed")
+	assert.Equal(t, 2, calls, "Expected 2 attempts")
+
+	result, err := retrier.RetryCtxWithData(ctx, func(context.Context) (int, error) {
+		return 42, nil
+	})
+
+	require.NoError(t, err, "Expected the operation to succeed")
+	assert.Equal(t, 42, result, "Expected the result of the operation")
+
+	// An abandoned attempt sees its context canceled, with ErrAttemptAbandoned as the cause.
+	causes := make(chan error, 1)
+
+	err = retrier.RetryCtx(ctx, func(ctx context.Context) error {
+		<-ctx.Done()
+
+		causes <- context.Cause(ctx)
+
+		return ctx.Err()
+	},
+		retrier.WithMaxRetries(1),
+		retrier.WithAbandonAfter(time.Millisecond))
+
+	require.ErrorIs(t, err, retrier.ErrAttemptAbandoned, "Expected the attempt to be abandoned")
+	assert.ErrorIs(t, <-causes, retrier.ErrAttemptAbandoned, "Expected the attempt's context to be canceled once abandoned")
+}
+
+func TestAttemptFromContext(t *testing.T) {
+	t.Parallel()
+
+	var metas []retrier.AttemptMeta
+
+	err := retrier.RetryCtx(context.Background(), func(ctx context.Context) error {
+		meta, ok := retrier.AttemptFromContext(ctx)
+
+		require.True(t, ok, "Expected the context to carry the metadata of the attempt")
+
+		metas = append(metas, meta)
+
+		if meta.Number < 2 {
+			return fmt.Errorf("attempt %d: %w", meta.Number, errTestOperation)
+		}
+
+		return nil
+	}, retrier.WithMinDelay(time.Millisecond), retrier.WithMaxDelay(time.Millisecond))
+
+	require.NoError(t, err, "Expected the operation to succeed")
+	require.Len(t, metas, 3, "Expected 3 attempts")
+
+	for i, meta := range metas {
+		assert.Equal(t, i, meta.Number, "Expected the number of the attempt")
+	}
+
+	require.NoError(t, metas[0].PreviousErr, "Expected no previous error for the first attempt")
+	assert.EqualError(t, metas[2].PreviousErr, "attempt 1: operation failed", "Expected the error of the previous attempt")
+	assert.Greater(t, metas[2].Elapsed, metas[1].Elapsed, "Expected the elapsed time to grow")
+
+	_, ok := retrier.AttemptFromContext(context.Background())
+
+	assert.False(t, ok, "Expected a context outside a retry sequence to carry no metadata")
+}
+
+func TestRetry_ErrorAggregation(t *testing.T) {
+	t.Parallel()
+
+	errFirst := errors.New("first failure")
+
+	calls := 0
+
+	err := retrier.Retry(context.Background(), func() error {
+		calls++
+
+		if calls == 1 {
+			return errFirst
+		}
+
+		return errTestOperation
+	},
+		retrier.WithMinDelay(time.Millisecond),
+		retrier.WithMaxDelay(time.Millisecond),
+		retrier.WithErrorAggregation())
+
+	var retryErr *retrier.Error
+
+	require.ErrorAs(t, err, &retryErr, "Expected an aggregated error")
+	assert.Equal(t, 3, retryErr.Attempts, "Expected the number of attempts")
+	assert.Positive(t, retryErr.Elapsed, "Expected the elapsed time")
+	require.ErrorIs(t, err, errFirst, "Expected the error of the first attempt to be kept")
+	require.ErrorIs(t, err, errTestOperation, "Expected the error of the last attempt to be kept")
+	assert.Equal(t, 2, strings.Count(err.Error(), errTestOperation.Error()), "Expected the error of every attempt once")
+
+	var stats retrier.Stats
+
+	err = retrier.Retry(context.Background(), func() error {
+		return retrier.Permanent(errTestOperation)
+	}, retrier.WithErrorAggregation(), retrier.WithStats(&stats))
+
+	require.ErrorAs(t, err, &retryErr, "Expected an aggregated error")
+	assert.Equal(t, stats.SequenceID, retryErr.SequenceID, "Expected the sequence ID")
+	assert.Equal(t, "retry sequence "+stats.SequenceID+": 1 attempts failed in "+retryErr.Elapsed.String()+": operation failed", err.Error(), "Expected the sequence ID and the permanent failure once")
+
+	require.NoError(t, retrier.Retry(context.Background(), func() error {
+		return nil
+	}, retrier.WithErrorAggregation()), "Expected a success to return no error")
+}
+
+func TestRetry_RetryError(t *testing.T) {
+	t.Parallel()
+
+	var stats retrier.Stats
+
+	err := retrier.Retry(context.Background(), func() error {
+		return errTestOperation
+	},
+		retrier.WithMaxRetries(2),
+		retrier.WithMinDelay(time.Millisecond),
+		retrier.WithMaxDelay(time.Millisecond),
+		retrier.WithStats(&stats))
+
+	var retryErr *retrier.RetryError
+
+	require.ErrorAs(t, err, &retryErr, "Expected a *RetryError once the attempts are exhausted")
+	assert.Equal(t, 2, retryErr.Attempts, "Expected the number of attempts")
+	assert.GreaterOrEqual(t, retryErr.TotalDelay, time.Millisecond, "Expected the total delay")
+	assert.GreaterOrEqual(t, retryErr.Elapsed, retryErr.TotalDelay, "Expected the elapsed time")
+	require.ErrorIs(t, retryErr.LastErr, errTestOperation, "Expected the last error")
+	assert.Equal(t, stats.SequenceID, retryErr.SequenceID, "Expected the sequence ID")
+	assert.Equal(t, "retry sequence "+stats.SequenceID+": "+errTestOperation.Error(), err.Error(), "Expected the sequence ID and the message of the last error")
+
+	err = retrier.Retry(context.Background(), func() error {
+		return retrier.Permanent(errTestOperation)
+	})
+
+	assert.NotErrorAs(t, err, &retryErr, "Expected a permanent failure not to exhaust the attempts")
+}
+
+func TestRetry_RetryError_MaxElapsedTime(t *testing.T) {
+	t.Parallel()
+
+	err := retrier.Retry(context.Background(), func() error {
+		return errTestOperation
+	},
+		retrier.WithMaxRetries(100),
+		retrier.WithMinDelay(20*time.Millisecond),
+		retrier.WithMaxDelay(20*time.Millisecond),
+		retrier.WithMaxElapsedTime(50*time.Millisecond))
+
+	var retryErr *retrier.RetryError
+
+	require.ErrorAs(t, err, &retryErr, "Expected a *RetryError once the elapsed time is exhausted")
+	assert.Less(t, retryErr.Attempts, 100, "Expected the retry sequence to give up before running out of attempts")
+	require.ErrorIs(t, retryErr.LastErr, errTestOperation, "Expected the last error")
+}
+
+func TestRetry_RetryError_SLO(t *testing.T) {
+	t.Parallel()
+
+	err := retrier.Retry(context.Background(), func() error {
+		time.Sleep(10 * time.Millisecond)
+
+		return errTestOperation
+	},
+		retrier.WithMaxRetries(100),
+		retrier.WithMinDelay(20*time.Millisecond),
+		retrier.WithMaxDelay(20*time.Millisecond),
+		retrier.WithSLO(60*time.Millisecond))
+
+	var retryErr *retrier.RetryError
+
+	require.ErrorAs(t, err, &retryErr, "Expected a *RetryError once the SLO cannot be met")
+	assert.Less(t, retryErr.Attempts, 100, "Expected the retry sequence to give up before running out of attempts")
+	require.ErrorIs(t, retryErr.LastErr, errTestOperation, "Expected the last error")
+}
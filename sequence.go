@@ -0,0 +1,44 @@
+package retrier
+
+import (
+	"crypto/rand"
+	"encoding/binary"
+	"encoding/hex"
+	"time"
+)
+
+// newSequenceID generates the identifier of a retry sequence, a UUID version 7: its first 48 bits are
+// the Unix time in milliseconds, so identifiers sort by creation time, and the rest is random.
+//
+// Returns:
+//   - id: The identifier, in the canonical 8-4-4-4-12 hexadecimal form.
+func newSequenceID() (id string) {
+	var uuid [16]byte
+
+	_, _ = rand.Read(uuid[6:])
+
+	var timestamp [8]byte
+
+	binary.BigEndian.PutUint64(timestamp[:], uint64(time.Now().UnixMilli())) //nolint:gosec // The Unix time is positive.
+
+	copy(uuid[:6], timestamp[2:])
+
+	uuid[6] = uuid[6]&0x0f | 0x70 // Version 7.
+	uuid[8] = uuid[8]&0x3f | 0x80 // RFC 9562 variant.
+
+	var encoded [36]byte
+
+	hex.Encode(encoded[0:8], uuid[0:4])
+	encoded[8] = '-'
+	hex.Encode(encoded[9:13], uuid[4:6])
+	encoded[13] = '-'
+	hex.Encode(encoded[14:18], uuid[6:8])
+	encoded[18] = '-'
+	hex.Encode(encoded[19:23], uuid[8:10])
+	encoded[23] = '-'
+	hex.Encode(encoded[24:], uuid[10:])
+
+	id = string(encoded[:])
+
+	return
+}
@@ -0,0 +1,41 @@
+package retrier
+
+import (
+	"context"
+	"time"
+
+	"go.source.hueristiq.com/retrier/jitter"
+)
+
+// Simple is a one-line facade for casual use: it attempts an operation up to attempts times, waiting a
+// fully jittered constant delay, between 0 and delay, between attempts. It runs on the same engine as
+// Retry, so that the options carried by ctx through ContextWithOptions, such as a shared budget, apply.
+//
+// Parameters:
+//   - ctx:       A context to control the lifetime of the retry sequence.
+//   - attempts:  The maximum number of attempts. It is raised to 1 if it is lower.
+//   - delay:     The maximum delay between attempts.
+//   - operation: The operation to be retried.
+//
+// Returns:
+//   - err: The error of the retry sequence, as returned by Retry.
+//
+// Example:
+//
+//	err := retrier.Simple(ctx, 3, time.Second, operation)
+func Simple(ctx context.Context, attempts int, delay time.Duration, operation func() error) (err error) {
+	opts := append([]Option{
+		WithMaxRetries(max(attempts, 1)),
+		WithMinDelay(delay),
+		WithMaxDelay(delay),
+		WithBackoff(func(minDelay, _ time.Duration, _ int) (backoff time.Duration) {
+			backoff = jitter.Full(minDelay, jitter.WithSource(jitter.FastSource()))
+
+			return
+		}),
+	}, OptionsFromContext(ctx)...)
+
+	err = Retry(ctx, operation, opts...)
+
+	return
+}
@@ -0,0 +1,85 @@
+package retrier
+
+import (
+	"runtime"
+	"runtime/debug"
+	"runtime/metrics"
+	"time"
+)
+
+// heapMetric is the runtime metric of the memory occupied by live and not yet swept heap objects.
+const heapMetric = "/memory/classes/heap/objects:bytes"
+
+// RuntimeSnapshot is a lightweight snapshot of the state of the Go runtime, captured when a retry
+// sequence gives up to help tell dependency outages from local resource exhaustion.
+//
+// Fields:
+//   - Goroutines:  The number of goroutines.
+//   - HeapBytes:   The bytes occupied by heap objects.
+//   - NumGC:       The number of completed garbage collections.
+//   - LastGCPause: The duration of the last garbage collection pause.
+type RuntimeSnapshot struct {
+	Goroutines  int
+	HeapBytes   uint64
+	NumGC       int64
+	LastGCPause time.Duration
+}
+
+// captureRuntime captures a RuntimeSnapshot without stopping the world.
+//
+// Returns:
+//   - snapshot: The RuntimeSnapshot.
+func captureRuntime() (snapshot RuntimeSnapshot) {
+	snapshot.Goroutines = runtime.NumGoroutine()
+
+	samples := []metrics.Sample{{Name: heapMetric}}
+
+	metrics.Read(samples)
+
+	if samples[0].Value.Kind() == metrics.KindUint64 {
+		snapshot.HeapBytes = samples[0].Value.Uint64()
+	}
+
+	var gc debug.GCStats
+
+	debug.ReadGCStats(&gc)
+
+	snapshot.NumGC = gc.NumGC
+
+	if len(gc.Pause) > 0 {
+		snapshot.LastGCPause = gc.Pause[0]
+	}
+
+	return
+}
+
+// SnapshotError is the error of a retry sequence that gave up with WithRuntimeSnapshot, carrying the
+// RuntimeSnapshot captured when it gave up.
+//
+// Fields:
+//   - Err:      The error the retry sequence gave up with.
+//   - Snapshot: The RuntimeSnapshot captured when the retry sequence gave up.
+type SnapshotError struct {
+	Err      error
+	Snapshot RuntimeSnapshot
+}
+
+// Error implements the error interface by returning the wrapped error's message.
+//
+// Returns:
+//   - message: The wrapped error's message.
+func (e *SnapshotError) Error() (message string) {
+	message = e.Err.Error()
+
+	return
+}
+
+// Unwrap returns the error the retry sequence gave up with.
+//
+// Returns:
+//   - err: The wrapped error.
+func (e *SnapshotError) Unwrap() (err error) {
+	err = e.Err
+
+	return
+}
@@ -0,0 +1,10 @@
+// Package sqlretrier provides database/sql helpers retrying on serialization failures and deadlocks.
+//
+// Databases running transactions under strict isolation abort some of them with errors that go away
+// if the transaction is run again: serialization failures and deadlocks, or busy and locked databases
+// for SQLite. ExecContext, QueryContext, and WithinTransaction retry on these errors, recognized by
+// IsRetryable for Postgres, MySQL, and SQLite without depending on their drivers, and give up
+// immediately on any other error. WithinTransaction rolls the failed transaction back before retrying
+// it from the start. The retry options of the helpers are those carried by the context, set through
+// retrier.ContextWithOptions, overridden by the options passed to the call.
+package sqlretrier
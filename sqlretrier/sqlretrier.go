@@ -0,0 +1,269 @@
+package sqlretrier
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+
+	"go.source.hueristiq.com/retrier"
+)
+
+// IsRetryable reports whether an error is a serialization failure or a deadlock of Postgres, MySQL,
+// or SQLite, after which running the statement or transaction again can succeed. It is the classifier
+// of the helpers of the package, which can be replaced, e.g., with a single database's classifier,
+// through retrier.WithRetryIf.
+//
+// Parameters:
+//   - err: The error of the statement or transaction.
+//
+// Returns:
+//   - retryable: Whether running the statement or transaction again can succeed.
+func IsRetryable(err error) (retryable bool) {
+	retryable = IsPostgresRetryable(err) || IsMySQLRetryable(err) || IsSQLiteRetryable(err)
+
+	return
+}
+
+// IsPostgresRetryable reports whether an error is a Postgres serialization failure (SQLSTATE 40001)
+// or deadlock (SQLSTATE 40P01). The SQLSTATE is read through the SQLState() string method of the
+// errors of the pgx and lib/pq drivers.
+//
+// Parameters:
+//   - err: The error of the statement or transaction.
+//
+// Returns:
+//   - retryable: Whether err is a serialization failure or a deadlock.
+func IsPostgresRetryable(err error) (retryable bool) {
+	var coded interface{ SQLState() string }
+
+	if errors.As(err, &coded) {
+		state := coded.SQLState()
+
+		retryable = state == "40001" || state == "40P01"
+	}
+
+	return
+}
+
+// IsMySQLRetryable reports whether an error is a MySQL deadlock (error 1213) or lock wait timeout
+// (error 1205). As the errors of the go-sql-driver/mysql driver only expose their number as a field,
+// it is read from their message, e.g., "Error 1213 (40001): Deadlock found".
+//
+// Parameters:
+//   - err: The error of the statement or transaction.
+//
+// Returns:
+//   - retryable: Whether err is a deadlock or a lock wait timeout.
+func IsMySQLRetryable(err error) (retryable bool) {
+	if err == nil {
+		return
+	}
+
+	message := err.Error()
+
+	retryable = strings.Contains(message, "Error 1213") || strings.Contains(message, "Error 1205")
+
+	return
+}
+
+// IsSQLiteRetryable reports whether an error is a SQLite SQLITE_BUSY or SQLITE_LOCKED error. The result
+// code is read through the Code() int method of the errors of the modernc.org/sqlite driver, and from
+// the message of the errors of the mattn/go-sqlite3 driver, which only expose it as a field.
+//
+// Parameters:
+//   - err: The error of the statement or transaction.
+//
+// Returns:
+//   - retryable: Whether the database was busy or locked.
+func IsSQLiteRetryable(err error) (retryable bool) {
+	const (
+		busy   = 5
+		locked = 6
+	)
+
+	var coded interface{ Code() int }
+
+	if errors.As(err, &coded) {
+		code := coded.Code() & 0xff
+
+		retryable = code == busy || code == locked
+
+		return
+	}
+
+	if err != nil {
+		message := err.Error()
+
+		retryable = strings.Contains(message, "database is locked") || strings.Contains(message, "database table is locked")
+	}
+
+	return
+}
+
+// ExecContext executes a statement, retrying it on the errors IsRetryable recognizes. It must not be
+// used for statements of a transaction, which a failure aborts as a whole; WithinTransaction retries
+// the transaction instead. Statements other than reads, see Idempotent, are retried as non-idempotent
+// operations, see retrier.WithIdempotent, and network errors are classified with
+// retrier.ClassifyNetworkError, so that a write whose outcome is unknown is not replayed.
+//
+// Parameters:
+//   - ctx:   A context to control the lifetime of the retries.
+//   - db:    The database.
+//   - query: The statement.
+//   - args:  The arguments of the statement.
+//   - opts:  The retry options, applied after the ones carried by ctx.
+//
+// Returns:
+//   - result: The result of the statement.
+//   - err:    The first non-retryable error, the error of the last attempt, or the context's error.
+//
+// Example:
+//
+//	result, err := sqlretrier.ExecContext(ctx, db, "UPDATE jobs SET state = $1 WHERE id = $2", []any{state, id}, retrier.WithMaxRetries(5))
+func ExecContext(ctx context.Context, db *sql.DB, query string, args []any, opts ...retrier.Option) (result sql.Result, err error) {
+	result, err = retrier.RetryCtxWithData(ctx, func(ctx context.Context) (result sql.Result, err error) {
+		result, err = db.ExecContext(ctx, query, args...)
+
+		err = retrier.ClassifyNetworkError(err)
+
+		return
+	}, options(ctx, opts, retrier.WithIdempotent(Idempotent(query)))...)
+
+	return
+}
+
+// QueryContext executes a query, retrying it on the errors IsRetryable recognizes. Only the execution
+// of the query is retried: errors reading the rows are returned by the rows. Like ExecContext, it
+// retries statements other than reads, e.g., an INSERT with a RETURNING clause, as non-idempotent
+// operations.
+//
+// Parameters:
+//   - ctx:   A context to control the lifetime of the retries.
+//   - db:    The database.
+//   - query: The query.
+//   - args:  The arguments of the query.
+//   - opts:  The retry options, applied after the ones carried by ctx.
+//
+// Returns:
+//   - rows: The rows of the query.
+//   - err:  The first non-retryable error, the error of the last attempt, or the context's error.
+//
+// Example:
+//
+//	rows, err := sqlretrier.QueryContext(ctx, db, "SELECT id FROM jobs WHERE state = $1", []any{state}, retrier.WithMaxRetries(5))
+func QueryContext(ctx context.Context, db *sql.DB, query string, args []any, opts ...retrier.Option) (rows *sql.Rows, err error) {
+	rows, err = retrier.RetryCtxWithData(ctx, func(ctx context.Context) (rows *sql.Rows, err error) {
+		rows, err = db.QueryContext(ctx, query, args...)
+
+		err = retrier.ClassifyNetworkError(err)
+
+		return
+	}, options(ctx, opts, retrier.WithIdempotent(Idempotent(query)))...)
+
+	return
+}
+
+// WithinTransaction runs a function within a transaction, and commits it, retrying the whole
+// transaction on the errors IsRetryable recognizes, from fn or from the commit. A failed transaction
+// is rolled back before the next attempt begins a new one, including when fn panics, so that fn always
+// starts from a clean state. fn may run several times, and must not have side effects outside of the
+// transaction.
+//
+// Parameters:
+//   - ctx:  A context to control the lifetime of the retries.
+//   - db:   The database.
+//   - fn:   The function running the statements of the transaction.
+//   - opts: The retry options, applied after the ones carried by ctx.
+//
+// Returns:
+//   - err: The first non-retryable error, the error of the last attempt, or the context's error,
+//     joined with the error of the rollback if it failed.
+//
+// Example:
+//
+//	err := sqlretrier.WithinTransaction(ctx, db, func(ctx context.Context, tx *sql.Tx) error {
+//	    _, err := tx.ExecContext(ctx, "UPDATE accounts SET balance = balance - $1 WHERE id = $2", amount, id)
+//
+//	    return err
+//	}, retrier.WithMaxRetries(5))
+func WithinTransaction(ctx context.Context, db *sql.DB, fn func(ctx context.Context, tx *sql.Tx) error, opts ...retrier.Option) (err error) {
+	err = retrier.RetryCtx(ctx, func(ctx context.Context) (err error) {
+		tx, err := db.BeginTx(ctx, nil)
+		if err != nil {
+			return
+		}
+
+		committed := false
+
+		defer func() {
+			if committed {
+				return
+			}
+
+			if rollback := tx.Rollback(); rollback != nil && !errors.Is(rollback, sql.ErrTxDone) {
+				err = errors.Join(err, rollback)
+			}
+		}()
+
+		if err = fn(ctx, tx); err != nil {
+			return
+		}
+
+		err, committed = tx.Commit(), true
+
+		return
+	}, options(ctx, opts)...)
+
+	return
+}
+
+// Idempotent reports whether a statement only reads, i.e., starts with SELECT, SHOW, EXPLAIN, or
+// DESCRIBE, and can therefore be replayed after a failure whose outcome is unknown. Other statements,
+// including those starting with WITH, which may modify data, are considered writes.
+//
+// Parameters:
+//   - query: The statement.
+//
+// Returns:
+//   - idempotent: Whether the statement only reads.
+//
+// Example:
+//
+//	sqlretrier.Idempotent("SELECT * FROM jobs") is true, sqlretrier.Idempotent("DELETE FROM jobs") is false.
+func Idempotent(query string) (idempotent bool) {
+	fields := strings.Fields(strings.TrimLeft(query, "( \t\r\n"))
+	if len(fields) == 0 {
+		return
+	}
+
+	switch strings.ToUpper(fields[0]) {
+	case "SELECT", "SHOW", "EXPLAIN", "DESCRIBE":
+		idempotent = true
+	}
+
+	return
+}
+
+// options returns the retry options of a helper: retrying on the errors IsRetryable recognizes and
+// the defaults of the helper, followed by the options carried by ctx and the options of the call.
+//
+// Parameters:
+//   - ctx:      The context carrying retry options.
+//   - opts:     The options of the call.
+//   - defaults: The default options of the helper.
+//
+// Returns:
+//   - all: The retry options.
+func options(ctx context.Context, opts []retrier.Option, defaults ...retrier.Option) (all []retrier.Option) {
+	carried := retrier.OptionsFromContext(ctx)
+
+	all = make([]retrier.Option, 0, 1+len(defaults)+len(carried)+len(opts))
+
+	all = append(all, retrier.WithRetryIf(IsRetryable))
+	all = append(all, defaults...)
+	all = append(all, carried...)
+	all = append(all, opts...)
+
+	return
+}
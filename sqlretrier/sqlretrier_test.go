@@ -0,0 +1,337 @@
+package sqlretrier_test
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.source.hueristiq.com/retrier"
+	"go.source.hueristiq.com/retrier/sqlretrier"
+)
+
+type stateError string
+
+func (e stateError) Error() string {
+	return "SQLSTATE " + string(e)
+}
+
+func (e stateError) SQLState() string {
+	return string(e)
+}
+
+type codeError int
+
+func (e codeError) Error() string {
+	return fmt.Sprintf("sqlite error %d", int(e))
+}
+
+func (e codeError) Code() int {
+	return int(e)
+}
+
+// fakeDB is a database/sql driver failing its statements with the errors of failures, in turn.
+type fakeDB struct {
+	mutex     sync.Mutex
+	failures  []error
+	begun     int
+	committed int
+	rolled    int
+}
+
+func (d *fakeDB) fail() (err error) {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	if len(d.failures) > 0 {
+		err, d.failures = d.failures[0], d.failures[1:]
+	}
+
+	return
+}
+
+func (d *fakeDB) Open(string) (driver.Conn, error) {
+	return &fakeConn{db: d}, nil
+}
+
+type fakeConn struct {
+	db *fakeDB
+}
+
+func (c *fakeConn) Prepare(string) (driver.Stmt, error) {
+	return &fakeStmt{db: c.db}, nil
+}
+
+func (c *fakeConn) Close() error {
+	return nil
+}
+
+func (c *fakeConn) Begin() (driver.Tx, error) {
+	c.db.mutex.Lock()
+	defer c.db.mutex.Unlock()
+
+	c.db.begun++
+
+	return &fakeTx{db: c.db}, nil
+}
+
+type fakeTx struct {
+	db *fakeDB
+}
+
+func (t *fakeTx) Commit() error {
+	t.db.mutex.Lock()
+	defer t.db.mutex.Unlock()
+
+	t.db.committed++
+
+	return nil
+}
+
+func (t *fakeTx) Rollback() error {
+	t.db.mutex.Lock()
+	defer t.db.mutex.Unlock()
+
+	t.db.rolled++
+
+	return nil
+}
+
+type fakeStmt struct {
+	db *fakeDB
+}
+
+func (s *fakeStmt) Close() error {
+	return nil
+}
+
+func (s *fakeStmt) NumInput() int {
+	return -1
+}
+
+func (s *fakeStmt) Exec([]driver.Value) (driver.Result, error) {
+	if err := s.db.fail(); err != nil {
+		return nil, err
+	}
+
+	return driver.RowsAffected(1), nil
+}
+
+func (s *fakeStmt) Query([]driver.Value) (driver.Rows, error) {
+	if err := s.db.fail(); err != nil {
+		return nil, err
+	}
+
+	return &fakeRows{}, nil
+}
+
+type fakeRows struct {
+	done bool
+}
+
+func (r *fakeRows) Columns() []string {
+	return []string{"n"}
+}
+
+func (r *fakeRows) Close() error {
+	return nil
+}
+
+func (r *fakeRows) Next(dest []driver.Value) error {
+	if r.done {
+		return io.EOF
+	}
+
+	r.done, dest[0] = true, int64(1)
+
+	return nil
+}
+
+var registered atomic.Int64
+
+func open(t *testing.T, failures ...error) (db *sql.DB, fake *fakeDB) {
+	t.Helper()
+
+	fake = &fakeDB{failures: failures}
+
+	name := fmt.Sprintf("sqlretrier-fake-%d", registered.Add(1))
+
+	sql.Register(name, fake)
+
+	db, err := sql.Open(name, "")
+
+	require.NoError(t, err)
+
+	t.Cleanup(func() {
+		_ = db.Close()
+	})
+
+	return
+}
+
+func fast() (ctx context.Context) {
+	ctx = retrier.ContextWithOptions(context.Background(), retrier.WithMinDelay(time.Millisecond), retrier.WithMaxDelay(time.Millisecond))
+
+	return
+}
+
+func TestIsRetryable(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name      string
+		err       error
+		retryable bool
+	}{
+		{"postgres serialization failure", stateError("40001"), true},
+		{"postgres deadlock", fmt.Errorf("update: %w", stateError("40P01")), true},
+		{"postgres unique violation", stateError("23505"), false},
+		{"mysql deadlock", errors.New("Error 1213 (40001): Deadlock found when trying to get lock"), true},
+		{"mysql lock wait timeout", errors.New("Error 1205: Lock wait timeout exceeded"), true},
+		{"mysql duplicate entry", errors.New("Error 1062 (23000): Duplicate entry"), false},
+		{"sqlite busy", codeError(5), true},
+		{"sqlite extended locked", codeError(6 | 1<<8), true},
+		{"sqlite constraint", codeError(19), false},
+		{"sqlite message", errors.New("database is locked"), true},
+		{"nil", nil, false},
+	}
+
+	for _, test := range tests {
+		assert.Equal(t, test.retryable, sqlretrier.IsRetryable(test.err), test.name)
+	}
+}
+
+func TestExecContext(t *testing.T) {
+	t.Parallel()
+
+	db, _ := open(t, stateError("40001"), stateError("40P01"))
+
+	result, err := sqlretrier.ExecContext(fast(), db, "UPDATE t SET n = n + 1", nil)
+
+	require.NoError(t, err)
+
+	affected, err := result.RowsAffected()
+
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), affected)
+
+	db, _ = open(t, stateError("23505"), stateError("40001"))
+
+	_, err = sqlretrier.ExecContext(fast(), db, "INSERT INTO t VALUES (?)", []any{1})
+
+	require.ErrorIs(t, err, stateError("23505"), "non-retryable errors are not retried")
+
+	db, _ = open(t, stateError("40001"), stateError("40001"))
+
+	_, err = sqlretrier.ExecContext(fast(), db, "UPDATE t SET n = n + 1", nil, retrier.WithMaxRetries(2))
+
+	assert.ErrorIs(t, err, stateError("40001"), "the options of the call override the ones carried by the context")
+}
+
+func TestExecContext_NonIdempotent(t *testing.T) {
+	t.Parallel()
+
+	reset := &net.OpError{Op: "read", Net: "tcp", Err: errors.New("connection reset by peer")}
+	retryAll := retrier.WithRetryIf(func(error) bool { return true })
+
+	db, _ := open(t, reset, reset)
+
+	_, err := sqlretrier.ExecContext(fast(), db, "INSERT INTO t VALUES (1)", nil, retryAll)
+
+	require.True(t, retrier.IsAmbiguous(err), "Expected the network error of a write to be ambiguous")
+
+	_, err = sqlretrier.ExecContext(fast(), db, "INSERT INTO t VALUES (1)", nil, retryAll)
+
+	require.ErrorIs(t, err, reset, "Expected the first write to make a single attempt, leaving the second failure")
+
+	db, _ = open(t, reset, reset)
+
+	rows, err := sqlretrier.QueryContext(fast(), db, "SELECT n FROM t", nil, retryAll)
+
+	require.NoError(t, err, "Expected a read to be replayed after an ambiguous failure")
+	require.NoError(t, rows.Close())
+}
+
+func TestIdempotent(t *testing.T) {
+	t.Parallel()
+
+	assert.True(t, sqlretrier.Idempotent("SELECT n FROM t"))
+	assert.True(t, sqlretrier.Idempotent("  (select n FROM t)"))
+	assert.True(t, sqlretrier.Idempotent("EXPLAIN SELECT n FROM t"))
+	assert.False(t, sqlretrier.Idempotent("INSERT INTO t VALUES (1)"))
+	assert.False(t, sqlretrier.Idempotent("WITH d AS (DELETE FROM t RETURNING n) SELECT n FROM d"))
+	assert.False(t, sqlretrier.Idempotent(""))
+}
+
+func TestQueryContext(t *testing.T) {
+	t.Parallel()
+
+	db, _ := open(t, codeError(5))
+
+	rows, err := sqlretrier.QueryContext(fast(), db, "SELECT n FROM t", nil)
+
+	require.NoError(t, err)
+
+	defer rows.Close()
+
+	var n int
+
+	require.True(t, rows.Next())
+	require.NoError(t, rows.Scan(&n))
+	require.NoError(t, rows.Err())
+	assert.Equal(t, 1, n)
+}
+
+func TestWithinTransaction(t *testing.T) {
+	t.Parallel()
+
+	db, fake := open(t, stateError("40001"), stateError("40001"))
+
+	calls := 0
+
+	err := sqlretrier.WithinTransaction(context.Background(), db, func(ctx context.Context, tx *sql.Tx) error {
+		calls++
+
+		_, err := tx.ExecContext(ctx, "UPDATE t SET n = n + 1")
+
+		return err
+	}, retrier.WithMinDelay(time.Millisecond), retrier.WithMaxDelay(time.Millisecond))
+
+	require.NoError(t, err)
+	assert.Equal(t, 3, calls)
+	assert.Equal(t, 3, fake.begun)
+	assert.Equal(t, 2, fake.rolled, "failed transactions are rolled back before retrying")
+	assert.Equal(t, 1, fake.committed)
+
+	db, fake = open(t)
+
+	failure := errors.New("insufficient funds")
+
+	err = sqlretrier.WithinTransaction(context.Background(), db, func(context.Context, *sql.Tx) error {
+		return failure
+	})
+
+	require.ErrorIs(t, err, failure)
+	assert.Equal(t, 1, fake.begun, "non-retryable errors are not retried")
+	assert.Equal(t, 1, fake.rolled)
+	assert.Equal(t, 0, fake.committed)
+
+	db, fake = open(t)
+
+	assert.Panics(t, func() {
+		_ = sqlretrier.WithinTransaction(context.Background(), db, func(context.Context, *sql.Tx) error {
+			panic("boom")
+		})
+	})
+
+	assert.Equal(t, 1, fake.rolled, "a panicking transaction is rolled back")
+}
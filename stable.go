@@ -0,0 +1,65 @@
+package retrier
+
+import (
+	"context"
+	"errors"
+)
+
+// ErrNotStable is the error of an attempt of RetryUntilStable whose result has not been observed for
+// enough consecutive attempts yet.
+var ErrNotStable = errors.New("result not stable")
+
+// RetryUntilStable retries an operation until it returns the same result for stableFor consecutive
+// attempts, e.g., to poll an eventually consistent system until its replicas converge. Failed attempts
+// are retried as usual and restart the count.
+//
+// Attempts must not run concurrently, so WithAbandonAfter must not be used.
+//
+// Parameters:
+//   - ctx:       A context to control the lifetime of the retry sequence.
+//   - operation: The operation to poll.
+//   - equals:    The function reporting whether two results are the same. It must not be nil.
+//   - stableFor: The number of consecutive attempts that must return the same result. Values lower than
+//     1 accept the first successful result.
+//   - opts:      Optional configuration options.
+//
+// Returns:
+//   - result: The stable result, or the last result observed if the result did not stabilize.
+//   - err:    ErrNotStable if the result did not stabilize before the retry sequence
+//     gave up, the error of the last attempt, or the context's error.
+//
+// Example:
+//
+//	version, err := retrier.RetryUntilStable(ctx, readVersion, func(a, b int) bool { return a == b }, 3)
+//	// Returns once three consecutive reads returned the same version.
+func RetryUntilStable[T any](ctx context.Context, operation OperationWithData[T], equals func(a, b T) bool, stableFor int, opts ...Option) (result T, err error) {
+	var (
+		last   T
+		streak int
+	)
+
+	result, err = RetryWithData(ctx, func() (result T, err error) {
+		result, err = operation()
+		if err != nil {
+			streak = 0
+
+			return
+		}
+
+		if streak > 0 && equals(last, result) {
+			streak++
+		} else {
+			streak = 1
+		}
+
+		last = result
+
+		if streak < stableFor {
+			err = ErrNotStable
+		}
+
+		return
+	}, opts...)
+
+	return
+}
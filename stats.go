@@ -0,0 +1,134 @@
+package retrier
+
+import "time"
+
+// Stats holds statistics about a completed retry sequence. It is populated through WithStats when
+// the retry sequence ends, whether it succeeded, gave up, or was stopped by its context.
+//
+// Fields:
+//   - SequenceID: The unique identifier of the retry sequence, shared with its Attempts.
+//   - Attempts: The number of times the operation was executed.
+//   - TotalDelay: The cumulative time spent waiting between attempts.
+//   - Elapsed: The wall-clock time of the whole retry sequence.
+//   - SLO: The target configured through WithSLO, or 0 if none is configured.
+//   - SLOMet: Whether the retry sequence succeeded within the SLO. Always false if no SLO is configured.
+//   - Errors: The errors of the failed attempts, in attempt order, retained according to WithErrorRetention.
+//   - Classes: The number of attempts per Class of their outcome, as classified through WithClassifier.
+//   - HookFailures: The failures of the hooks, such as the notifiers, in the order they occurred.
+//   - Abandoned: The number of attempts abandoned after the duration set through WithAbandonAfter. Each
+//     of them leaked a goroutine until the operation returns. An attempt left running once the retry
+//     sequence is canceled is not counted.
+type Stats struct {
+	SequenceID   string
+	Attempts     int
+	Abandoned    int
+	TotalDelay   time.Duration
+	Elapsed      time.Duration
+	SLO          time.Duration
+	SLOMet       bool
+	Errors       []error
+	Classes      map[Class]int
+	HookFailures []error
+}
+
+// ErrorRetention determines which errors of the attempts of a retry sequence are retained.
+type ErrorRetention int
+
+const (
+	// ErrorRetentionDefault retains every error of bounded retry sequences, and the last error only of
+	// unbounded ones.
+	ErrorRetentionDefault ErrorRetention = iota
+	// ErrorRetentionLastOnly retains the last error only.
+	ErrorRetentionLastOnly
+	// ErrorRetentionAll retains every error.
+	ErrorRetentionAll
+	// ErrorRetentionSampled retains the errors of the attempts numbered 0 and powers of two, plus the last
+	// error, i.e., a number of errors logarithmic in the number of attempts.
+	ErrorRetentionSampled
+)
+
+// errorRetainer retains the errors of the attempts of a retry sequence according to an ErrorRetention mode.
+type errorRetainer struct {
+	retention ErrorRetention
+	errors    []error
+	last      error
+	retained  bool
+}
+
+// newErrorRetainer returns an errorRetainer, resolving ErrorRetentionDefault according to whether the
+// retry sequence is bounded.
+//
+// Parameters:
+//   - retention: The ErrorRetention mode.
+//   - bounded:   Whether the retry sequence has a maximum number of attempts.
+//
+// Returns:
+//   - retainer: The errorRetainer.
+func newErrorRetainer(retention ErrorRetention, bounded bool) (retainer *errorRetainer) {
+	if retention == ErrorRetentionDefault {
+		retention = ErrorRetentionLastOnly
+
+		if bounded {
+			retention = ErrorRetentionAll
+		}
+	}
+
+	retainer = &errorRetainer{retention: retention}
+
+	return
+}
+
+// add records the error of a failed attempt.
+//
+// Parameters:
+//   - attempt: The zero-based number of the failed attempt.
+//   - err:     The error of the attempt.
+func (r *errorRetainer) add(attempt int, err error) {
+	r.last, r.retained = err, false
+
+	switch r.retention {
+	case ErrorRetentionAll:
+		r.errors, r.retained = append(r.errors, err), true
+	case ErrorRetentionSampled:
+		if attempt&(attempt-1) == 0 {
+			r.errors, r.retained = append(r.errors, err), true
+		}
+	}
+}
+
+// retainedErrors returns the retained errors, in attempt order.
+//
+// Returns:
+//   - errs: The retained errors.
+func (r *errorRetainer) retainedErrors() (errs []error) {
+	errs = r.errors
+
+	if r.last != nil && !r.retained {
+		errs = append(errs, r.last)
+	}
+
+	return
+}
+
+// fitSLO adjusts the delay before the next attempt so that the attempt can still finish within the
+// SLO, based on the average duration of the attempts executed so far.
+//
+// Parameters:
+//   - slo:      The SLO target of the retry sequence.
+//   - elapsed:  The time elapsed since the retry sequence started.
+//   - estimate: The estimated duration of the next attempt.
+//   - delay:    The delay computed by the backoff strategy.
+//
+// Returns:
+//   - fitted: The delay shrunk to leave room for the next attempt within the SLO.
+//   - ok:     Whether another attempt can finish within the SLO at all.
+func fitSLO(slo, elapsed, estimate, delay time.Duration) (fitted time.Duration, ok bool) {
+	remaining := slo - elapsed - estimate
+	if remaining <= 0 {
+		return
+	}
+
+	fitted, ok = min(delay, remaining), true
+
+	return
+}
@@ -0,0 +1,90 @@
+package retrier
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+)
+
+// ErrSuperseded is the cause of cancellation of a retry sequence superseded by a newer one started
+// with the same key through WithSupersede. It wraps context.Canceled.
+var ErrSuperseded = fmt.Errorf("retry sequence superseded: %w", context.Canceled)
+
+// supersession identifies the retry sequence currently registered for a key.
+type supersession struct {
+	cancel context.CancelCauseFunc
+}
+
+// supersessions maps supersession keys to the retry sequence currently registered for them.
+var supersessions = struct {
+	mutex     sync.Mutex
+	sequences map[string]*supersession
+}{
+	sequences: make(map[string]*supersession),
+}
+
+// supersede registers a new retry sequence for the key derived from ctx, cancelling the sequence
+// previously registered for the same key with ErrSuperseded.
+//
+// Parameters:
+//   - ctx: The context of the new retry sequence.
+//   - key: The function deriving the supersession key from ctx. An empty key disables supersession.
+//
+// Returns:
+//   - sequenceCtx: The context of the new retry sequence, cancelled if it is superseded in turn.
+//   - release:     The function to call once the retry sequence ends.
+func supersede(ctx context.Context, key func(ctx context.Context) string) (sequenceCtx context.Context, release func()) {
+	k := key(ctx)
+	if k == "" {
+		return ctx, func() {}
+	}
+
+	sequenceCtx, cancel := context.WithCancelCause(ctx)
+
+	current := &supersession{cancel: cancel}
+
+	supersessions.mutex.Lock()
+
+	if previous, ok := supersessions.sequences[k]; ok {
+		previous.cancel(ErrSuperseded)
+	}
+
+	supersessions.sequences[k] = current
+
+	supersessions.mutex.Unlock()
+
+	release = func() {
+		supersessions.mutex.Lock()
+
+		if supersessions.sequences[k] == current {
+			delete(supersessions.sequences, k)
+		}
+
+		supersessions.mutex.Unlock()
+
+		cancel(nil)
+	}
+
+	return
+}
+
+// contextError returns the error explaining why ctx is done, reporting ErrSuperseded rather than
+// context.Canceled for superseded retry sequences.
+//
+// Parameters:
+//   - ctx: The context that is done.
+//
+// Returns:
+//   - err: The context's error, or ErrSuperseded.
+func contextError(ctx context.Context) (err error) {
+	if cause := context.Cause(ctx); errors.Is(cause, ErrSuperseded) {
+		err = cause
+
+		return
+	}
+
+	err = ctx.Err()
+
+	return
+}
@@ -0,0 +1,102 @@
+package retrier
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ErrNotReady is wrapped by the error of WaitAll when some dependencies did not become ready.
+var ErrNotReady = errors.New("dependencies not ready")
+
+// Readiness reports how the readiness check of a dependency went in WaitAll.
+//
+// Fields:
+//   - Ready:    Whether the dependency became ready.
+//   - Attempts: The number of attempts of the check.
+//   - Elapsed:  The time the check took, until it succeeded or gave up.
+//   - Err:      The error of the last attempt, or the context's error, if the dependency did not become ready.
+type Readiness struct {
+	Ready    bool
+	Attempts int
+	Elapsed  time.Duration
+	Err      error
+}
+
+// WaitAll retries the readiness checks of several dependencies, e.g., a database, a cache, and a
+// broker at startup, concurrently and each with its own backoff, and waits for every check to succeed
+// or give up.
+//
+// Parameters:
+//   - ctx:    A context to control the lifetime of the checks. Cancelling it stops every check.
+//   - checks: The readiness checks, keyed by the name of their dependency.
+//   - opts:   Optional configuration options applied to the retry sequence of every check.
+//
+// Returns:
+//   - report: The Readiness of every dependency, keyed by its name.
+//   - err:    nil if every dependency became ready, or an error wrapping ErrNotReady and naming the
+//     dependencies that did not, with their errors.
+//
+// Example:
+//
+//	report, err := retrier.WaitAll(ctx, map[string]retrier.Operation{
+//	    "postgres": db.Ping,
+//	    "redis":    cache.Ping,
+//	}, retrier.WithMaxRetries(-1))
+func WaitAll(ctx context.Context, checks map[string]Operation, opts ...Option) (report map[string]Readiness, err error) {
+	report = make(map[string]Readiness, len(checks))
+
+	var (
+		mutex sync.Mutex
+		wg    sync.WaitGroup
+	)
+
+	for name, check := range checks {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			var stats Stats
+
+			checkErr := Retry(ctx, check, append(opts[:len(opts):len(opts)], WithStats(&stats))...)
+
+			mutex.Lock()
+			defer mutex.Unlock()
+
+			report[name] = Readiness{
+				Ready:    checkErr == nil,
+				Attempts: stats.Attempts,
+				Elapsed:  stats.Elapsed,
+				Err:      checkErr,
+			}
+		}()
+	}
+
+	wg.Wait()
+
+	var failures []error
+
+	for name, readiness := range report {
+		if !readiness.Ready {
+			failures = append(failures, fmt.Errorf("%s: %w", name, readiness.Err))
+		}
+	}
+
+	if len(failures) == 0 {
+		return
+	}
+
+	// Sort the failures, collected in map order, so that the error reads the same across runs.
+	slices.SortFunc(failures, func(a, b error) int {
+		return strings.Compare(a.Error(), b.Error())
+	})
+
+	err = fmt.Errorf("%w: %w", ErrNotReady, errors.Join(failures...))
+
+	return
+}